@@ -0,0 +1,152 @@
+package activity
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371000
+
+// movingSpeed is the speed in m/s below which a track counts as stopped
+const movingSpeed = 0.5
+
+// Point is a single recorded position
+type Point struct {
+	Lat       float64 // degrees
+	Lon       float64 // degrees
+	Elevation float64 // meters
+	Time      time.Time
+}
+
+// Activity is a recorded run, walk, ride or other workout
+type Activity struct {
+	Name     string
+	Sport    string    // e.g. "running", as written by the device or app
+	Segments [][]Point // the track, split where recording was paused
+
+	// Totals recorded by the device, if the file has them
+	Start     time.Time
+	Distance  float64       // meters
+	Elapsed   time.Duration // start to finish
+	TimerTime time.Duration // time the timer was running
+}
+
+// Stats summarizes an activity
+type Stats struct {
+	Start    time.Time
+	End      time.Time
+	Distance float64 // kilometers
+	Moving   time.Duration
+}
+
+// ReadFile reads a GPX or FIT file, depending on its extension
+func ReadFile(path string) (Activity, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Activity{}, fmt.Errorf("failed to open activity: %w", err)
+	}
+	defer file.Close()
+
+	var activity Activity
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".gpx":
+		activity, err = ReadGPX(file)
+	case ".fit":
+		activity, err = ReadFIT(file)
+	default:
+		return Activity{}, fmt.Errorf("unsupported activity file: %s (use .gpx or .fit)", filepath.Base(path))
+	}
+	if err != nil {
+		return Activity{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return activity, nil
+}
+
+// Summarize computes the distance and moving time of an activity. Totals
+// recorded by the device (distance, timer time, start and elapsed time) are
+// preferred, since they're measured more precisely than the track; the track
+// fills in whatever the file doesn't have.
+func Summarize(activity Activity) Stats {
+	var stats Stats
+	var distance float64
+	for _, segment := range activity.Segments {
+		for i, point := range segment {
+			if !point.Time.IsZero() {
+				if stats.Start.IsZero() || point.Time.Before(stats.Start) {
+					stats.Start = point.Time
+				}
+				if point.Time.After(stats.End) {
+					stats.End = point.Time
+				}
+			}
+			if i == 0 {
+				continue
+			}
+
+			previous := segment[i-1]
+			meters := haversine(previous, point)
+			distance += meters
+
+			elapsed := point.Time.Sub(previous.Time)
+			if !previous.Time.IsZero() && elapsed > 0 && meters/elapsed.Seconds() >= movingSpeed {
+				stats.Moving += elapsed
+			}
+		}
+	}
+	stats.Distance = distance / 1000
+
+	if activity.Distance > 0 {
+		stats.Distance = activity.Distance / 1000
+	}
+	if activity.TimerTime > 0 {
+		stats.Moving = activity.TimerTime
+	}
+	if !activity.Start.IsZero() {
+		stats.Start = activity.Start
+	}
+	if activity.Elapsed > 0 {
+		stats.End = stats.Start.Add(activity.Elapsed)
+	}
+	if stats.Moving == 0 && stats.End.After(stats.Start) {
+		stats.Moving = stats.End.Sub(stats.Start)
+	}
+	return stats
+}
+
+// Pace returns the moving time per kilometer
+func (s Stats) Pace() time.Duration {
+	if s.Distance <= 0 {
+		return 0
+	}
+	return time.Duration(float64(s.Moving) / s.Distance)
+}
+
+// Speed returns the moving speed in km/h
+func (s Stats) Speed() float64 {
+	if s.Moving <= 0 {
+		return 0
+	}
+	return s.Distance / s.Moving.Hours()
+}
+
+// FormatPace formats a pace like "5:32 /km"
+func FormatPace(pace time.Duration) string {
+	seconds := int(pace.Round(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d /km", seconds/60, seconds%60)
+}
+
+// haversine returns the distance between two points in meters
+func haversine(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
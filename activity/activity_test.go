@@ -0,0 +1,164 @@
+package activity
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// degree is the length of a degree of latitude in meters
+const degree = earthRadius * math.Pi / 180
+
+func TestReadGPX(t *testing.T) {
+	activity, err := ReadFile(filepath.Join("testdata", "run.gpx"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if activity.Name != "Morning Run" || activity.Sport != "running" {
+		t.Errorf("got %q (%s), want Morning Run (running)", activity.Name, activity.Sport)
+	}
+	if len(activity.Segments) != 2 || len(activity.Segments[0]) != 6 || len(activity.Segments[1]) != 3 {
+		t.Fatalf("got segments of %d points, want 6 and 3", pointCounts(activity))
+	}
+	if first := activity.Segments[0][0]; first.Lat != 47 || first.Lon != 8.5 || first.Elevation != 410 {
+		t.Errorf("first point %+v, want 47, 8.5 at 410 m", first)
+	}
+
+	// The wait at the crossing and the pause between segments aren't moving time
+	start := time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC)
+	stats := Summarize(activity)
+	want := Stats{Start: start, End: start.Add(6 * time.Minute), Distance: 6 * degree / 1000 * 0.001, Moving: 3 * time.Minute}
+	checkStats(t, stats, want)
+	if pace := FormatPace(stats.Pace()); pace != "4:30 /km" {
+		t.Errorf("pace %s, want 4:30 /km", pace)
+	}
+	if speed := stats.Speed(); math.Abs(speed-13.34) > 0.01 {
+		t.Errorf("speed %.2f km/h, want 13.34", speed)
+	}
+}
+
+func TestReadGPXErrors(t *testing.T) {
+	tests := map[string]string{
+		"not XML":       "run",
+		"no track":      `<gpx><trk><trkseg><trkpt lat="47" lon="8.5"><time>2026-10-17T07:00:00Z</time></trkpt></trkseg></trk></gpx>`,
+		"no timestamps": `<gpx><trk><trkseg><trkpt lat="47" lon="8.5"/><trkpt lat="47.001" lon="8.5"/></trkseg></trk></gpx>`,
+	}
+	for name, gpx := range tests {
+		if _, err := ReadGPX(bytes.NewBufferString(gpx)); err == nil {
+			t.Errorf("%s: read without an error", name)
+		}
+	}
+}
+
+func TestReadFIT(t *testing.T) {
+	activity, err := ReadFile(filepath.Join("testdata", "run.fit"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	start := fitTime(1108000000)
+	if activity.Sport != "running" || !activity.Start.Equal(start) {
+		t.Errorf("got a %s at %v, want a run at %v", activity.Sport, activity.Start, start)
+	}
+	if activity.Distance != 334 || activity.Elapsed != 100*time.Second || activity.TimerTime != 95*time.Second {
+		t.Errorf("got totals %.0f m, %s, %s, want 334 m, 1m40s, 1m35s", activity.Distance, activity.Elapsed, activity.TimerTime)
+	}
+
+	// The point without a position is skipped; the last one has a compressed timestamp
+	if len(activity.Segments) != 1 || len(activity.Segments[0]) != 4 {
+		t.Fatalf("got segments of %d points, want 4", pointCounts(activity))
+	}
+	for i, point := range activity.Segments[0] {
+		lat := 47 + 0.001*float64(i)
+		at := start.Add(time.Duration(30*i) * time.Second)
+		if math.Abs(point.Lat-lat) > 1e-6 || math.Abs(point.Lon-8.5) > 1e-6 || point.Elevation != float64(410+i) || !point.Time.Equal(at) {
+			t.Errorf("point %d: got %+v, want %.3f, 8.5 at %d m at %v", i, point, lat, 410+i, at)
+		}
+	}
+
+	// The device's totals win over the track
+	checkStats(t, Summarize(activity), Stats{Start: start, End: start.Add(100 * time.Second), Distance: 0.334, Moving: 95 * time.Second})
+}
+
+func TestReadFITErrors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "run.fit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A data message of a local type that wasn't defined
+	undefined := append(append([]byte{}, data[:4]...), 1, 0, 0, 0)
+	undefined = append(append(undefined, data[8:14]...), 0x05)
+	tests := map[string][]byte{
+		"not a FIT file":    []byte("<gpx></gpx>"),
+		"truncated":         data[:len(data)-40],
+		"undefined message": undefined,
+	}
+	for name, data := range tests {
+		if _, err := ReadFIT(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: read without an error", name)
+		}
+	}
+}
+
+func TestSummarizeTotals(t *testing.T) {
+	start := time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC)
+	track := [][]Point{{
+		{Lat: 47, Lon: 8.5, Time: start},
+		{Lat: 47.01, Lon: 8.5, Time: start.Add(5 * time.Minute)},
+	}}
+	tests := []struct {
+		name     string
+		activity Activity
+		want     Stats
+	}{
+		{"track only", Activity{Segments: track},
+			Stats{Start: start, End: start.Add(5 * time.Minute), Distance: 10 * degree / 1000 * 0.001, Moving: 5 * time.Minute}},
+		{"timer time", Activity{Segments: track, TimerTime: 4 * time.Minute},
+			Stats{Start: start, End: start.Add(5 * time.Minute), Distance: 10 * degree / 1000 * 0.001, Moving: 4 * time.Minute}},
+		{"totals only", Activity{Start: start, Distance: 5000, Elapsed: 30 * time.Minute},
+			Stats{Start: start, End: start.Add(30 * time.Minute), Distance: 5, Moving: 30 * time.Minute}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkStats(t, Summarize(test.activity), test.want)
+		})
+	}
+}
+
+func TestFormatPace(t *testing.T) {
+	tests := map[time.Duration]string{
+		5*time.Minute + 32*time.Second:       "5:32 /km",
+		4*time.Minute + 59*time.Second + 6e8: "5:00 /km",
+		12 * time.Minute:                     "12:00 /km",
+	}
+	for pace, want := range tests {
+		if got := FormatPace(pace); got != want {
+			t.Errorf("FormatPace(%s) = %s, want %s", pace, got, want)
+		}
+	}
+	if pace := (Stats{Moving: time.Hour}).Pace(); pace != 0 {
+		t.Errorf("pace without a distance = %s, want 0", pace)
+	}
+}
+
+// checkStats compares stats, with distances in meters
+func checkStats(t *testing.T, got, want Stats) {
+	t.Helper()
+	if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+		t.Errorf("got %v-%v, want %v-%v", got.Start, got.End, want.Start, want.End)
+	}
+	if math.Abs(got.Distance-want.Distance) > 0.001 || got.Moving != want.Moving {
+		t.Errorf("got %.3f km in %s, want %.3f km in %s", got.Distance, got.Moving, want.Distance, want.Moving)
+	}
+}
+
+// pointCounts returns the number of points of each segment
+func pointCounts(activity Activity) []int {
+	var counts []int
+	for _, segment := range activity.Segments {
+		counts = append(counts, len(segment))
+	}
+	return counts
+}
@@ -0,0 +1,241 @@
+package activity
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FIT global message and field numbers used here
+const (
+	fitMessageSession = 18
+	fitMessageRecord  = 20
+
+	fitFieldTimestamp = 253
+
+	fitRecordLat              = 0
+	fitRecordLon              = 1
+	fitRecordAltitude         = 2
+	fitRecordEnhancedAltitude = 78
+
+	fitSessionStartTime = 2
+	fitSessionSport     = 5
+	fitSessionElapsed   = 7
+	fitSessionTimer     = 8
+	fitSessionDistance  = 9
+)
+
+// fitEpoch is the start of FIT timestamps
+var fitEpoch = time.Date(1989, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// fitSports names the FIT sport values that map to habits
+var fitSports = map[uint64]string{
+	1:  "running",
+	2:  "cycling",
+	5:  "swimming",
+	11: "walking",
+	17: "hiking",
+}
+
+// fitField is a field of a FIT definition message
+type fitField struct {
+	num  byte
+	size int
+}
+
+// fitDefinition describes the data messages of a local message type
+type fitDefinition struct {
+	global    uint16
+	order     binary.ByteOrder
+	fields    []fitField
+	extraSize int // developer fields, which are skipped
+}
+
+// ReadFIT reads the track and session totals of a FIT activity file, as
+// written by Garmin, Wahoo, Coros and most other devices. Only the messages
+// needed for distance and time are decoded; everything else is skipped.
+func ReadFIT(r io.Reader) (Activity, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Activity{}, fmt.Errorf("failed to read FIT file: %w", err)
+	}
+	if len(data) < 12 || (data[0] != 12 && data[0] != 14) || !bytes.Equal(data[8:12], []byte(".FIT")) {
+		return Activity{}, fmt.Errorf("not a FIT file")
+	}
+
+	headerSize := int(data[0])
+	end := headerSize + int(binary.LittleEndian.Uint32(data[4:8]))
+	if end > len(data) {
+		return Activity{}, fmt.Errorf("truncated FIT file")
+	}
+
+	var activity Activity
+	var segment []Point
+	definitions := make(map[byte]*fitDefinition)
+	var lastTimestamp uint32
+
+	for pos := headerSize; pos < end; {
+		header := data[pos]
+		pos++
+
+		var local byte
+		var timestamp uint32
+		switch {
+		case header&0x80 != 0:
+			// Compressed timestamp header: a data message with a 5 bit time offset
+			local = (header >> 5) & 0x03
+			offset := uint32(header & 0x1F)
+			timestamp = lastTimestamp&^0x1F + offset
+			if offset < lastTimestamp&0x1F {
+				timestamp += 0x20
+			}
+			lastTimestamp = timestamp
+		case header&0x40 != 0:
+			definition, size, err := readFITDefinition(data[pos:end], header&0x20 != 0)
+			if err != nil {
+				return Activity{}, err
+			}
+			definitions[header&0x0F] = definition
+			pos += size
+			continue
+		default:
+			local = header & 0x0F
+		}
+
+		definition, exists := definitions[local]
+		if !exists {
+			return Activity{}, fmt.Errorf("invalid FIT file: data message without definition")
+		}
+
+		values := make(map[byte]uint64)
+		for _, field := range definition.fields {
+			if pos+field.size > end {
+				return Activity{}, fmt.Errorf("truncated FIT file")
+			}
+			if value, ok := fitValue(data[pos:pos+field.size], definition.order); ok {
+				values[field.num] = value
+			}
+			pos += field.size
+		}
+		pos += definition.extraSize
+		if pos > end {
+			return Activity{}, fmt.Errorf("truncated FIT file")
+		}
+
+		if value, ok := values[fitFieldTimestamp]; ok {
+			timestamp = uint32(value)
+			lastTimestamp = timestamp
+		}
+
+		switch definition.global {
+		case fitMessageRecord:
+			point, ok := fitPoint(values, timestamp)
+			if ok {
+				segment = append(segment, point)
+			}
+		case fitMessageSession:
+			if sport, ok := fitSports[values[fitSessionSport]]; ok {
+				activity.Sport = sport
+			}
+			if value, ok := values[fitSessionStartTime]; ok && activity.Start.IsZero() {
+				activity.Start = fitTime(uint32(value))
+			}
+			activity.Distance += float64(values[fitSessionDistance]) / 100
+			activity.Elapsed += time.Duration(values[fitSessionElapsed]) * time.Millisecond
+			activity.TimerTime += time.Duration(values[fitSessionTimer]) * time.Millisecond
+		}
+	}
+
+	if len(segment) > 0 {
+		activity.Segments = append(activity.Segments, segment)
+	}
+	if len(segment) < 2 && activity.TimerTime == 0 {
+		return Activity{}, fmt.Errorf("no track or session found")
+	}
+	return activity, nil
+}
+
+// readFITDefinition reads a definition message, returning it and its size
+func readFITDefinition(data []byte, developerFields bool) (*fitDefinition, int, error) {
+	if len(data) < 5 {
+		return nil, 0, fmt.Errorf("truncated FIT file")
+	}
+
+	definition := &fitDefinition{order: binary.LittleEndian}
+	if data[1] == 1 {
+		definition.order = binary.BigEndian
+	}
+	definition.global = definition.order.Uint16(data[2:4])
+
+	count := int(data[4])
+	pos := 5
+	if len(data) < pos+count*3 {
+		return nil, 0, fmt.Errorf("truncated FIT file")
+	}
+	for i := 0; i < count; i++ {
+		definition.fields = append(definition.fields, fitField{num: data[pos], size: int(data[pos+1])})
+		pos += 3
+	}
+
+	if developerFields {
+		if len(data) < pos+1 {
+			return nil, 0, fmt.Errorf("truncated FIT file")
+		}
+		count := int(data[pos])
+		pos++
+		if len(data) < pos+count*3 {
+			return nil, 0, fmt.Errorf("truncated FIT file")
+		}
+		for i := 0; i < count; i++ {
+			definition.extraSize += int(data[pos+1])
+			pos += 3
+		}
+	}
+	return definition, pos, nil
+}
+
+// fitValue decodes an unsigned integer field. Fields of other sizes (strings,
+// arrays) and fields holding the "invalid" value of their size are skipped.
+func fitValue(data []byte, order binary.ByteOrder) (uint64, bool) {
+	switch len(data) {
+	case 1:
+		return uint64(data[0]), data[0] != 0xFF
+	case 2:
+		value := order.Uint16(data)
+		return uint64(value), value != 0xFFFF
+	case 4:
+		value := order.Uint32(data)
+		return uint64(value), value != 0xFFFFFFFF && value != 0x7FFFFFFF
+	default:
+		return 0, false
+	}
+}
+
+// fitPoint converts a record message to a point
+func fitPoint(values map[byte]uint64, timestamp uint32) (Point, bool) {
+	lat, hasLat := values[fitRecordLat]
+	lon, hasLon := values[fitRecordLon]
+	if !hasLat || !hasLon || timestamp == 0 {
+		return Point{}, false
+	}
+
+	// Positions are stored in semicircles, 2^31 of which make 180 degrees
+	point := Point{
+		Lat:  float64(int32(uint32(lat))) * 180 / (1 << 31),
+		Lon:  float64(int32(uint32(lon))) * 180 / (1 << 31),
+		Time: fitTime(timestamp),
+	}
+	if altitude, ok := values[fitRecordEnhancedAltitude]; ok {
+		point.Elevation = float64(altitude)/5 - 500
+	} else if altitude, ok := values[fitRecordAltitude]; ok {
+		point.Elevation = float64(altitude)/5 - 500
+	}
+	return point, true
+}
+
+// fitTime converts a FIT timestamp to a time
+func fitTime(timestamp uint32) time.Time {
+	return fitEpoch.Add(time.Duration(timestamp) * time.Second)
+}
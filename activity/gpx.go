@@ -0,0 +1,66 @@
+package activity
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// gpxFile is the part of a GPX document holding tracks
+type gpxFile struct {
+	Metadata struct {
+		Name string `xml:"name"`
+	} `xml:"metadata"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []struct {
+				Lat       float64   `xml:"lat,attr"`
+				Lon       float64   `xml:"lon,attr"`
+				Elevation float64   `xml:"ele"`
+				Time      time.Time `xml:"time"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// ReadGPX reads the tracks of a GPX file, as exported by Strava, Garmin
+// Connect and most other apps. All tracks of the file form one activity.
+func ReadGPX(r io.Reader) (Activity, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return Activity{}, fmt.Errorf("invalid GPX file: %w", err)
+	}
+
+	activity := Activity{Name: strings.TrimSpace(file.Metadata.Name)}
+	points := 0
+	for _, track := range file.Tracks {
+		if activity.Name == "" {
+			activity.Name = strings.TrimSpace(track.Name)
+		}
+		if activity.Sport == "" {
+			activity.Sport = strings.TrimSpace(track.Type)
+		}
+		for _, segment := range track.Segments {
+			var trackPoints []Point
+			for _, point := range segment.Points {
+				trackPoints = append(trackPoints, Point{Lat: point.Lat, Lon: point.Lon, Elevation: point.Elevation, Time: point.Time})
+			}
+			if len(trackPoints) > 0 {
+				activity.Segments = append(activity.Segments, trackPoints)
+				points += len(trackPoints)
+			}
+		}
+	}
+
+	if points < 2 {
+		return Activity{}, fmt.Errorf("no track found")
+	}
+	if activity.Segments[0][0].Time.IsZero() {
+		return Activity{}, fmt.Errorf("track has no timestamps")
+	}
+	return activity, nil
+}
@@ -0,0 +1,252 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Options controls how a chart is rendered
+type Options struct {
+	Title  string
+	Goal   float64 // daily goal line, 0 for none
+	Unit   string  // suffix for values, e.g. "h" or "x"
+	Color  string  // main color as a CSS color
+	Width  int
+	Height int
+}
+
+// heatmapColors are the fill colors for empty to full heatmap cells
+var heatmapColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// withDefaults fills in unset options
+func (o Options) withDefaults() Options {
+	if o.Width == 0 {
+		o.Width = 640
+	}
+	if o.Height == 0 {
+		o.Height = 180
+	}
+	if o.Color == "" {
+		o.Color = "#40c463"
+	}
+	return o
+}
+
+// BarSVG renders daily totals as an SVG bar chart
+func BarSVG(totals []types.DailyTotal, opts Options) string {
+	opts = opts.withDefaults()
+	const padLeft, padRight, padTop, padBottom = 40, 10, 24, 24
+
+	plotWidth := float64(opts.Width - padLeft - padRight)
+	plotHeight := float64(opts.Height - padTop - padBottom)
+	maxValue := maxTotal(totals, opts.Goal)
+
+	var svg strings.Builder
+	writeHeader(&svg, opts)
+
+	// Axis and max label
+	svg.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#999" stroke-width="1"/>`,
+		padLeft, opts.Height-padBottom, opts.Width-padRight, opts.Height-padBottom))
+	svg.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="10" text-anchor="end" fill="#666">%s</text>`,
+		padLeft-4, padTop+4, html.EscapeString(formatValue(maxValue, opts.Unit))))
+
+	// Bars
+	if len(totals) > 0 {
+		slot := plotWidth / float64(len(totals))
+		barWidth := math.Max(1, slot*0.8)
+		for i, day := range totals {
+			barHeight := day.Value / maxValue * plotHeight
+			x := float64(padLeft) + float64(i)*slot + (slot-barWidth)/2
+			y := float64(opts.Height-padBottom) - barHeight
+			svg.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s</title></rect>`,
+				x, y, barWidth, barHeight, html.EscapeString(opts.Color),
+				day.Date.Format("Jan 2"), html.EscapeString(formatValue(day.Value, opts.Unit))))
+		}
+
+		// First and last date labels
+		svg.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="10" fill="#666">%s</text>`,
+			padLeft, opts.Height-8, totals[0].Date.Format("Jan 2")))
+		svg.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="10" text-anchor="end" fill="#666">%s</text>`,
+			opts.Width-padRight, opts.Height-8, totals[len(totals)-1].Date.Format("Jan 2")))
+	}
+
+	writeGoalLine(&svg, opts, maxValue, padLeft, padRight, padTop, padBottom)
+	svg.WriteString("</svg>")
+	return svg.String()
+}
+
+// LineSVG renders daily totals as an SVG line chart
+func LineSVG(totals []types.DailyTotal, opts Options) string {
+	opts = opts.withDefaults()
+	const padLeft, padRight, padTop, padBottom = 40, 10, 24, 24
+
+	plotWidth := float64(opts.Width - padLeft - padRight)
+	plotHeight := float64(opts.Height - padTop - padBottom)
+	maxValue := maxTotal(totals, opts.Goal)
+
+	var svg strings.Builder
+	writeHeader(&svg, opts)
+
+	// Axis and max label
+	svg.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#999" stroke-width="1"/>`,
+		padLeft, opts.Height-padBottom, opts.Width-padRight, opts.Height-padBottom))
+	svg.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="10" text-anchor="end" fill="#666">%s</text>`,
+		padLeft-4, padTop+4, html.EscapeString(formatValue(maxValue, opts.Unit))))
+
+	// Line and points
+	if len(totals) > 0 {
+		step := 0.0
+		if len(totals) > 1 {
+			step = plotWidth / float64(len(totals)-1)
+		}
+
+		var points []string
+		for i, day := range totals {
+			x, y := linePoint(i, day.Value, step, maxValue, plotHeight, padLeft, opts.Height-padBottom)
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		}
+		svg.WriteString(fmt.Sprintf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"/>`,
+			strings.Join(points, " "), html.EscapeString(opts.Color)))
+		for i, day := range totals {
+			x, y := linePoint(i, day.Value, step, maxValue, plotHeight, padLeft, opts.Height-padBottom)
+			svg.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="2.5" fill="%s"><title>%s: %s</title></circle>`,
+				x, y, html.EscapeString(opts.Color),
+				day.Date.Format("Jan 2"), html.EscapeString(formatValue(day.Value, opts.Unit))))
+		}
+
+		// First and last date labels
+		svg.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="10" fill="#666">%s</text>`,
+			padLeft, opts.Height-8, totals[0].Date.Format("Jan 2")))
+		svg.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="10" text-anchor="end" fill="#666">%s</text>`,
+			opts.Width-padRight, opts.Height-8, totals[len(totals)-1].Date.Format("Jan 2")))
+	}
+
+	writeGoalLine(&svg, opts, maxValue, padLeft, padRight, padTop, padBottom)
+	svg.WriteString("</svg>")
+	return svg.String()
+}
+
+// HeatmapSVG renders daily totals as a calendar heatmap with one column per week
+func HeatmapSVG(totals []types.DailyTotal, opts Options) string {
+	opts = opts.withDefaults()
+	const cell, gap, padLeft, padTop = 12, 2, 28, 24
+
+	if len(totals) == 0 {
+		opts.Width, opts.Height = padLeft, padTop
+		var svg strings.Builder
+		writeHeader(&svg, opts)
+		svg.WriteString("</svg>")
+		return svg.String()
+	}
+
+	firstWeek := parser.WeekStart(totals[0].Date)
+	weeks := parser.DaysInRange(firstWeek, totals[len(totals)-1].Date)/7 + 1
+	opts.Width = padLeft + weeks*(cell+gap)
+	opts.Height = padTop + 7*(cell+gap)
+
+	maxValue := maxTotal(totals, 0)
+
+	var svg strings.Builder
+	writeHeader(&svg, opts)
+
+	// Weekday labels (Monday first)
+	for row, label := range []string{"Mon", "", "Wed", "", "Fri", "", "Sun"} {
+		if label != "" {
+			svg.WriteString(fmt.Sprintf(`<text x="0" y="%d" font-size="9" fill="#666">%s</text>`,
+				padTop+row*(cell+gap)+cell-2, label))
+		}
+	}
+
+	// Cells
+	for _, day := range totals {
+		column := (parser.DaysInRange(firstWeek, day.Date)) / 7
+		row := (int(day.Date.Weekday()) + 6) % 7
+		level := 0
+		if day.Value > 0 {
+			level = 1 + int(math.Min(3, math.Floor(day.Value/maxValue*4)))
+		}
+		svg.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %s</title></rect>`,
+			padLeft+column*(cell+gap), padTop+row*(cell+gap), cell, cell, heatmapColors[level],
+			day.Date.Format("Mon, Jan 2"), html.EscapeString(formatValue(day.Value, opts.Unit))))
+	}
+
+	svg.WriteString("</svg>")
+	return svg.String()
+}
+
+// RenderSVG renders daily totals as an SVG chart of the given type (line, bar or heatmap)
+func RenderSVG(chartType string, totals []types.DailyTotal, opts Options) (string, error) {
+	switch chartType {
+	case "line":
+		return LineSVG(totals, opts), nil
+	case "bar":
+		return BarSVG(totals, opts), nil
+	case "heatmap":
+		return HeatmapSVG(totals, opts), nil
+	default:
+		return "", ValidateType(chartType)
+	}
+}
+
+// ValidateType checks that a chart type is supported
+func ValidateType(chartType string) error {
+	switch chartType {
+	case "line", "bar", "heatmap":
+		return nil
+	default:
+		return fmt.Errorf("invalid chart type: %s (must be 'line', 'bar' or 'heatmap')", chartType)
+	}
+}
+
+// linePoint returns the position of the i-th point of a line chart
+func linePoint(i int, value, step, maxValue, plotHeight float64, left, bottom int) (float64, float64) {
+	return float64(left) + float64(i)*step, float64(bottom) - value/maxValue*plotHeight
+}
+
+// writeHeader writes the opening svg tag and the title
+func writeHeader(svg *strings.Builder, opts Options) {
+	svg.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`,
+		opts.Width, opts.Height, opts.Width, opts.Height))
+	if opts.Title != "" {
+		svg.WriteString(fmt.Sprintf(`<text x="0" y="14" font-size="13" font-weight="bold" fill="#333">%s</text>`,
+			html.EscapeString(opts.Title)))
+	}
+}
+
+// writeGoalLine writes a dashed horizontal line at the daily goal
+func writeGoalLine(svg *strings.Builder, opts Options, maxValue float64, padLeft, padRight, padTop, padBottom int) {
+	if opts.Goal <= 0 {
+		return
+	}
+	plotHeight := float64(opts.Height - padTop - padBottom)
+	y := float64(opts.Height-padBottom) - opts.Goal/maxValue*plotHeight
+	svg.WriteString(fmt.Sprintf(`<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#e36209" stroke-width="1" stroke-dasharray="4 3"><title>Goal: %s</title></line>`,
+		padLeft, y, opts.Width-padRight, y, html.EscapeString(formatValue(opts.Goal, opts.Unit))))
+}
+
+// maxTotal returns the largest daily value (or the goal), never zero
+func maxTotal(totals []types.DailyTotal, goal float64) float64 {
+	maxValue := goal
+	for _, day := range totals {
+		if day.Value > maxValue {
+			maxValue = day.Value
+		}
+	}
+	if maxValue == 0 {
+		maxValue = 1
+	}
+	return maxValue
+}
+
+// formatValue formats a value with its unit
+func formatValue(value float64, unit string) string {
+	if unit == "x" {
+		return fmt.Sprintf("%.0fx", value)
+	}
+	return fmt.Sprintf("%.1f%s", value, unit)
+}
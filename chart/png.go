@@ -0,0 +1,166 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// RenderPNG renders daily totals as a PNG chart of the given type (line, bar or heatmap).
+// Rasterizing is done in pure Go, so text labels and titles are left out.
+func RenderPNG(w io.Writer, chartType string, totals []types.DailyTotal, opts Options) error {
+	opts = opts.withDefaults()
+
+	var img *image.RGBA
+	switch chartType {
+	case "line":
+		img = rasterLine(totals, opts)
+	case "bar":
+		img = rasterBar(totals, opts)
+	case "heatmap":
+		img = rasterHeatmap(totals)
+	default:
+		return ValidateType(chartType)
+	}
+
+	return png.Encode(w, img)
+}
+
+// rasterBar draws a bar chart with the same layout as BarSVG
+func rasterBar(totals []types.DailyTotal, opts Options) *image.RGBA {
+	const padLeft, padRight, padTop, padBottom = 40, 10, 24, 24
+	img := newCanvas(opts.Width, opts.Height)
+
+	plotWidth := float64(opts.Width - padLeft - padRight)
+	plotHeight := float64(opts.Height - padTop - padBottom)
+	maxValue := maxTotal(totals, opts.Goal)
+	fill := parseColor(opts.Color)
+
+	if len(totals) > 0 {
+		slot := plotWidth / float64(len(totals))
+		barWidth := math.Max(1, slot*0.8)
+		for i, day := range totals {
+			barHeight := day.Value / maxValue * plotHeight
+			x := float64(padLeft) + float64(i)*slot + (slot-barWidth)/2
+			y := float64(opts.Height-padBottom) - barHeight
+			fillRect(img, int(x), int(y), int(math.Ceil(barWidth)), int(math.Round(barHeight)), fill)
+		}
+	}
+
+	drawAxis(img, opts, padLeft, padRight, padBottom)
+	drawGoal(img, opts, maxValue, padLeft, padRight, padTop, padBottom)
+	return img
+}
+
+// rasterLine draws a line chart with the same layout as LineSVG
+func rasterLine(totals []types.DailyTotal, opts Options) *image.RGBA {
+	const padLeft, padRight, padTop, padBottom = 40, 10, 24, 24
+	img := newCanvas(opts.Width, opts.Height)
+
+	plotWidth := float64(opts.Width - padLeft - padRight)
+	plotHeight := float64(opts.Height - padTop - padBottom)
+	maxValue := maxTotal(totals, opts.Goal)
+	stroke := parseColor(opts.Color)
+
+	step := 0.0
+	if len(totals) > 1 {
+		step = plotWidth / float64(len(totals)-1)
+	}
+	for i := range totals {
+		x, y := linePoint(i, totals[i].Value, step, maxValue, plotHeight, padLeft, opts.Height-padBottom)
+		fillRect(img, int(x)-2, int(y)-2, 5, 5, stroke)
+		if i > 0 {
+			px, py := linePoint(i-1, totals[i-1].Value, step, maxValue, plotHeight, padLeft, opts.Height-padBottom)
+			drawLine(img, px, py, x, y, stroke)
+		}
+	}
+
+	drawAxis(img, opts, padLeft, padRight, padBottom)
+	drawGoal(img, opts, maxValue, padLeft, padRight, padTop, padBottom)
+	return img
+}
+
+// rasterHeatmap draws a calendar heatmap with the same layout as HeatmapSVG
+func rasterHeatmap(totals []types.DailyTotal) *image.RGBA {
+	const cell, gap, padLeft, padTop = 12, 2, 28, 24
+	if len(totals) == 0 {
+		return newCanvas(padLeft, padTop)
+	}
+
+	firstWeek := parser.WeekStart(totals[0].Date)
+	weeks := parser.DaysInRange(firstWeek, totals[len(totals)-1].Date)/7 + 1
+	img := newCanvas(padLeft+weeks*(cell+gap), padTop+7*(cell+gap))
+
+	maxValue := maxTotal(totals, 0)
+	for _, day := range totals {
+		column := parser.DaysInRange(firstWeek, day.Date) / 7
+		row := (int(day.Date.Weekday()) + 6) % 7
+		level := 0
+		if day.Value > 0 {
+			level = 1 + int(math.Min(3, math.Floor(day.Value/maxValue*4)))
+		}
+		fillRect(img, padLeft+column*(cell+gap), padTop+row*(cell+gap), cell, cell, parseColor(heatmapColors[level]))
+	}
+
+	return img
+}
+
+// newCanvas creates a white image
+func newCanvas(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	return img
+}
+
+// fillRect fills a rectangle, clipped to the image
+func fillRect(img *image.RGBA, x, y, width, height int, c color.Color) {
+	rect := image.Rect(x, y, x+width, y+height).Intersect(img.Bounds())
+	draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawLine draws a 2px wide line between two points
+func drawLine(img *image.RGBA, x1, y1, x2, y2 float64, c color.Color) {
+	steps := int(math.Max(math.Abs(x2-x1), math.Abs(y2-y1)))
+	if steps == 0 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := x1 + (x2-x1)*t
+		y := y1 + (y2-y1)*t
+		fillRect(img, int(x), int(y)-1, 2, 2, c)
+	}
+}
+
+// drawAxis draws the x axis
+func drawAxis(img *image.RGBA, opts Options, padLeft, padRight, padBottom int) {
+	fillRect(img, padLeft, opts.Height-padBottom, opts.Width-padLeft-padRight, 1, parseColor("#999999"))
+}
+
+// drawGoal draws a dashed horizontal line at the daily goal
+func drawGoal(img *image.RGBA, opts Options, maxValue float64, padLeft, padRight, padTop, padBottom int) {
+	if opts.Goal <= 0 {
+		return
+	}
+	plotHeight := float64(opts.Height - padTop - padBottom)
+	y := int(float64(opts.Height-padBottom) - opts.Goal/maxValue*plotHeight)
+	for x := padLeft; x < opts.Width-padRight; x += 7 {
+		fillRect(img, x, y, 4, 1, parseColor("#e36209"))
+	}
+}
+
+// parseColor parses "#rrggbb" colors, falling back to gray
+func parseColor(hex string) color.RGBA {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{0x99, 0x99, 0x99, 0xff}
+	}
+	return color.RGBA{r, g, b, 0xff}
+}
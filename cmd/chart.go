@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/chart"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// NewChartCmd creates the chart command
+func NewChartCmd() *cobra.Command {
+	var chartType string
+	var rangeInput string
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "chart [habit]",
+		Short: "Export a habit chart as SVG or PNG",
+		Long: `Export a habit chart as SVG or PNG.
+
+The chart uses the same daily totals as the summary command. The output
+format follows the file extension (.svg or .png). Without --out, the SVG is
+written to stdout.
+
+Examples:
+  lazytrack chart code --type line --range 90d --out code.svg
+  lazytrack chart water --type bar --range this-month --out water.png
+  lazytrack chart read --type heatmap --range 365d --out read.svg`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChart(args[0], chartType, rangeInput, outPath)
+		},
+	}
+
+	cmd.Flags().StringVarP(&chartType, "type", "t", "bar", "Chart type (line, bar or heatmap)")
+	cmd.Flags().StringVarP(&rangeInput, "range", "r", "30d", "Range to chart")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Output file (.svg or .png)")
+	return cmd
+}
+
+// runChart handles the chart command execution
+func runChart(habitName, chartType, rangeInput, outPath string) error {
+	if err := chart.ValidateType(chartType); err != nil {
+		return err
+	}
+	// Check the format first, so a file isn't created for nothing
+	format := strings.ToLower(filepath.Ext(outPath))
+	if outPath != "" && format != ".svg" && format != ".png" {
+		return fmt.Errorf("unsupported chart format: %s (use .svg or .png)", filepath.Ext(outPath))
+	}
+
+	startDate, endDate, err := parser.ParseRange(rangeInput, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	habit, err := store.GetHabitByName(strings.ToLower(strings.TrimSpace(habitName)))
+	if err != nil {
+		return err
+	}
+
+	logs, err := store.GetLogsByHabit(habit.Name, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %w", err)
+	}
+
+	totals := summary.CalculateDailyTotals(*habit, logs, startDate, endDate)
+	opts := chartOptions(*habit)
+
+	// Write SVG to stdout when no file is given
+	if outPath == "" {
+		svg, err := chart.RenderSVG(chartType, totals, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Println(svg)
+		return nil
+	}
+
+	// Render before writing, so a failed chart doesn't overwrite the file
+	var out bytes.Buffer
+	if format == ".png" {
+		err = chart.RenderPNG(&out, chartType, totals, opts)
+	} else {
+		var svg string
+		svg, err = chart.RenderSVG(chartType, totals, opts)
+		out.WriteString(svg)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write chart: %w", err)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Chart written to %s\n", outPath)
+	return nil
+}
+
+// chartOptions returns the chart options for a habit. A weekly goal is drawn
+// as its daily share, as in HTML reports.
+func chartOptions(habit types.Habit) chart.Options {
+	opts := chart.Options{
+		Title: fmt.Sprintf("%s %s", habit.Emoji, habit.Name),
+		Goal:  summary.PeriodGoal(habit, 1),
+		Unit:  "h",
+	}
+	if habit.GoalType == "count" {
+		opts.Unit = "x"
+	}
+	return opts
+}
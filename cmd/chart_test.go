@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+func TestChartUnsupportedFormatKeepsFile(t *testing.T) {
+	s := newTestStore(t)
+	s.GetOrCreateHabit("code")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(existing, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{existing, filepath.Join(dir, "chart.jpg"), filepath.Join(dir, "chart")} {
+		err := runChart("code", "bar", "7d", path)
+		if err == nil || !strings.Contains(err.Error(), "unsupported chart format") {
+			t.Errorf("runChart(%s) = %v, want an unsupported format error", path, err)
+		}
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep me" {
+		t.Errorf("existing file was overwritten with %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("files were created: %v", entries)
+	}
+
+	// An unknown habit doesn't create the file either
+	if err := runChart("nope", "bar", "7d", filepath.Join(dir, "chart.svg")); err == nil {
+		t.Errorf("runChart of an unknown habit succeeded")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("files were created: %v", entries)
+	}
+
+	for _, name := range []string{"chart.svg", "chart.PNG"} {
+		path := filepath.Join(dir, name)
+		if err := runChart("code", "bar", "7d", path); err != nil {
+			t.Fatalf("runChart(%s): %v", name, err)
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s wasn't written: %v", name, err)
+		}
+	}
+}
+
+func TestChartOptionsGoal(t *testing.T) {
+	tests := []struct {
+		habit types.Habit
+		goal  float64
+		unit  string
+	}{
+		{types.Habit{Name: "code", GoalType: "duration", DailyGoal: 2}, 2, "h"},
+		{types.Habit{Name: "gym", GoalType: "count", WeeklyGoal: 7}, 1, "x"},
+		{types.Habit{Name: "run", GoalType: "duration", WeeklyGoal: 3}, 3.0 / 7, "h"},
+		{types.Habit{Name: "notes", GoalType: "count"}, 0, "x"},
+	}
+	for _, test := range tests {
+		if opts := chartOptions(test.habit); opts.Goal != test.goal || opts.Unit != test.unit {
+			t.Errorf("%s: goal %v%s, want %v%s", test.habit.Name, opts.Goal, opts.Unit, test.goal, test.unit)
+		}
+	}
+}
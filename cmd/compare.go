@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/spf13/cobra"
+)
+
+// NewCompareCmd creates the compare command
+func NewCompareCmd() *cobra.Command {
+	var current string
+	var against string
+
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare habits between two periods",
+		Long: `Compare each habit's total, goal progress and consistency between two periods.
+
+Ranges can be today, yesterday, this-week, last-week, this-month, last-month,
+this-year, a rolling window like 30d or 4w, or dates like 2026-10-01..2026-10-07.
+Without --against, the period right before --range is used.
+
+Examples:
+  lazytrack compare                             # This week vs last week
+  lazytrack compare --range this-month          # This month vs last month
+  lazytrack compare --range 30d                 # Last 30 days vs the 30 before
+  lazytrack compare --range last-week --against 2026-09-01..2026-09-07`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompare(current, against)
+		},
+	}
+
+	cmd.Flags().StringVarP(&current, "range", "r", "this-week", "Period to report on")
+	cmd.Flags().StringVarP(&against, "against", "a", "", "Period to compare against (default: the previous period)")
+	return cmd
+}
+
+// runCompare handles the compare command execution
+func runCompare(current, against string) error {
+	now := time.Now()
+	startDate, endDate, err := parser.ParseRange(current, now)
+	if err != nil {
+		return err
+	}
+
+	previousStart, previousEnd := parser.PreviousRange(startDate, endDate)
+	if against != "" {
+		previousStart, previousEnd, err = parser.ParseRange(against, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Initialize store
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Get all habits
+	habits, err := store.GetAllHabits()
+	if err != nil {
+		return fmt.Errorf("failed to get habits: %w", err)
+	}
+
+	if len(habits) == 0 {
+		displayEmptyState()
+		return nil
+	}
+
+	currentSummary := summary.CalculateSummary(habits, loadLogsByHabit(store, habits, startDate, endDate), startDate, endDate)
+	previousSummary := summary.CalculateSummary(habits, loadLogsByHabit(store, habits, previousStart, previousEnd), previousStart, previousEnd)
+
+	fmt.Println(summary.FormatComparison(summary.ComparePeriods(currentSummary, previousSummary)))
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println(summary.GetMotivationalMessage(currentSummary, &previousSummary))
+
+	return nil
+}
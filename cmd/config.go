@@ -6,8 +6,11 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/dailynote"
+	"github.com/master-wayne7/lazytrack/schedule"
 	"github.com/master-wayne7/lazytrack/store"
 	"github.com/master-wayne7/lazytrack/types"
 	"github.com/spf13/cobra"
@@ -18,8 +21,15 @@ func NewConfigCmd() *cobra.Command {
 	var habitName string
 	var emoji string
 	var goal string
+	var weeklyGoal string
 	var goalType string
 	var defaultDuration string
+	var dailyNote string
+	var dailyNoteTime string
+	var remind string
+	var lateReminder string
+	var quietHours string
+	var dnd string
 
 	cmd := &cobra.Command{
 		Use:   "config",
@@ -30,25 +40,62 @@ Examples:
   lazytrack config                    # Interactive configuration
   lazytrack config --habit code --emoji 💻
   lazytrack config --habit water --goal 8 --type count
-  lazytrack config --habit read --goal 2 --type duration`,
+  lazytrack config --habit read --goal 2 --type duration
+  lazytrack config --habit gym --weekly-goal 3 --type count
+  lazytrack config --habit water --remind "every 2h 09:00-21:00"
+  lazytrack config --habit stretch --remind "18:30 mon-fri"
+  lazytrack config --habit water --remind off
+  lazytrack config --late-reminder 21:30
+  lazytrack config --quiet-hours 22:00-07:00
+  lazytrack config --dnd "12:00-13:00 weekdays; 09:00-12:00 sun"
+  lazytrack config --daily-note "~/vault/Daily/{{date}}.md"
+  lazytrack config --daily-note off
+  lazytrack config --daily-note-time 20:30
+  lazytrack config set notifications.enabled false
+  lazytrack config set habits.water.muted true
+  lazytrack config list`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runConfig(habitName, emoji, goal, goalType, defaultDuration)
+			if dailyNote != "" {
+				return runDailyNoteConfig(dailyNote)
+			}
+			if dailyNoteTime != "" {
+				return runDailyNoteTimeConfig(dailyNoteTime)
+			}
+			if lateReminder != "" {
+				return runLateReminderConfig(lateReminder)
+			}
+			if quietHours != "" {
+				return runQuietWindowsConfig(quietHoursConfigKey, quietHours)
+			}
+			if dnd != "" {
+				return runQuietWindowsConfig(dndConfigKey, dnd)
+			}
+			return runConfig(habitName, emoji, goal, weeklyGoal, goalType, defaultDuration, remind)
 		},
 	}
 
+	cmd.AddCommand(newConfigSetCmd(), newConfigGetCmd(), newConfigListCmd())
+
 	cmd.Flags().StringVarP(&habitName, "habit", "a", "", "Habit name to configure")
 	cmd.Flags().StringVarP(&emoji, "emoji", "e", "", "Emoji for the habit")
 	cmd.Flags().StringVarP(&goal, "goal", "g", "", "Daily goal value")
+	cmd.Flags().StringVarP(&weeklyGoal, "weekly-goal", "w", "", "Weekly goal value, used when there is no daily goal")
 	cmd.Flags().StringVarP(&goalType, "type", "t", "", "Goal type (duration or count)")
 	cmd.Flags().StringVarP(&defaultDuration, "duration", "d", "", "Default duration")
+	cmd.Flags().StringVar(&remind, "remind", "", `Reminder schedule, e.g. "18:30 mon-fri" or "every 2h 09:00-21:00" ("off" to disable)`)
+	cmd.Flags().StringVar(&lateReminder, "late-reminder", "", `Time of the late reminder for pending goals, e.g. "21:30" (default "20:00")`)
+	cmd.Flags().StringVar(&quietHours, "quiet-hours", "", `Hours without reminders, e.g. "22:00-07:00" ("off" to disable)`)
+	cmd.Flags().StringVar(&dnd, "dnd", "", `Do-not-disturb windows, e.g. "12:00-13:00 weekdays; 09:00-12:00 sun" ("off" to disable)`)
+	cmd.Flags().StringVar(&dailyNote, "daily-note", "", `Markdown daily note path, e.g. "~/vault/Daily/{{date}}.md" ("off" to disable)`)
+	cmd.Flags().StringVar(&dailyNoteTime, "daily-note-time", "", `Time the daemon writes the habits table into the daily note, e.g. "20:30" (default "21:00")`)
 
 	return cmd
 }
 
 // runConfig handles the config command execution
-func runConfig(habitName, emoji, goal, goalType, defaultDuration string) error {
+func runConfig(habitName, emoji, goal, weeklyGoal, goalType, defaultDuration, remind string) error {
 	// Initialize store
-	store, err := store.NewStore()
+	store, err := store.NewLockedStore()
 	if err != nil {
 		return fmt.Errorf("failed to initialize store: %w", err)
 	}
@@ -82,6 +129,15 @@ func runConfig(habitName, emoji, goal, goalType, defaultDuration string) error {
 		updated = true
 	}
 
+	if weeklyGoal != "" {
+		goalValue, err := strconv.Atoi(weeklyGoal)
+		if err != nil || goalValue < 0 {
+			return fmt.Errorf("invalid weekly goal value: %s", weeklyGoal)
+		}
+		habit.WeeklyGoal = goalValue
+		updated = true
+	}
+
 	if goalType != "" {
 		if goalType != "duration" && goalType != "count" {
 			return fmt.Errorf("invalid goal type: %s (must be 'duration' or 'count')", goalType)
@@ -95,6 +151,18 @@ func runConfig(habitName, emoji, goal, goalType, defaultDuration string) error {
 		updated = true
 	}
 
+	if remind == "off" {
+		habit.Remind = ""
+		updated = true
+	} else if remind != "" {
+		parsed, err := schedule.Parse(remind)
+		if err != nil {
+			return err
+		}
+		habit.Remind = parsed.String()
+		updated = true
+	}
+
 	if updated {
 		err = store.UpdateHabit(habit)
 		if err != nil {
@@ -111,6 +179,118 @@ func runConfig(habitName, emoji, goal, goalType, defaultDuration string) error {
 	return nil
 }
 
+// runDailyNoteConfig sets or disables the daily note path template
+func runDailyNoteConfig(template string) error {
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	green := color.New(color.FgGreen, color.Bold)
+	if template == "off" {
+		green.Println("✅ Daily note disabled")
+		return store.SetConfig(dailyNoteConfigKey, "")
+	}
+
+	path, err := dailynote.Path(template, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := store.SetConfig(dailyNoteConfigKey, template); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	green.Printf("✅ Logs will be written to your daily note (today: %s)\n", path)
+	return nil
+}
+
+// runDailyNoteTimeConfig sets the time the daemon writes the habits table
+func runDailyNoteTimeConfig(spec string) error {
+	at, err := parseDailyNoteTime(spec)
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.SetConfig(dailyNoteTimeConfigKey, at); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ The daemon will write the habits table into your daily note at %s\n", at)
+	if template, _ := store.GetConfig(dailyNoteConfigKey); template == "" {
+		fmt.Println("💡 Set the note path with: lazytrack config --daily-note \"~/vault/Daily/{{date}}.md\"")
+	}
+	return nil
+}
+
+// runLateReminderConfig sets the time of the late reminder
+func runLateReminderConfig(spec string) error {
+	late, err := schedule.Parse(spec)
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.SetConfig(lateReminderConfigKey, late.String()); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Late reminder set to %s (next: %s)\n", late, late.Next(time.Now()).Format("Mon 15:04"))
+	return nil
+}
+
+// runQuietWindowsConfig sets or disables the quiet hours or do-not-disturb
+// windows, in which reminders are held back until the window ends
+func runQuietWindowsConfig(key, spec string) error {
+	if spec == "off" {
+		spec = ""
+	}
+	windows, err := schedule.ParseWindows(spec)
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	var normalized []string
+	for _, window := range windows {
+		normalized = append(normalized, window.String())
+	}
+	if err := store.SetConfig(key, strings.Join(normalized, "; ")); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	name := "Quiet hours"
+	if key == dndConfigKey {
+		name = "Do not disturb"
+	}
+	green := color.New(color.FgGreen, color.Bold)
+	if len(normalized) == 0 {
+		green.Printf("✅ %s disabled\n", name)
+	} else {
+		green.Printf("✅ %s: %s\n", name, strings.Join(normalized, "; "))
+	}
+	return nil
+}
+
 // runInteractiveConfig runs interactive configuration mode
 func runInteractiveConfig(store *store.Store) error {
 	cyan := color.New(color.FgCyan, color.Bold)
@@ -245,11 +425,23 @@ func displayHabitConfig(habit types.Habit) {
 			fmt.Print(" hours")
 		}
 		fmt.Print(")")
+	} else if habit.WeeklyGoal > 0 {
+		fmt.Printf(" (Goal: %d", habit.WeeklyGoal)
+		if habit.GoalType == "count" {
+			fmt.Print(" times")
+		} else {
+			fmt.Print(" hours")
+		}
+		fmt.Print(" a week)")
 	}
 
 	if habit.DefaultDuration != "" {
 		fmt.Printf(" [Default: %s]", habit.DefaultDuration)
 	}
 
+	if habit.Remind != "" {
+		fmt.Printf(" ⏰ %s", habit.Remind)
+	}
+
 	fmt.Println()
 }
@@ -1,27 +1,93 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/master-wayne7/lazytrack/daemon"
+	"github.com/master-wayne7/lazytrack/dailynote"
 	"github.com/master-wayne7/lazytrack/notification"
+	"github.com/master-wayne7/lazytrack/reminder"
+	"github.com/master-wayne7/lazytrack/schedule"
 	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/summary"
 	"github.com/spf13/cobra"
 )
 
+// Job kinds the daemon runs on a schedule
+const (
+	jobHabitReminder = "habit"
+	jobLateReminder  = "late"
+	jobDailyNote     = "note"
+)
+
+// lateReminderConfigKey is the config key of the late reminder schedule
+const lateReminderConfigKey = "late_reminder"
+
+// defaultLateReminder is when the late reminder is sent unless configured
+const defaultLateReminder = "20:00"
+
+// maxDaemonSleep bounds how long the daemon sleeps before planning again, so
+// config changes and clock jumps (e.g. waking from suspend) are picked up
+const maxDaemonSleep = 15 * time.Minute
+
+// Config keys of the windows in which no reminders are shown
+const (
+	quietHoursConfigKey = "quiet_hours"
+	dndConfigKey        = "dnd"
+)
+
+// daemonJob is a reminder or other task due at a time
+type daemonJob struct {
+	kind    string
+	habit   string    // for habit reminders
+	slot    time.Time // the scheduled time, which reminders are delivered once for
+	at      time.Time // when to run, later than slot in quiet hours
+	snoozed bool      // the reminder at the end of a snooze
+}
+
+// key returns the key the job's delivered slots are stored under
+func (job daemonJob) key() string {
+	return reminder.Key(job.kind, job.habit)
+}
+
 // NewDaemonCmd creates the daemon command for automatic reminders
 func NewDaemonCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "daemon",
-		Short: "Run LazyTrack daemon for automatic late reminders",
-		Long: `Run LazyTrack daemon for automatic late reminders.
+		Short: "Run LazyTrack daemon for reminders",
+		Long: `Run LazyTrack daemon for reminders.
 
-This command runs in the background and automatically shows late reminders after 8 PM.
-It checks every hour for pending goals and shows notifications when appropriate.
+The daemon sends each habit's reminders on its schedule (set with
+'lazytrack config --habit water --remind "every 2h 09:00-21:00"') and a late
+reminder for pending goals at the late-reminder time (20:00 unless set with
+'lazytrack config --late-reminder 21:30'). Between reminders it sleeps until
+the next one is due.
+
+Each reminder is delivered once, even if the daemon is restarted; one missed
+earlier in the day while the daemon wasn't running is sent when it starts.
+Reminders due in quiet hours or do-not-disturb windows are held back until the
+window ends, and snoozed or dismissed habits are left alone (see 'lazytrack
+snooze' and 'lazytrack dismiss').
+
+Only one daemon runs at a time. It logs to daemon.log in the data directory
+(~/.lazytrack), which is rotated at 1 MB, and shuts down cleanly on Ctrl+C or
+SIGTERM.
 
 Examples:
   lazytrack daemon              # Run daemon in foreground
-  lazytrack daemon --background # Run daemon in background`,
+  lazytrack daemon --background # Run daemon in background
+  lazytrack daemon status       # Check if the daemon is running
+  lazytrack daemon logs -f      # Follow the daemon's log
+  lazytrack daemon restart      # Restart it, e.g. after an update
+  lazytrack daemon stop`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			background, _ := cmd.Flags().GetBool("background")
 			return runDaemon(background)
@@ -29,49 +95,373 @@ Examples:
 	}
 
 	cmd.Flags().BoolP("background", "b", false, "Run daemon in background")
+	cmd.AddCommand(newDaemonStatusCmd(), newDaemonStopCmd(), newDaemonRestartCmd(), newDaemonLogsCmd(), newDaemonInstallCmd(), newDaemonUninstallCmd())
 	return cmd
 }
 
 // runDaemon handles the daemon command execution
 func runDaemon(background bool) error {
+	paths, err := getDaemonPaths()
+	if err != nil {
+		return err
+	}
+
 	if background {
-		// For now, just run in foreground
-		// In the future, this could fork to background
-		fmt.Println("🔄 Starting LazyTrack daemon...")
-		fmt.Println("📅 Will check for late reminders after 8 PM")
-		fmt.Println("⏰ Checking every hour for pending goals")
-		fmt.Println("💡 Press Ctrl+C to stop the daemon")
+		return startDaemonBackground(paths)
 	}
 
-	// Check interval (1 hour)
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
+	pidFile, err := daemon.Acquire(paths.pid)
+	if errors.Is(err, daemon.ErrRunning) {
+		return fmt.Errorf("%w (stop it with: lazytrack daemon stop)", err)
+	}
+	if err != nil {
+		return err
+	}
+	defer pidFile.Release()
 
-	// Check immediately on startup
-	if err := checkAndShowLateReminder(); err != nil {
-		fmt.Printf("⚠️  Error checking reminders: %v\n", err)
+	logFile, err := daemon.OpenLog(paths.log, daemon.DefaultMaxLogSize, daemon.DefaultLogBackups)
+	if err != nil {
+		return err
 	}
+	defer logFile.Close()
+	logger := slog.New(slog.NewJSONHandler(logFile, nil))
+
+	// Stop on Ctrl+C or SIGTERM (e.g. from 'lazytrack daemon stop')
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	logger.Info("daemon started", "pid", os.Getpid())
 	fmt.Println("✅ Daemon started successfully!")
+	fmt.Println("💡 Press Ctrl+C to stop the daemon")
 
-	// Run the daemon loop
+	runDaemonLoop(ctx, logger)
+
+	logger.Info("daemon stopped", "reason", context.Cause(ctx).Error())
+	fmt.Println("\n👋 Daemon stopped")
+	return nil
+}
+
+// runDaemonLoop runs jobs as they become due until ctx is done
+func runDaemonLoop(ctx context.Context, logger *slog.Logger) {
+	var announced time.Time
 	for {
+		jobs, err := planDaemonJobs(time.Now(), logger)
+		if err != nil {
+			logger.Error("failed to plan reminders", "error", err)
+			fmt.Printf("⚠️  Error planning reminders: %v\n", err)
+		}
+
+		var next time.Time
+		for _, job := range jobs {
+			if next.IsZero() || job.at.Before(next) {
+				next = job.at
+			}
+		}
+		if !next.IsZero() && !next.Equal(announced) && next.After(time.Now()) {
+			logger.Info("next reminder", "at", next)
+			fmt.Printf("⏰ Next reminder at %s\n", next.Format("Mon 15:04"))
+			announced = next
+		}
+
+		// Sleep until the next job, but plan again at least every maxDaemonSleep
+		sleep := maxDaemonSleep
+		if !next.IsZero() && time.Until(next) < sleep {
+			sleep = time.Until(next)
+		}
+		timer := time.NewTimer(sleep)
 		select {
-		case <-ticker.C:
-			if err := checkAndShowLateReminder(); err != nil {
-				fmt.Printf("⚠️  Error checking reminders: %v\n", err)
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		var done []daemonJob
+		for _, job := range jobs {
+			if job.at.After(now) {
+				continue
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			// A failed job isn't retried, so a broken backend can't repeat
+			// the reminder on the others
+			done = append(done, job)
+			if reason := daemonJobSkipped(job); reason != "" {
+				logger.Info("job skipped", "kind", job.kind, "habit", job.habit, "slot", job.slot, "reason", reason)
+				continue
+			}
+			err := runDaemonJob(ctx, job, now)
+			if ctx.Err() != nil {
+				// Shutting down cut the job short, so it's run again on the next start
+				done = done[:len(done)-1]
+				logger.Info("job interrupted", "kind", job.kind, "habit", job.habit, "slot", job.slot)
+				break
+			}
+			if failures := notification.BackendErrors(err); len(failures) > 0 {
+				for _, failure := range failures {
+					logger.Error("notification failed", "kind", job.kind, "habit", job.habit, "slot", job.slot, "backend", failure.Backend, "error", failure.Err)
+				}
+				printNotificationErrors(job.kind+" reminder", err)
+				continue
+			}
+			if err != nil {
+				logger.Error("job failed", "kind", job.kind, "habit", job.habit, "slot", job.slot, "error", err)
+				fmt.Printf("⚠️  Error running %s reminder: %v\n", job.kind, err)
+				continue
 			}
+			logger.Info("job done", "kind", job.kind, "habit", job.habit, "slot", job.slot)
+		}
+		if err := markDaemonJobsDone(done, now); err != nil {
+			logger.Error("failed to save reminder state", "error", err)
+			fmt.Printf("⚠️  Error saving reminder state: %v\n", err)
 		}
 	}
 }
 
-// checkAndShowLateReminder checks if it's late and shows reminders
-func checkAndShowLateReminder() error {
-	// Check if it's time to show late reminder
-	if !notification.ShouldShowLateReminder() {
-		return nil // Not late enough yet
+// planDaemonJobs returns the next slot of every scheduled job after now, or
+// today's last slot if it wasn't delivered yet
+func planDaemonJobs(now time.Time, logger *slog.Logger) ([]daemonJob, error) {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	state := reminder.NewState()
+	if err := store.LoadState(reminder.StateName, state); err != nil {
+		return nil, err
+	}
+	quiet, err := quietWindows(store)
+	if err != nil {
+		return nil, err
+	}
+	late, err := lateReminderSchedule(store)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := store.GetSettings()
+
+	var jobs []daemonJob
+	lateJob := daemonJob{kind: jobLateReminder}
+	lateJob.slot = nextSlot(late, lateJob.key(), state, now)
+	lateJob.at = reminder.AfterQuiet(lateJob.slot, quiet)
+	jobs = append(jobs, lateJob)
+
+	if template, _ := store.GetConfig(dailyNoteConfigKey); template != "" {
+		noteJob := daemonJob{kind: jobDailyNote}
+		noteSchedule, err := dailyNoteSchedule(store)
+		if err != nil {
+			return nil, err
+		}
+		noteJob.slot = nextSlot(noteSchedule, noteJob.key(), state, now)
+		noteJob.at = noteJob.slot
+		jobs = append(jobs, noteJob)
+	}
+
+	habits, err := store.GetAllHabits()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habits: %w", err)
 	}
+	for _, habit := range habits {
+		job := daemonJob{kind: jobHabitReminder, habit: habit.Name}
+		if state.IsDismissed(habit.Name, now) || notification.IsHabitMuted(settings, habit.Name) {
+			continue
+		}
+		if until, snoozed := state.SnoozedUntil(habit.Name); snoozed {
+			// A snoozed habit is reminded of once the snooze ends
+			job.slot, job.snoozed = until, true
+		} else if habit.Remind != "" {
+			remind, err := schedule.Parse(habit.Remind)
+			if err != nil {
+				logger.Warn("skipping reminders", "habit", habit.Name, "error", err)
+				continue
+			}
+			job.slot = nextSlot(remind, job.key(), state, now)
+		} else {
+			continue
+		}
+		job.at = reminder.AfterQuiet(job.slot, quiet)
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// nextSlot returns today's last slot of a schedule if it wasn't delivered
+// yet (e.g. the daemon wasn't running), or else the next one
+func nextSlot(s *schedule.Schedule, key string, state *reminder.State, now time.Time) time.Time {
+	previous := s.Previous(now)
+	if previous.YearDay() == now.YearDay() && previous.Year() == now.Year() && !state.WasSent(key, previous) {
+		return previous
+	}
+	return s.Next(now)
+}
 
+// markDaemonJobsDone records that jobs ran, so they're not repeated
+func markDaemonJobsDone(jobs []daemonJob, now time.Time) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	// Lock the store, so snooze or dismiss don't change the state in between
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Load the state again, since snooze or dismiss may have changed it
+	state := reminder.NewState()
+	if err := store.LoadState(reminder.StateName, state); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		state.MarkSent(job.key(), job.slot)
+		if until, snoozed := state.SnoozedUntil(job.habit); job.snoozed && snoozed && until.Equal(job.slot) {
+			state.Clear(job.habit)
+		}
+	}
+	state.Prune(now)
+	return store.SaveState(reminder.StateName, state)
+}
+
+// daemonJobSkipped returns why a due job isn't run because of the
+// notification settings, or "" if it is
+func daemonJobSkipped(job daemonJob) string {
+	if job.kind == jobDailyNote {
+		return ""
+	}
+
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return "" // the job reports the error
+	}
+	return notificationsOff(store.GetSettings(), job.habit)
+}
+
+// runDaemonJob runs a job that is due, giving up on notifications when ctx is done
+func runDaemonJob(ctx context.Context, job daemonJob, now time.Time) error {
+	switch job.kind {
+	case jobLateReminder:
+		return checkAndShowLateReminder(ctx)
+	case jobDailyNote:
+		return writeDailyNoteTable(job.slot)
+	case jobHabitReminder:
+		return sendHabitReminder(ctx, job.habit, now)
+	}
+	return nil
+}
+
+// sendHabitReminder reminds of a habit, unless its goal is already reached
+// (or, for habits without a goal, it was already logged today)
+func sendHabitReminder(ctx context.Context, habitName string, now time.Time) error {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	habit, err := store.GetHabitByName(habitName)
+	if err != nil {
+		return err
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	logs, err := store.GetLogsByHabit(habit.Name, today, today.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %w", err)
+	}
+
+	if habit.DailyGoal == 0 {
+		if len(logs) > 0 {
+			return nil
+		}
+		if err := sendNotification(ctx, store, notification.HabitReminder(habitMessageData(store, *habit, now))); err != nil {
+			return err
+		}
+		printReminderSent(habit.Name)
+		return nil
+	}
+
+	if summary.IsGoalReached(*habit, logs) {
+		return nil
+	}
+	if err := sendNotification(ctx, store, notification.GoalReminder(habitMessageData(store, *habit, now))); err != nil {
+		return err
+	}
+	printReminderSent(habit.Name)
+	return nil
+}
+
+// printReminderSent logs a reminder with how to snooze or dismiss it
+func printReminderSent(habitName string) {
+	fmt.Printf("⏰ Reminder sent for %s (lazytrack snooze %s 30m, or lazytrack dismiss %s)\n", habitName, habitName, habitName)
+}
+
+// lateReminderSchedule returns when the late reminder is sent
+func lateReminderSchedule(store *store.Store) (*schedule.Schedule, error) {
+	spec, _ := store.GetConfig(lateReminderConfigKey)
+	if spec == "" {
+		spec = defaultLateReminder
+	}
+	late, err := schedule.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid late reminder time: %w", err)
+	}
+	return late, nil
+}
+
+// dailyNoteSchedule returns when the habits table is written into the daily
+// note: at the daily note time, and again just before midnight to include late logs
+func dailyNoteSchedule(store *store.Store) (*schedule.Schedule, error) {
+	spec, _ := store.GetConfig(dailyNoteTimeConfigKey)
+	if spec == "" {
+		spec = defaultDailyNoteTime
+	}
+	at, err := parseDailyNoteTime(spec)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Parse(at + ",23:55")
+}
+
+// parseDailyNoteTime checks a daily note time, returning it as HH:MM
+func parseDailyNoteTime(spec string) (string, error) {
+	at, err := time.Parse("15:04", strings.TrimSpace(spec))
+	if err != nil {
+		return "", fmt.Errorf("invalid daily note time: %s (use HH:MM, e.g. 21:00)", spec)
+	}
+	return at.Format("15:04"), nil
+}
+
+// quietWindows returns the quiet hours and do-not-disturb windows
+func quietWindows(store *store.Store) ([]*schedule.Window, error) {
+	var windows []*schedule.Window
+	for _, key := range []string{quietHoursConfigKey, dndConfigKey} {
+		spec, _ := store.GetConfig(key)
+		parsed, err := schedule.ParseWindows(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", strings.ReplaceAll(key, "_", " "), err)
+		}
+		windows = append(windows, parsed...)
+	}
+	return windows, nil
+}
+
+// isPastLateReminder checks if the late reminder time of today has passed
+func isPastLateReminder(store *store.Store, now time.Time) (bool, error) {
+	late, err := lateReminderSchedule(store)
+	if err != nil {
+		return false, err
+	}
+	previous := late.Previous(now)
+	return previous.YearDay() == now.YearDay() && previous.Year() == now.Year(), nil
+}
+
+// checkAndShowLateReminder checks if it's late and shows reminders
+func checkAndShowLateReminder(ctx context.Context) error {
 	// Initialize store
 	store, err := store.NewStore()
 	if err != nil {
@@ -84,6 +474,11 @@ func checkAndShowLateReminder() error {
 	if err != nil {
 		return fmt.Errorf("failed to get habits: %w", err)
 	}
+	state := reminder.NewState()
+	if err := store.LoadState(reminder.StateName, state); err != nil {
+		return err
+	}
+	settings := store.GetSettings()
 
 	// Get current time
 	now := time.Now()
@@ -92,47 +487,23 @@ func checkAndShowLateReminder() error {
 	// Check each habit for pending goals
 	for _, habit := range habits {
 		if habit.DailyGoal == 0 {
-			continue // Skip habits without goals
-		}
-
-		// Get today's logs for this habit
-		today := now.Truncate(24 * time.Hour)
-		tomorrow := today.AddDate(0, 0, 1)
-
-		logs, err := store.GetLogsByHabit(habit.Name, today, tomorrow)
-		if err != nil {
-			continue // Skip if we can't get logs
+			continue // Skip habits without daily goals
 		}
-
-		// Calculate current progress
-		var currentProgress int
-		if habit.GoalType == "count" {
-			for _, log := range logs {
-				currentProgress += log.Count
-			}
-		} else {
-			// For duration-based habits, convert to minutes
-			for _, log := range logs {
-				if log.Duration != "" {
-					// Parse duration and add to progress
-					// This is a simplified version - you might want to use the parser
-					currentProgress += 30 // Default 30 minutes for now
-				}
-			}
+		if state.IsMuted(habit.Name, now) || notification.IsHabitMuted(settings, habit.Name) {
+			continue // Skip snoozed, dismissed and muted habits
 		}
 
 		// Check if goal is not reached
-		if currentProgress < habit.DailyGoal {
+		progress, goal := dayProgress(store, habit, now)
+		if progress < goal {
 			pendingHabits = append(pendingHabits, habit.Name)
 		}
 	}
 
 	// Show late reminder if there are pending habits
 	if len(pendingHabits) > 0 {
-		if notification.IsNotificationEnabled() {
-			if err := notification.ShowLateReminder(pendingHabits); err != nil {
-				return fmt.Errorf("late reminder notification failed: %w", err)
-			}
+		if err := sendNotification(ctx, store, notification.LateReminder(pendingHabits, getPaceHints(store, pendingHabits, now))); err != nil {
+			return err
 		}
 		fmt.Printf("🌙 Late reminder sent for: %s\n", joinHabitsDaemon(pendingHabits))
 	}
@@ -140,6 +511,24 @@ func checkAndShowLateReminder() error {
 	return nil
 }
 
+// writeDailyNoteTable writes the habits table into the daily note of a day
+func writeDailyNoteTable(day time.Time) error {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	path, err := updateDailyNote(store, day, dailynote.HabitsSection)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		fmt.Printf("📝 Habits table written to %s\n", path)
+	}
+	return nil
+}
+
 // joinHabitsDaemon joins habit names with commas (for daemon)
 func joinHabitsDaemon(habits []string) string {
 	if len(habits) == 0 {
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/daemon"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/spf13/cobra"
+)
+
+// How long to wait for the daemon to start or stop
+const (
+	daemonStartTimeout = 5 * time.Second
+	daemonStopTimeout  = 10 * time.Second
+)
+
+// daemonPaths are the daemon's files in the data directory
+type daemonPaths struct {
+	pid string
+	log string
+}
+
+// getDaemonPaths returns the paths of the daemon's PID and log files
+func getDaemonPaths() (daemonPaths, error) {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return daemonPaths{}, fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return daemonPaths{
+		pid: filepath.Join(store.DataDir(), "daemon.pid"),
+		log: filepath.Join(store.DataDir(), "daemon.log"),
+	}, nil
+}
+
+// newDaemonStatusCmd creates the daemon status command
+func newDaemonStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the daemon is running",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonStatus()
+		},
+	}
+}
+
+// newDaemonStopCmd creates the daemon stop command
+func newDaemonStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonStop()
+		},
+	}
+}
+
+// newDaemonRestartCmd creates the daemon restart command
+func newDaemonRestartCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the daemon in the background",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonRestart()
+		},
+	}
+}
+
+// newDaemonLogsCmd creates the daemon logs command
+func newDaemonLogsCmd() *cobra.Command {
+	var lines int
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the daemon's log",
+		Long: `Show the daemon's log, one JSON object per line.
+
+Examples:
+  lazytrack daemon logs
+  lazytrack daemon logs -n 100
+  lazytrack daemon logs -f   # Keep printing new lines`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonLogs(lines, follow)
+		},
+	}
+
+	cmd.Flags().IntVarP(&lines, "lines", "n", 20, "Number of lines to show")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing lines as they're logged")
+	return cmd
+}
+
+// startDaemonBackground starts the daemon as a detached background process
+func startDaemonBackground(paths daemonPaths) error {
+	if pid, running := daemon.Running(paths.pid); running {
+		return fmt.Errorf("daemon is already running (PID %d)", pid)
+	}
+
+	fmt.Println("🔄 Starting LazyTrack daemon...")
+	pid, err := daemon.Detach([]string{"daemon"}, paths.pid, daemonStartTimeout)
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Daemon started in the background (PID %d)\n", pid)
+	fmt.Println("💡 Check on it with 'lazytrack daemon status' or 'lazytrack daemon logs'")
+	return nil
+}
+
+// runDaemonStatus handles the daemon status command execution
+func runDaemonStatus() error {
+	paths, err := getDaemonPaths()
+	if err != nil {
+		return err
+	}
+
+	pid, running := daemon.Running(paths.pid)
+	if !running {
+		yellow := color.New(color.FgYellow, color.Bold)
+		yellow.Println("⚪ Daemon is not running")
+		fmt.Println("💡 Start it with: lazytrack daemon --background")
+		return nil
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("🟢 Daemon is running (PID %d)\n", pid)
+	if startedAt, err := daemon.StartedAt(paths.pid); err == nil {
+		fmt.Printf("   Started:       %s\n", startedAt.Format("Mon Jan 2 15:04"))
+	}
+	jobs, err := planDaemonJobs(time.Now(), slog.New(slog.DiscardHandler))
+	if err == nil {
+		var next daemonJob
+		for _, job := range jobs {
+			if job.kind != jobDailyNote && (next.at.IsZero() || job.at.Before(next.at)) {
+				next = job
+			}
+		}
+		if !next.at.IsZero() {
+			what := "late reminder"
+			if next.kind == jobHabitReminder {
+				what = next.habit
+			}
+			fmt.Printf("   Next reminder: %s (%s)\n", next.at.Format("Mon 15:04"), what)
+		}
+	}
+	fmt.Printf("   Log:           %s\n", paths.log)
+	return nil
+}
+
+// runDaemonStop handles the daemon stop command execution
+func runDaemonStop() error {
+	paths, err := getDaemonPaths()
+	if err != nil {
+		return err
+	}
+
+	if err := daemon.Stop(paths.pid, daemonStopTimeout); err != nil {
+		return err
+	}
+	green := color.New(color.FgGreen, color.Bold)
+	green.Println("🛑 Daemon stopped")
+	return nil
+}
+
+// runDaemonRestart handles the daemon restart command execution
+func runDaemonRestart() error {
+	paths, err := getDaemonPaths()
+	if err != nil {
+		return err
+	}
+
+	if _, running := daemon.Running(paths.pid); running {
+		if err := daemon.Stop(paths.pid, daemonStopTimeout); err != nil {
+			return err
+		}
+		fmt.Println("🛑 Daemon stopped")
+	}
+	return startDaemonBackground(paths)
+}
+
+// runDaemonLogs handles the daemon logs command execution
+func runDaemonLogs(lines int, follow bool) error {
+	paths, err := getDaemonPaths()
+	if err != nil {
+		return err
+	}
+
+	tail, err := daemon.Tail(paths.log, lines)
+	if err != nil {
+		return err
+	}
+	if len(tail) == 0 && !follow {
+		fmt.Println("No daemon logs yet")
+		return nil
+	}
+	for _, line := range tail {
+		fmt.Println(line)
+	}
+
+	if follow {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return daemon.Follow(ctx, paths.log, os.Stdout)
+	}
+	return nil
+}
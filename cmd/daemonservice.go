@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/daemon"
+	"github.com/master-wayne7/lazytrack/service"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/spf13/cobra"
+)
+
+// newDaemonInstallCmd creates the daemon install command
+func newDaemonInstallCmd() *cobra.Command {
+	var printOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Start the daemon automatically at login",
+		Long: `Install the daemon as a service of your session, so it starts now and
+whenever you log in: a systemd user unit on Linux, a launchd agent on macOS
+and a scheduled task on Windows. The service runs this lazytrack binary, so
+install again after moving it.
+
+Examples:
+  lazytrack daemon install
+  lazytrack daemon install --print   # Show the service file without installing`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonInstall(printOnly)
+		},
+	}
+
+	cmd.Flags().BoolVar(&printOnly, "print", false, "Print the service file instead of installing it")
+	return cmd
+}
+
+// newDaemonUninstallCmd creates the daemon uninstall command
+func newDaemonUninstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Stop starting the daemon at login",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonUninstall()
+		},
+	}
+}
+
+// runDaemonInstall handles the daemon install command execution
+func runDaemonInstall(printOnly bool) error {
+	svc, err := getDaemonService()
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		fmt.Printf("# %s\n", svc.Path)
+		fmt.Print(svc.Content)
+		return nil
+	}
+
+	// A daemon started by hand would keep the service's daemon from starting
+	paths, err := getDaemonPaths()
+	if err != nil {
+		return err
+	}
+	if _, running := daemon.Running(paths.pid); running {
+		if err := daemon.Stop(paths.pid, daemonStopTimeout); err != nil {
+			return err
+		}
+		fmt.Println("🛑 Stopped the running daemon, so the service can start it")
+	}
+
+	if err := svc.Install(); err != nil {
+		return fmt.Errorf("failed to install the %s service (written to %s): %w", svc.Platform, svc.Path, err)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Daemon installed as a %s service: %s\n", svc.Platform, svc.Path)
+	fmt.Println("💡 It's running now and starts whenever you log in ('lazytrack daemon uninstall' to undo)")
+	return nil
+}
+
+// runDaemonUninstall handles the daemon uninstall command execution
+func runDaemonUninstall() error {
+	svc, err := getDaemonService()
+	if err != nil {
+		return err
+	}
+	if !svc.Installed() {
+		return fmt.Errorf("the daemon isn't installed as a service (no %s)", svc.Path)
+	}
+
+	if err := svc.Uninstall(); err != nil {
+		return fmt.Errorf("failed to uninstall the %s service: %w", svc.Platform, err)
+	}
+
+	// The scheduled task's daemon runs detached from the task, so it's left
+	// running when the task is removed
+	paths, err := getDaemonPaths()
+	if err != nil {
+		return err
+	}
+	if _, running := daemon.Running(paths.pid); running {
+		if err := daemon.Stop(paths.pid, daemonStopTimeout); err != nil {
+			return err
+		}
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Daemon service removed: %s\n", svc.Path)
+	return nil
+}
+
+// getDaemonService returns the service running this binary's daemon on this
+// platform
+func getDaemonService() (*service.Service, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the lazytrack executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	if strings.Contains(executable, "go-build") {
+		return nil, fmt.Errorf("%s is a temporary build from 'go run'; install lazytrack first", executable)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	cfg := service.Config{Executable: executable, Args: []string{"daemon"}, Label: service.Label}
+	if runtime.GOOS == "windows" {
+		// Scheduled tasks run in a console window, so the daemon detaches from it
+		cfg.Args = []string{"daemon", "--background"}
+		cfg.Label = service.TaskName
+		if domain := os.Getenv("USERDOMAIN"); domain != "" {
+			cfg.User = domain + `\` + os.Getenv("USERNAME")
+		} else {
+			cfg.User = os.Getenv("USERNAME")
+		}
+	}
+	return service.New(runtime.GOOS, cfg, homeDir, store.DataDir())
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/dailynote"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/spf13/cobra"
+)
+
+// dailyNoteConfigKey is the config key of the daily note path template
+const dailyNoteConfigKey = "daily_note_path"
+
+// dailyNoteTimeConfigKey is the config key of the time the daemon writes the
+// habits table
+const dailyNoteTimeConfigKey = "daily_note_time"
+
+// defaultDailyNoteTime is when the daemon writes the habits table unless configured
+const defaultDailyNoteTime = "21:00"
+
+// dailyNoteHeadings are the headings added above new sections of a note
+var dailyNoteHeadings = map[string]string{
+	dailynote.LogSection:    "## Habit Log",
+	dailynote.HabitsSection: "## Habits",
+}
+
+// NewDailyNoteCmd creates the daily-note command
+func NewDailyNoteCmd() *cobra.Command {
+	var date string
+
+	cmd := &cobra.Command{
+		Use:   "daily-note",
+		Short: "Write the day's logs and habits table into your daily note",
+		Long: `Write the day's logs and habits table into a Markdown daily note, e.g. in
+an Obsidian vault. Set the note path first:
+
+  lazytrack config --daily-note "~/vault/Daily/{{date}}.md"
+
+Once set, every 'lazytrack <habit>' updates the log list of the day's note and
+the daemon writes the habits table at 21:00 (or the time set with
+'lazytrack config --daily-note-time 20:30') and again at 23:55 to include late
+logs. lazytrack only touches the parts between its <!-- lazytrack:... -->
+markers, so running it again updates them in place and the rest of the note is
+yours.
+
+Examples:
+  lazytrack daily-note
+  lazytrack daily-note --date 2026-10-17`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDailyNote(date)
+		},
+	}
+
+	cmd.Flags().StringVar(&date, "date", "", "Day to write (YYYY-MM-DD, default: today)")
+	return cmd
+}
+
+// runDailyNote handles the daily-note command execution
+func runDailyNote(date string) error {
+	day := time.Now()
+	if date != "" {
+		var err error
+		if day, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+			return fmt.Errorf("invalid date: %s (use YYYY-MM-DD)", date)
+		}
+	}
+
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	path, err := updateDailyNote(store, day, dailynote.LogSection, dailynote.HabitsSection)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return fmt.Errorf("no daily note configured (run: lazytrack config --daily-note \"~/vault/Daily/{{date}}.md\")")
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("📝 Updated %s\n", path)
+	return nil
+}
+
+// updateDailyNote rewrites sections of a day's note and returns its path, or
+// "" if no daily note is configured
+func updateDailyNote(store *store.Store, day time.Time, sections ...string) (string, error) {
+	template, _ := store.GetConfig(dailyNoteConfigKey)
+	if template == "" {
+		return "", nil
+	}
+
+	path, err := dailynote.Path(template, day)
+	if err != nil {
+		return "", err
+	}
+
+	habits, err := store.GetAllHabits()
+	if err != nil {
+		return "", fmt.Errorf("failed to get habits: %w", err)
+	}
+	allLogs, err := store.GetAllLogs()
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs: %w", err)
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 1)
+	var logs = allLogs[:0]
+	for _, log := range allLogs {
+		if !log.LoggedAt.Before(start) && log.LoggedAt.Before(end) {
+			logs = append(logs, log)
+		}
+	}
+
+	for _, section := range sections {
+		var content string
+		switch section {
+		case dailynote.LogSection:
+			content = dailynote.LogList(logs, habits)
+		case dailynote.HabitsSection:
+			content = dailynote.HabitsTable(habits, logs)
+		}
+		if err := dailynote.UpdateSection(path, section, dailyNoteHeadings[section], content); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyNoteSchedule(t *testing.T) {
+	tests := []struct {
+		config  string
+		want    []string // the day's slots
+		wantErr bool
+	}{
+		{config: "", want: []string{"21:00", "23:55"}},
+		{config: "20:30", want: []string{"20:30", "23:55"}},
+		{config: " 7:05 ", want: []string{"07:05", "23:55"}},
+		{config: "21", wantErr: true},
+		{config: "25:00", wantErr: true},
+	}
+
+	for _, test := range tests {
+		s := newTestStore(t)
+		if err := s.SetConfig(dailyNoteTimeConfigKey, test.config); err != nil {
+			t.Fatalf("SetConfig: %v", err)
+		}
+
+		noteSchedule, err := dailyNoteSchedule(s)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: got %s, want an error", test.config, noteSchedule)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.config, err)
+			continue
+		}
+
+		at := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+		for _, want := range test.want {
+			at = noteSchedule.Next(at)
+			if got := at.Format("15:04"); got != want {
+				t.Errorf("%q: got a slot at %s, want %s", test.config, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// NewExportCmd creates the export command
+func NewExportCmd() *cobra.Command {
+	var format string
+	var since string
+	var habitNames []string
+	var outPath string
+	var serveAddr string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export your logs as JSON, CSV, NDJSON, text, org or iCalendar",
+		Long: `Export your logs as JSON, CSV, NDJSON, plain text, org-mode or iCalendar (.ics).
+
+--since accepts a date (2026-01-01) or a range like 30d or this-month,
+in which case the export starts at the beginning of that range.
+
+The ics format turns time-ranged logs into calendar events and count-based
+logs into all-day events with the day's count. With --serve, the calendar is
+served as a feed your calendar app can subscribe to; the feed also accepts
+?habit=code&since=90d query parameters.
+
+Examples:
+  lazytrack export --format csv --out logs.csv
+  lazytrack export --format json --since 2026-01-01 --habit code
+  lazytrack export --format ndjson --since 30d | jq .
+  lazytrack export --format org --out habits.org
+  lazytrack export --format ics --out lazytrack.ics
+  lazytrack export --format ics --serve 127.0.0.1:5151`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if serveAddr != "" {
+				if format != "ics" {
+					return fmt.Errorf("--serve only supports the ics format")
+				}
+				return runICSFeed(serveAddr, since, habitNames)
+			}
+			return runExport(format, since, habitNames, outPath)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "json", "Export format (json, csv, ndjson, text, org or ics)")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Only export logs from this date or range on")
+	cmd.Flags().StringSliceVarP(&habitNames, "habit", "a", nil, "Only export these habits")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Output file (default: stdout)")
+	cmd.Flags().StringVar(&serveAddr, "serve", "", "Serve an ics feed on this address (e.g. 127.0.0.1:5151)")
+	return cmd
+}
+
+// runExport handles the export command execution
+func runExport(format, since string, habitNames []string, outPath string) error {
+	// Check the format first, so --out isn't truncated for nothing
+	if err := exchange.ValidateFormat(format); err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	logs, err := filterLogs(store, since, habitNames)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if outPath != "" {
+		file, err := os.Create(outPath)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if format == "ics" {
+		habits, err := store.GetAllHabits()
+		if err != nil {
+			return fmt.Errorf("failed to get habits: %w", err)
+		}
+		err = exchange.WriteICS(w, logs, habits, time.Now())
+	} else {
+		err = exchange.Export(w, format, logs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export logs: %w", err)
+	}
+
+	if outPath != "" {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("✅ Exported %d logs to %s\n", len(logs), outPath)
+	}
+	return nil
+}
+
+// filterLogs gets all logs since a date or range for the given habits, oldest first
+func filterLogs(store *store.Store, since string, habitNames []string) ([]types.Log, error) {
+	var startDate time.Time
+	if since != "" {
+		var err error
+		startDate, _, err = parser.ParseRange(since, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range habitNames {
+		wanted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	allLogs, err := store.GetAllLogs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
+	}
+
+	var logs []types.Log
+	for _, log := range allLogs {
+		if log.LoggedAt.Before(startDate) {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[log.HabitName] {
+			continue
+		}
+		logs = append(logs, log)
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].LoggedAt.Before(logs[j].LoggedAt)
+	})
+
+	return logs, nil
+}
+
+// runICSFeed serves logs as an iCalendar feed, reading the latest data on every request
+func runICSFeed(addr, since string, habitNames []string) error {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && r.URL.Path != "/lazytrack.ics" {
+			http.NotFound(w, r)
+			return
+		}
+
+		// Query parameters override the command-line filters
+		feedSince, feedHabits := since, habitNames
+		if value := r.URL.Query().Get("since"); value != "" {
+			feedSince = value
+		}
+		if values := r.URL.Query()["habit"]; len(values) > 0 {
+			feedHabits = values
+		}
+
+		// The store is only read here, so it's not closed (which would save it)
+		store, err := store.NewStore()
+		if err != nil {
+			http.Error(w, "failed to load data", http.StatusInternalServerError)
+			return
+		}
+
+		logs, err := filterLogs(store, feedSince, feedHabits)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		habits, err := store.GetAllHabits()
+		if err != nil {
+			http.Error(w, "failed to load habits", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="lazytrack.ics"`)
+		if err := exchange.WriteICS(w, logs, habits, time.Now()); err != nil {
+			fmt.Printf("⚠️  Error writing feed: %v\n", err)
+		}
+	})
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("📅 Serving calendar feed at http://%s/lazytrack.ics\n", addr)
+	fmt.Println("💡 Press Ctrl+C to stop")
+
+	return http.ListenAndServe(addr, handler)
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportInvalidFormatKeepsFile(t *testing.T) {
+	newTestStore(t)
+	out := filepath.Join(t.TempDir(), "logs.csv")
+	if err := os.WriteFile(out, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := runExport("xml", "", nil, out)
+	if err == nil || !strings.Contains(err.Error(), "invalid export format") {
+		t.Errorf("runExport = %v, want an invalid format error", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "keep me" {
+		t.Errorf("--out file was overwritten with %q", data)
+	}
+}
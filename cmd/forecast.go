@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// NewForecastCmd creates the forecast command
+func NewForecastCmd() *cobra.Command {
+	var rangeInput string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Project whether you'll hit your goals this period",
+		Long: `Project whether you'll hit your goals this period.
+
+Uses your pace so far to project the end-of-period total for each habit with
+a goal, and shows what you need per day for the rest of the period.
+
+Examples:
+  lazytrack forecast                     # This week
+  lazytrack forecast --range this-month  # This month
+  lazytrack forecast --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runForecast(rangeInput, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVarP(&rangeInput, "range", "r", "this-week", "Period to forecast")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output forecasts as JSON")
+	return cmd
+}
+
+// runForecast handles the forecast command execution
+func runForecast(rangeInput string, jsonOutput bool) error {
+	now := time.Now()
+	startDate, endDate, err := parser.ParseRange(rangeInput, now)
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Get all habits
+	habits, err := store.GetAllHabits()
+	if err != nil {
+		return fmt.Errorf("failed to get habits: %w", err)
+	}
+
+	logsByHabit := loadLogsByHabit(store, habits, startDate, endDate)
+
+	var forecasts []types.Forecast
+	for _, habit := range habits {
+		if habit.DailyGoal == 0 && habit.WeeklyGoal == 0 {
+			continue // Skip habits without goals
+		}
+		forecasts = append(forecasts, summary.CalculateForecast(habit, logsByHabit[habit.Name], startDate, endDate, now))
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(forecasts)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("🔮 Forecast - %s to %s\n", startDate.Format("Jan 2"), endDate.AddDate(0, 0, -1).Format("Jan 2"))
+	cyan.Println(strings.Repeat("=", 50))
+
+	if len(forecasts) == 0 {
+		fmt.Println("No goals set yet. Set one with:")
+		fmt.Println()
+		fmt.Println("  lazytrack config --habit water --goal 8 --type count")
+		return nil
+	}
+
+	for _, forecast := range forecasts {
+		fmt.Println(summary.FormatForecast(forecast))
+	}
+
+	return nil
+}
+
+// getPaceHints describes how much more of each habit is needed today to stay on
+// pace for this week's goal, e.g. "45m more code"
+func getPaceHints(store *store.Store, habitNames []string, now time.Time) []string {
+	startDate := parser.WeekStart(now)
+	endDate := startDate.AddDate(0, 0, 7)
+
+	var hints []string
+	for _, habitName := range habitNames {
+		habit, err := store.GetHabitByName(habitName)
+		if err != nil || habit.DailyGoal == 0 {
+			continue
+		}
+
+		logs, err := store.GetLogsByHabit(habit.Name, startDate, endDate)
+		if err != nil {
+			continue
+		}
+
+		forecast := summary.CalculateForecast(*habit, logs, startDate, endDate, now)
+		if forecast.NeededToday > 0 {
+			hints = append(hints, fmt.Sprintf("%s more %s", summary.FormatAmount(habit.GoalType, forecast.NeededToday), habit.Name))
+		}
+	}
+
+	return hints
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/gitlog"
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies post-commit hooks written by lazytrack
+const hookMarker = "# lazytrack post-commit hook"
+
+// NewGitHookCmd creates the git-hook command
+func NewGitHookCmd() *cobra.Command {
+	var repo string
+	var habit string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "git-hook [install|uninstall]",
+		Short: "Install a git hook that logs commits as they happen",
+		Long: `Install or remove a post-commit hook that runs 'lazytrack git-import' in
+the background after every commit, so coding sessions are logged as you work.
+
+Examples:
+  lazytrack git-hook install
+  lazytrack git-hook install --repo ~/src/app --habit work
+  lazytrack git-hook uninstall`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"install", "uninstall"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "install":
+				return runGitHookInstall(repo, habit, force)
+			case "uninstall":
+				return runGitHookUninstall(repo)
+			default:
+				return fmt.Errorf("invalid action: %s (must be 'install' or 'uninstall')", args[0])
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&repo, "repo", "r", ".", "Path to the git repository")
+	cmd.Flags().StringVarP(&habit, "habit", "a", "code", "Habit to log the sessions as")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing post-commit hook")
+	return cmd
+}
+
+// runGitHookInstall writes the post-commit hook
+func runGitHookInstall(repo, habit string, force bool) error {
+	hookPath, existing, err := postCommitHook(repo)
+	if err != nil {
+		return err
+	}
+	if existing != "" && !strings.Contains(existing, hookMarker) && !force {
+		return fmt.Errorf("%s already exists (use --force to replace it)", hookPath)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the lazytrack executable: %w", err)
+	}
+
+	// Run in the background so committing never waits for lazytrack. git-import
+	// locks the data like every other writer, so quick commits queue up.
+	script := fmt.Sprintf(`#!/bin/sh
+%s: logs commits as coding sessions
+'%s' git-import --repo "$(git rev-parse --show-toplevel)" --habit '%s' --since 2d >/dev/null 2>&1 &
+`, hookMarker, shellQuote(filepath.ToSlash(executable)), shellQuote(strings.ToLower(habit)))
+
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write hook: %w", err)
+	}
+	// WriteFile keeps the mode of a replaced file, and git ignores hooks that are not executable
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		return fmt.Errorf("failed to make hook executable: %w", err)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Installed post-commit hook: %s\n", hookPath)
+	fmt.Printf("💡 Commits are now logged as '%s' sessions\n", strings.ToLower(habit))
+	return nil
+}
+
+// runGitHookUninstall removes a post-commit hook written by lazytrack
+func runGitHookUninstall(repo string) error {
+	hookPath, existing, err := postCommitHook(repo)
+	if err != nil {
+		return err
+	}
+	if existing == "" {
+		return fmt.Errorf("no post-commit hook installed")
+	}
+	if !strings.Contains(existing, hookMarker) {
+		return fmt.Errorf("%s was not installed by lazytrack, leaving it alone", hookPath)
+	}
+
+	if err := os.Remove(hookPath); err != nil {
+		return fmt.Errorf("failed to remove hook: %w", err)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Removed post-commit hook: %s\n", hookPath)
+	return nil
+}
+
+// postCommitHook returns the path and current contents of a repository's post-commit hook
+func postCommitHook(repo string) (string, string, error) {
+	topLevel, err := gitlog.TopLevel(repo)
+	if err != nil {
+		return "", "", fmt.Errorf("not a git repository: %w", err)
+	}
+	hooksDir, err := gitlog.HooksDir(topLevel)
+	if err != nil {
+		return "", "", err
+	}
+
+	hookPath := filepath.Join(hooksDir, "post-commit")
+	data, err := os.ReadFile(hookPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("failed to read hook: %w", err)
+	}
+	return hookPath, string(data), nil
+}
+
+// shellQuote escapes a value for use inside single quotes in a shell script
+func shellQuote(value string) string {
+	return strings.ReplaceAll(value, `'`, `'\''`)
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/gitlog"
+	"github.com/master-wayne7/lazytrack/importer"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// maxNoteSubjects is the number of commit subjects kept in a session's notes
+const maxNoteSubjects = 3
+
+// gitImportOptions holds the flags of the git-import command
+type gitImportOptions struct {
+	repo    string
+	habit   string
+	authors []string
+	since   string
+	until   string
+	gap     time.Duration
+	lead    time.Duration
+	dryRun  bool
+}
+
+// NewGitImportCmd creates the git-import command
+func NewGitImportCmd() *cobra.Command {
+	var opts gitImportOptions
+
+	cmd := &cobra.Command{
+		Use:   "git-import",
+		Short: "Log coding sessions estimated from git commits",
+		Long: `Log coding sessions estimated from the commits of a local git repository.
+
+Commits less than --gap apart are grouped into one session, which is assumed
+to have started --lead before its first commit. Each session becomes a log
+with the number of commits in its notes. A session running across --since is
+read in full. Running the import again (or from the post-commit hook, see
+'lazytrack git-hook') updates the logged sessions that a session overlaps
+instead of duplicating them, and merges sessions that new commits join.
+
+By default only your own commits (git config user.email) are counted.
+
+Examples:
+  lazytrack git-import
+  lazytrack git-import --repo ~/src/app --habit code --since 2026-01-01
+  lazytrack git-import --author me@work.com --author me@home.com --gap 90m
+  lazytrack git-import --since last-month --until last-month --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGitImport(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", ".", "Path to the git repository")
+	cmd.Flags().StringVarP(&opts.habit, "habit", "a", "code", "Habit to log the sessions as")
+	cmd.Flags().StringSliceVar(&opts.authors, "author", nil, "Only count commits by these emails (default: git config user.email)")
+	cmd.Flags().StringVarP(&opts.since, "since", "s", "90d", "Only count commits from this date or range on")
+	cmd.Flags().StringVarP(&opts.until, "until", "u", "", "Only count commits up to this date or the end of this range")
+	cmd.Flags().DurationVar(&opts.gap, "gap", 2*time.Hour, "Longest pause between commits of one session")
+	cmd.Flags().DurationVar(&opts.lead, "lead", 30*time.Minute, "Time spent before the first commit of a session")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview the sessions without saving anything")
+	return cmd
+}
+
+// runGitImport handles the git-import command execution
+func runGitImport(opts gitImportOptions) error {
+	habitName := strings.ToLower(strings.TrimSpace(opts.habit))
+	if habitName == "" {
+		return fmt.Errorf("habit name cannot be empty")
+	}
+
+	repo, err := gitlog.TopLevel(opts.repo)
+	if err != nil {
+		return fmt.Errorf("not a git repository: %w", err)
+	}
+
+	now := time.Now()
+	var readOpts gitlog.Options
+	if opts.since != "" {
+		if readOpts.Since, _, err = parser.ParseRange(opts.since, now); err != nil {
+			return err
+		}
+	}
+	if opts.until != "" {
+		if _, readOpts.Until, err = parser.ParseRange(opts.until, now); err != nil {
+			return err
+		}
+	}
+	readOpts.Authors = opts.authors
+	if len(readOpts.Authors) == 0 {
+		if email := gitlog.UserEmail(repo); email != "" {
+			readOpts.Authors = []string{email}
+		}
+	}
+
+	sessions, err := gitlog.ReadSessions(repo, readOpts, opts.gap, opts.lead)
+	if err != nil {
+		return fmt.Errorf("failed to read commits: %w", err)
+	}
+	commits := 0
+	for _, session := range sessions {
+		commits += len(session.Commits)
+	}
+
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	var habit *types.Habit
+	if !opts.dryRun {
+		if habit, err = store.GetOrCreateHabit(habitName); err != nil {
+			return fmt.Errorf("failed to get/create habit: %w", err)
+		}
+	}
+
+	if opts.dryRun {
+		cyan := color.New(color.FgCyan, color.Bold)
+		cyan.Println("🔍 Dry run - nothing was saved")
+	}
+
+	logs, err := store.GetAllLogs()
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %w", err)
+	}
+	prefix := gitlog.Prefix(repo, readOpts.Authors)
+	claimed := make(map[int]bool)
+
+	added, updated, merged, unchanged := 0, 0, 0, 0
+	var total time.Duration
+	for _, session := range sessions {
+		log := sessionLog(session, habitName, repo, readOpts.Authors)
+		total += session.End.Sub(session.Start)
+
+		// The session replaces the logs it grew out of, and the first of them keeps its ID
+		matches := loggedSessions(logs, session, habitName, prefix, claimed)
+		var existing *types.Log
+		if len(matches) > 0 {
+			existing = &matches[0]
+		}
+
+		marker := "~"
+		switch {
+		case existing == nil:
+			marker = "+"
+			added++
+		case len(matches) == 1 && existing.Source == log.Source && existing.Duration == log.Duration &&
+			existing.Notes == log.Notes && existing.StartedAt.Equal(log.StartedAt) && existing.LoggedAt.Equal(log.LoggedAt):
+			unchanged++
+			continue
+		default:
+			updated++
+			merged += len(matches) - 1
+		}
+
+		if opts.dryRun {
+			fmt.Printf("   %s %s %-6s %s\n", marker, session.Start.Local().Format("2006-01-02 15:04"), log.Duration, log.Notes)
+			continue
+		}
+
+		log.HabitID = habit.ID
+		if existing != nil {
+			log.ID = existing.ID
+			err = store.UpdateLog(log)
+		} else {
+			err = store.AddLogEntry(log)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+		for _, match := range matches[min(1, len(matches)):] {
+			if err := store.DeleteLog(match.ID); err != nil {
+				return fmt.Errorf("failed to merge session: %w", err)
+			}
+		}
+	}
+
+	displayGitImportResult(commits, len(sessions), added, updated, merged, unchanged, total, opts.dryRun)
+	return nil
+}
+
+// loggedSessions returns the logs of a habit that a session replaces, oldest
+// first: the sessions with keys starting with prefix (of the same repository
+// and authors) it overlaps, and those logged before sessions had such keys
+// ("git:<hash>") by one of its commits. Logs are claimed by the first session
+// they match.
+func loggedSessions(logs []types.Log, session gitlog.Session, habitName, prefix string, claimed map[int]bool) []types.Log {
+	hashes := make(map[string]bool)
+	for _, commit := range session.Commits {
+		hashes["git:"+commit.Hash] = true
+	}
+
+	var matches []types.Log
+	for _, log := range logs {
+		if log.HabitName != habitName || claimed[log.ID] {
+			continue
+		}
+		if hashes[log.Source] || (strings.HasPrefix(log.Source, prefix) && session.Overlaps(log.StartedAt, log.LoggedAt)) {
+			matches = append(matches, log)
+			claimed[log.ID] = true
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].StartedAt.Before(matches[j].StartedAt)
+	})
+	return matches
+}
+
+// sessionLog converts a coding session to a log entry
+func sessionLog(session gitlog.Session, habitName, repo string, authors []string) types.Log {
+	var subjects []string
+	for _, commit := range session.Commits {
+		if len(subjects) == maxNoteSubjects {
+			subjects = append(subjects, "…")
+			break
+		}
+		subjects = append(subjects, commit.Subject)
+	}
+
+	commits := "commits"
+	if len(session.Commits) == 1 {
+		commits = "commit"
+	}
+
+	return types.Log{
+		HabitName: habitName,
+		Duration:  importer.FormatMinutes(session.End.Sub(session.Start)),
+		StartedAt: session.Start,
+		LoggedAt:  session.End,
+		Notes:     fmt.Sprintf("%d %s in %s: %s", len(session.Commits), commits, filepath.Base(repo), strings.Join(subjects, "; ")),
+		Source:    session.Key(repo, authors),
+	}
+}
+
+// displayGitImportResult shows what was (or would be) logged
+func displayGitImportResult(commits, sessions, added, updated, merged, unchanged int, total time.Duration, dryRun bool) {
+	green := color.New(color.FgGreen, color.Bold)
+
+	fmt.Printf("🔎 Found %d commits in %d sessions (%s)\n", commits, sessions, importer.FormatMinutes(total))
+
+	verb := "Logged"
+	if dryRun {
+		verb = "Would log"
+	}
+	green.Printf("✅ %s %d new sessions", verb, added)
+	if updated > 0 {
+		fmt.Printf(", %d updated", updated)
+	}
+	if merged > 0 {
+		fmt.Printf(", %d merged", merged)
+	}
+	if unchanged > 0 {
+		fmt.Printf(" (%d already logged)", unchanged)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/gitlog"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// testCommit adds an empty commit at a time to a repository
+func testCommit(t *testing.T, repo string, when time.Time, subject string) {
+	t.Helper()
+	command := exec.Command("git", "-C", repo, "commit", "-q", "--allow-empty", "--no-verify", "-m", subject)
+	date := when.Format(time.RFC3339)
+	command.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=me@example.com", "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=me@example.com", "GIT_COMMITTER_DATE="+date,
+	)
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v: %s", err, output)
+	}
+}
+
+// importedLogs imports a repository's sessions and returns the logs of code
+func importedLogs(t *testing.T, repo string) []types.Log {
+	t.Helper()
+	opts := gitImportOptions{repo: repo, habit: "code", authors: []string{"me@example.com"}, gap: 2 * time.Hour, lead: 30 * time.Minute}
+	if err := runGitImport(opts); err != nil {
+		t.Fatalf("runGitImport: %v", err)
+	}
+	s, err := store.NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	logs, _ := s.GetLogsByHabit("code", time.Time{}, time.Now().AddDate(1, 0, 0))
+	return logs
+}
+
+func TestGitImportMergesSessions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	newTestStore(t)
+	repo := t.TempDir()
+	if _, err := gitlog.Run(repo, "init", "-q"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	repo, _ = gitlog.TopLevel(repo)
+
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	testCommit(t, repo, base, "first")
+	testCommit(t, repo, base.Add(3*time.Hour), "second")
+
+	logs := importedLogs(t, repo)
+	if len(logs) != 2 {
+		t.Fatalf("got %d logs, want a log per session", len(logs))
+	}
+	firstID := logs[0].ID
+
+	// A commit between them joins both sessions into one
+	testCommit(t, repo, base.Add(90*time.Minute), "bridge")
+	logs = importedLogs(t, repo)
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want the sessions merged into one", len(logs))
+	}
+	log := logs[0]
+	if log.ID != firstID {
+		t.Errorf("merged session has ID %d, want the first session's %d", log.ID, firstID)
+	}
+	if !log.StartedAt.Equal(base.Add(-30*time.Minute)) || !log.LoggedAt.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("merged session runs %v-%v, want %v-%v", log.StartedAt, log.LoggedAt, base.Add(-30*time.Minute), base.Add(3*time.Hour))
+	}
+	if log.Duration != "3h30m" {
+		t.Errorf("merged session lasts %s, want 3h30m", log.Duration)
+	}
+
+	// A commit before the first one changes the session's start, not its log
+	testCommit(t, repo, base.Add(-time.Hour), "earlier")
+	logs = importedLogs(t, repo)
+	if len(logs) != 1 || logs[0].ID != firstID || logs[0].Duration != "4h30m" {
+		t.Errorf("got %+v, want the session updated to 4h30m", logs)
+	}
+
+	if logs := importedLogs(t, repo); len(logs) != 1 || logs[0].ID != firstID {
+		t.Errorf("got %+v after importing again, want the session unchanged", logs)
+	}
+}
+
+func TestGitImportReplacesCommitKeys(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	s := newTestStore(t)
+	repo := t.TempDir()
+	if _, err := gitlog.Run(repo, "init", "-q"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	repo, _ = gitlog.TopLevel(repo)
+
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	testCommit(t, repo, base, "first")
+	hash, err := gitlog.Run(repo, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("git rev-parse: %v", err)
+	}
+	testCommit(t, repo, base.Add(time.Hour), "second")
+
+	// A session logged when sessions were keyed by their first commit
+	habit, _ := s.GetOrCreateHabit("code")
+	legacy := types.Log{HabitID: habit.ID, HabitName: "code", Duration: "30m", StartedAt: base.Add(-30 * time.Minute), LoggedAt: base, Source: "git:" + hash}
+	if err := s.AddLogEntry(legacy); err != nil {
+		t.Fatalf("AddLogEntry: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	logs := importedLogs(t, repo)
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want the legacy session updated", len(logs))
+	}
+	if want := gitlog.Prefix(repo, []string{"me@example.com"}); !strings.HasPrefix(logs[0].Source, want) || logs[0].Duration != "1h30m" {
+		t.Errorf("got %+v, want a 1h30m session keyed by %s", logs[0], want)
+	}
+}
@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/importer"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// maxPreviewRows is the number of rows shown by a dry run
+const maxPreviewRows = 20
+
+// importResult summarizes an import
+type importResult struct {
+	Added      int
+	Updated    int
+	Duplicates int
+	NewHabits  []string
+}
+
+// NewImportCmd creates the import command
+func NewImportCmd() *cobra.Command {
+	var format, source, mappingPath, defaultHabit string
+	var dryRun, interactive bool
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import logs from a file or another tracker",
+		Long: `Import logs from a JSON, CSV, NDJSON, plain-text or org-mode file.
+
+Every row is validated before anything is imported; if any row is invalid,
+all errors are listed with their line numbers and nothing is imported.
+Logs that already exist (same habit, timestamp and quantity) are skipped and
+missing habits are created automatically.
+
+Examples:
+  lazytrack import logs.csv
+  lazytrack import backup.json --dry-run
+  lazytrack import logs.txt --format ndjson
+
+Timewarrior and Toggl:
+  Use --source to read a Timewarrior data directory, a .data file or the
+  output of 'timew export', or a Toggl CSV/JSON export. Projects and tags are
+  mapped to habits with a JSON mapping file such as {"client x": "code",
+  "books": "read", "meetings": "-"} ("-" skips an entry), by answering a
+  prompt for each unknown key with --interactive, or with --default-habit.
+
+  lazytrack import ~/.timewarrior/data --source timewarrior --map map.json
+  lazytrack import toggl.csv --source toggl --interactive --dry-run
+
+Loop Habit Tracker and Habitica:
+  Use --source loop with Loop's CSV export (the zip file or its directory)
+  or --source habitica with Habitica's userdata.json. Habits are created
+  with their frequency as a daily or weekly goal and every check-in becomes
+  a log, so streaks carry over.
+
+  lazytrack import "Loop Habits CSV 2026-10-17.zip" --source loop
+  lazytrack import userdata.json --source habitica --dry-run
+
+Apple Health and Google Fit:
+  Use --source apple-health with export.zip (or the export.xml inside it) or
+  --source google-fit with the Takeout "Fit" folder. Steps become one 'steps'
+  log per day, sleep one 'sleep' log per night, and workouts logs like 'run',
+  'walk', 'cycle' or 'gym'. Importing a newer export again updates these logs
+  instead of duplicating them.
+
+  lazytrack import export.zip --source apple-health
+  lazytrack import Takeout/Fit --source google-fit --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch source {
+			case "", "lazytrack":
+				return runImport(args[0], format, dryRun)
+			case "timewarrior", "toggl":
+				return runExternalImport(args[0], source, mappingPath, defaultHabit, interactive, dryRun)
+			case "loop", "habitica", "apple-health", "google-fit":
+				return runBackupImport(args[0], source, dryRun)
+			default:
+				return fmt.Errorf("invalid source: %s (must be 'lazytrack', 'timewarrior', 'toggl', 'loop', 'habitica', 'apple-health' or 'google-fit')", source)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "Import format (json, csv, ndjson, text or org; default: from file extension)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview the import without saving anything")
+	cmd.Flags().StringVarP(&source, "source", "s", "lazytrack", "Where the file comes from (lazytrack, timewarrior, toggl, loop, habitica, apple-health or google-fit)")
+	cmd.Flags().StringVarP(&mappingPath, "map", "m", "", "JSON file mapping projects/tags to habits")
+	cmd.Flags().StringVar(&defaultHabit, "default-habit", "", "Habit for entries without a mapping")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Ask which habit to use for unmapped projects/tags")
+	return cmd
+}
+
+// runImport handles the import command execution
+func runImport(path, format string, dryRun bool) error {
+	if format == "" {
+		var err error
+		if format, err = exchange.DetectFormat(path); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := exchange.Parse(file, format)
+	if err != nil {
+		return importError(err)
+	}
+
+	return importRecords(rowRecords(rows), dryRun)
+}
+
+// runExternalImport imports intervals exported by Timewarrior or Toggl
+func runExternalImport(path, source, mappingPath, defaultHabit string, interactive, dryRun bool) error {
+	var entries []importer.Entry
+	var lineErrors []exchange.LineError
+	var err error
+
+	switch source {
+	case "timewarrior":
+		entries, lineErrors, err = importer.ReadTimewarrior(path)
+	case "toggl":
+		entries, lineErrors, err = importer.ReadToggl(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	mapper := &importer.Mapper{DefaultHabit: strings.ToLower(strings.TrimSpace(defaultHabit))}
+	if mappingPath != "" {
+		if mapper.Mapping, err = importer.LoadMapping(mappingPath); err != nil {
+			return err
+		}
+	}
+	if interactive {
+		mapper.Prompt = promptHabit(bufio.NewReader(os.Stdin))
+	}
+
+	rows, skipped := importer.ToRows(entries, mapper)
+	rows, err = exchange.Validate(rows, lineErrors...)
+	if err != nil {
+		return importError(err)
+	}
+
+	if unmapped := mapper.Unmapped(); len(unmapped) > 0 {
+		yellow := color.New(color.FgYellow)
+		yellow.Printf("⚠️  No habit for: %s (use --map, --interactive or --default-habit)\n", strings.Join(unmapped, ", "))
+	}
+	if skipped > 0 {
+		fmt.Printf("⏭️  Skipped %d entries without a habit or shorter than a minute\n", skipped)
+	}
+
+	return importRecords(rowRecords(rows), dryRun)
+}
+
+// runBackupImport imports habits and their history from habit trackers and health apps
+func runBackupImport(path, source string, dryRun bool) error {
+	var backup importer.Backup
+	var err error
+
+	switch source {
+	case "loop":
+		backup, err = importer.ReadLoop(path)
+	case "habitica":
+		backup, err = importer.ReadHabitica(path)
+	case "apple-health":
+		backup, err = importer.ReadAppleHealth(path)
+	case "google-fit":
+		backup, err = importer.ReadGoogleFit(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Create missing habits with their goals, keeping existing habits as configured
+	var newHabits []string
+	for _, habit := range backup.Habits {
+		if _, err := store.GetHabitByName(habit.Name); err == nil {
+			continue
+		}
+		newHabits = append(newHabits, habit.Name+describeGoal(habit))
+		if dryRun {
+			continue
+		}
+
+		created, err := store.GetOrCreateHabit(habit.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get/create habit: %w", err)
+		}
+		created.GoalType = habit.GoalType
+		created.DailyGoal = habit.DailyGoal
+		created.WeeklyGoal = habit.WeeklyGoal
+		if err := store.UpdateHabit(created); err != nil {
+			return fmt.Errorf("failed to update habit: %w", err)
+		}
+	}
+
+	result, preview, err := addRecords(store, backup.Records, dryRun)
+	if err != nil {
+		return err
+	}
+	result.NewHabits = newHabits
+
+	if backup.Skipped > 0 {
+		fmt.Printf("⏭️  Skipped %d tasks that are not habits (to-dos, rewards or negative habits)\n", backup.Skipped)
+	}
+	displayImportResult(result, preview, dryRun)
+	return nil
+}
+
+// describeGoal describes an imported habit's goal, e.g. " (3x/week)"
+func describeGoal(habit types.Habit) string {
+	switch {
+	case habit.DailyGoal > 0:
+		return fmt.Sprintf(" (%s/day)", summary.FormatAmount(habit.GoalType, float64(habit.DailyGoal)))
+	case habit.WeeklyGoal > 0:
+		return fmt.Sprintf(" (%s/week)", summary.FormatAmount(habit.GoalType, float64(habit.WeeklyGoal)))
+	default:
+		return ""
+	}
+}
+
+// promptHabit returns a prompt asking which habit a project or tag belongs to
+func promptHabit(reader *bufio.Reader) func(key string) string {
+	return func(key string) string {
+		fmt.Printf("🔗 Habit for '%s' (empty to skip): ", key)
+		answer, _ := reader.ReadString('\n')
+		return strings.TrimSpace(answer)
+	}
+}
+
+// importError prints every invalid row of a failed import
+func importError(err error) error {
+	var validationErr *exchange.ValidationError
+	if errors.As(err, &validationErr) {
+		red := color.New(color.FgRed, color.Bold)
+		red.Println("❌ Nothing was imported, please fix these rows first:")
+		for _, lineErr := range validationErr.Errors {
+			fmt.Printf("   %s\n", lineErr.Error())
+		}
+		return fmt.Errorf("import aborted: %d invalid rows", len(validationErr.Errors))
+	}
+	return fmt.Errorf("failed to read import file: %w", err)
+}
+
+// rowRecords returns the records of validated rows
+func rowRecords(rows []exchange.Row) []exchange.Record {
+	records := make([]exchange.Record, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, row.Record)
+	}
+	return records
+}
+
+// importRecords adds records to the store, skipping duplicates, and prints the result
+func importRecords(records []exchange.Record, dryRun bool) error {
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	result, preview, err := addRecords(store, records, dryRun)
+	if err != nil {
+		return err
+	}
+
+	displayImportResult(result, preview, dryRun)
+	return nil
+}
+
+// addRecords adds records to the store, returning the records that were (or would be) added
+func addRecords(store *store.Store, records []exchange.Record, dryRun bool) (importResult, []exchange.Record, error) {
+	var result importResult
+	var added []exchange.Record
+	seen := make(map[string]bool)
+	newHabits := make(map[string]bool)
+
+	for _, record := range records {
+		key := recordKey(record)
+		if seen[key] {
+			result.Duplicates++
+			continue
+		}
+		seen[key] = true
+
+		// Records with a source replace the log imported from the same source before
+		if existing, err := store.GetLogBySource(record.Source); err == nil {
+			if recordKey(exchange.NewRecord(*existing)) == key && existing.Notes == record.Notes && existing.Distance == record.Distance {
+				result.Duplicates++
+				continue
+			}
+			result.Updated++
+			if dryRun {
+				continue
+			}
+
+			log := record.Log()
+			log.ID = existing.ID
+			log.HabitID = existing.HabitID
+			if err := store.UpdateLog(log); err != nil {
+				return result, nil, fmt.Errorf("failed to update log: %w", err)
+			}
+			continue
+		}
+
+		if store.HasLog(record.Habit, record.LoggedAt, record.Duration, record.Count) {
+			result.Duplicates++
+			continue
+		}
+
+		if _, err := store.GetHabitByName(record.Habit); err != nil {
+			newHabits[record.Habit] = true
+		}
+
+		added = append(added, record)
+		result.Added++
+		if dryRun {
+			continue
+		}
+
+		habit, err := store.GetOrCreateHabit(record.Habit)
+		if err != nil {
+			return result, nil, fmt.Errorf("failed to get/create habit: %w", err)
+		}
+
+		log := record.Log()
+		log.HabitID = habit.ID
+		if err := store.AddLogEntry(log); err != nil {
+			return result, nil, fmt.Errorf("failed to add log: %w", err)
+		}
+	}
+
+	for name := range newHabits {
+		result.NewHabits = append(result.NewHabits, name)
+	}
+	sort.Strings(result.NewHabits)
+
+	return result, added, nil
+}
+
+// recordKey identifies a record for duplicate detection within one import
+func recordKey(record exchange.Record) string {
+	minutes := 0
+	if parsed, err := parser.ParseDuration(record.Duration); err == nil {
+		minutes = parser.GetTotalMinutes(parsed)
+	}
+	return fmt.Sprintf("%s|%s|%d|%d|%d", record.Source, record.Habit, record.LoggedAt.UnixNano(), minutes, record.Count)
+}
+
+// displayImportResult shows what was (or would be) imported
+func displayImportResult(result importResult, preview []exchange.Record, dryRun bool) {
+	green := color.New(color.FgGreen, color.Bold)
+	cyan := color.New(color.FgCyan, color.Bold)
+
+	if dryRun {
+		cyan.Println("🔍 Dry run - nothing was saved")
+		for i, record := range preview {
+			if i == maxPreviewRows {
+				fmt.Printf("   ... and %d more\n", len(preview)-maxPreviewRows)
+				break
+			}
+			amount := record.Duration
+			if amount == "" {
+				amount = parser.FormatCount(record.Count)
+			}
+			if record.Distance > 0 {
+				amount += fmt.Sprintf(" · %.2f km", record.Distance)
+			}
+			fmt.Printf("   + %s %-12s %s\n", record.LoggedAt.Local().Format("2006-01-02 15:04"), record.Habit, amount)
+		}
+		green.Printf("✅ Would import %d logs", result.Added)
+	} else {
+		green.Printf("✅ Imported %d logs", result.Added)
+	}
+
+	if result.Updated > 0 {
+		fmt.Printf(", %d updated", result.Updated)
+	}
+	if result.Duplicates > 0 {
+		fmt.Printf(" (%d duplicates skipped)", result.Duplicates)
+	}
+	fmt.Println()
+
+	if len(result.NewHabits) > 0 {
+		fmt.Printf("🆕 New habits: %s\n", strings.Join(result.NewHabits, ", "))
+	}
+}
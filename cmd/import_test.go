@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// newTestStore returns a store in a temporary home directory
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+	s, err := store.NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	return s
+}
+
+func TestExportImportIsNoOp(t *testing.T) {
+	for _, format := range []string{"json", "ndjson", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			s := newTestStore(t)
+			loggedAt := time.Date(2025, 3, 14, 9, 26, 53, 589793238, time.Local)
+			logs := []types.Log{
+				{HabitName: "code", Duration: "1h30m", LoggedAt: loggedAt, StartedAt: loggedAt.Add(-90 * time.Minute), Notes: "refactor"},
+				{HabitName: "water", Count: 2, LoggedAt: loggedAt.Add(time.Hour + 987654321)},
+			}
+			for _, log := range logs {
+				habit, _ := s.GetOrCreateHabit(log.HabitName)
+				log.HabitID = habit.ID
+				if err := s.AddLogEntry(log); err != nil {
+					t.Fatalf("AddLogEntry: %v", err)
+				}
+			}
+
+			all, _ := s.GetAllLogs()
+			var buf bytes.Buffer
+			if err := exchange.Export(&buf, format, all); err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			rows, err := exchange.Parse(&buf, format)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			result, added, err := addRecords(s, rowRecords(rows), false)
+			if err != nil {
+				t.Fatalf("addRecords: %v", err)
+			}
+			if result.Added != 0 || len(added) != 0 || result.Duplicates != len(logs) {
+				t.Errorf("got %+v, want every log to be a duplicate", result)
+			}
+			if after, _ := s.GetAllLogs(); len(after) != len(logs) {
+				t.Errorf("store has %d logs after import, want %d", len(after), len(logs))
+			}
+		})
+	}
+}
+
+func TestHealthReimportIsNoOp(t *testing.T) {
+	tests := map[string]string{
+		"apple-health": filepath.Join("..", "importer", "testdata", "applehealth", "export.xml"),
+		"google-fit":   filepath.Join("..", "importer", "testdata", "googlefit"),
+	}
+	for source, path := range tests {
+		t.Run(source, func(t *testing.T) {
+			newTestStore(t)
+			logs := func() []types.Log {
+				t.Helper()
+				if err := runBackupImport(path, source, false); err != nil {
+					t.Fatalf("runBackupImport: %v", err)
+				}
+				s, err := store.NewStore()
+				if err != nil {
+					t.Fatalf("NewStore: %v", err)
+				}
+				logs, _ := s.GetAllLogs()
+				return logs
+			}
+
+			first := logs()
+			if len(first) == 0 {
+				t.Fatal("nothing was imported")
+			}
+			if again := logs(); !reflect.DeepEqual(again, first) {
+				t.Errorf("importing again changed the logs:\n%+v\nwant\n%+v", again, first)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/master-wayne7/lazytrack/activity"
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/importer"
+	"github.com/spf13/cobra"
+)
+
+// NewImportActivityCmd creates the import-activity command
+func NewImportActivityCmd() *cobra.Command {
+	var habit string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import-activity <file>...",
+		Short: "Log runs, walks and rides from GPX or FIT files",
+		Long: `Log runs, walks and rides recorded as GPX or FIT files.
+
+The distance, moving time and pace of each activity are computed from its
+track (or taken from the totals recorded by the device) and logged with the
+moving time as duration. Summaries show the total distance of the habit.
+
+Without --habit, the habit is picked from the activity type in the file
+(running → run, walking → walk, cycling → cycle). Importing a file again
+updates its log instead of duplicating it.
+
+Examples:
+  lazytrack import-activity run.gpx --habit run
+  lazytrack import-activity ~/Downloads/*.fit
+  lazytrack import-activity ride.gpx --habit cycle --dry-run`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImportActivity(args, habit, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&habit, "habit", "a", "", "Habit to log the activities as (default: from the activity type)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview the import without saving anything")
+	return cmd
+}
+
+// runImportActivity handles the import-activity command execution
+func runImportActivity(paths []string, habit string, dryRun bool) error {
+	habit = strings.ToLower(strings.TrimSpace(habit))
+
+	var records []exchange.Record
+	for _, path := range paths {
+		recorded, err := activity.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		record, err := activityRecord(recorded, habit)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		records = append(records, record)
+		if !dryRun {
+			fmt.Printf("🏃 %s %s: %s\n", record.StartedAt.Local().Format("2006-01-02 15:04"), record.Habit, record.Notes)
+		}
+	}
+
+	return importRecords(records, dryRun)
+}
+
+// activityRecord converts an activity to a record
+func activityRecord(recorded activity.Activity, habit string) (exchange.Record, error) {
+	stats := activity.Summarize(recorded)
+	if stats.Start.IsZero() {
+		return exchange.Record{}, fmt.Errorf("activity has no start time")
+	}
+
+	duration := importer.FormatMinutes(stats.Moving)
+	if duration == "0m" {
+		return exchange.Record{}, fmt.Errorf("activity is shorter than a minute")
+	}
+
+	if habit == "" {
+		habit = importer.WorkoutHabit(recorded.Sport)
+	}
+
+	details := []string{fmt.Sprintf("%.2f km", stats.Distance)}
+	if stats.Distance > 0 {
+		if habit == "cycle" {
+			details = append(details, fmt.Sprintf("%.1f km/h", stats.Speed()))
+		} else {
+			details = append(details, activity.FormatPace(stats.Pace()))
+		}
+	}
+	if recorded.Name != "" {
+		details = append([]string{recorded.Name}, details...)
+	}
+
+	end := stats.End
+	if end.Before(stats.Start) {
+		end = stats.Start.Add(stats.Moving)
+	}
+
+	return exchange.Record{
+		Habit:     habit,
+		StartedAt: stats.Start,
+		LoggedAt:  end,
+		Duration:  duration,
+		Distance:  importer.RoundDistance(stats.Distance),
+		Notes:     strings.Join(details, " · "),
+		Source:    fmt.Sprintf("activity:%d", stats.Start.Unix()),
+	}, nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/insights"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/spf13/cobra"
+)
+
+// NewInsightsCmd creates the insights command
+func NewInsightsCmd() *cobra.Command {
+	var rangeInput string
+	var minDays int
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "insights",
+		Short: "Find relationships between your habits",
+		Long: `Find relationships between your habits.
+
+Compares habits day by day and reports correlations and differences like
+"on days you meditate, you log 40% more code". Only relationships that are
+statistically meaningful over at least --min-days shared days are shown.
+Everything is computed locally from your logs.
+
+Examples:
+  lazytrack insights                 # Last 90 days
+  lazytrack insights --range 365d    # Last year
+  lazytrack insights --min-days 30   # Require more data
+  lazytrack insights --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInsights(rangeInput, minDays, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVarP(&rangeInput, "range", "r", "90d", "Range to analyze")
+	cmd.Flags().IntVarP(&minDays, "min-days", "m", 14, "Minimum number of shared days")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output insights as JSON")
+	return cmd
+}
+
+// runInsights handles the insights command execution
+func runInsights(rangeInput string, minDays int, jsonOutput bool) error {
+	startDate, endDate, err := parser.ParseRange(rangeInput, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Get all habits
+	habits, err := store.GetAllHabits()
+	if err != nil {
+		return fmt.Errorf("failed to get habits: %w", err)
+	}
+
+	if len(habits) == 0 {
+		displayEmptyState()
+		return nil
+	}
+
+	results := insights.Calculate(habits, loadLogsByHabit(store, habits, startDate, endDate), startDate, endDate, minDays)
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("🔍 Insights - %s to %s\n", startDate.Format("Jan 2, 2006"), endDate.AddDate(0, 0, -1).Format("Jan 2, 2006"))
+	cyan.Println(strings.Repeat("=", 50))
+
+	if len(results) == 0 {
+		fmt.Println("No meaningful relationships found yet. Keep logging and check back later!")
+		return nil
+	}
+
+	for _, insight := range results {
+		fmt.Println(insight.Message)
+	}
+
+	return nil
+}
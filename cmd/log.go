@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/dailynote"
+	"github.com/master-wayne7/lazytrack/message"
 	"github.com/master-wayne7/lazytrack/parser"
 	"github.com/master-wayne7/lazytrack/store"
 	"github.com/master-wayne7/lazytrack/summary"
@@ -42,7 +45,7 @@ func runLog(args []string, notes string) error {
 	habitName := strings.ToLower(strings.TrimSpace(args[0]))
 
 	// Initialize store
-	store, err := store.NewStore()
+	store, err := store.NewLockedStore()
 	if err != nil {
 		return fmt.Errorf("failed to initialize store: %w", err)
 	}
@@ -101,52 +104,34 @@ func runLog(args []string, notes string) error {
 
 	// Success notification removed - only show console output
 
+	// Add the log to today's daily note, if one is configured
+	if _, err := updateDailyNote(store, time.Now(), dailynote.LogSection); err != nil {
+		fmt.Printf("⚠️  Failed to update daily note: %v\n", err)
+	}
+
 	// Check if goal is reached (console output only)
 	checkAndShowGoalMessage(store, habit)
 
 	// Display success message
-	displaySuccessMessage(habit, duration, count, isCountBased)
+	displaySuccessMessage(store, habit, duration, count, isCountBased)
 
 	return nil
 }
 
 // displaySuccessMessage shows a colorful success message
-func displaySuccessMessage(habit *types.Habit, duration string, count int, isCountBased bool) {
-	// Try color output first, fallback to regular if it fails
-	green := color.New(color.FgGreen, color.Bold)
-	cyan := color.New(color.FgCyan, color.Bold)
-
-	// Use a more robust approach - check if we're in a terminal
-	if !color.NoColor {
-		green.Print("✅ Logged ")
-		cyan.Printf("\"%s\"", habit.Name)
-
-		if isCountBased {
-			green.Printf(" for %s", parser.FormatCount(count))
-		} else {
-			green.Printf(" for %s", duration)
-		}
-
-		green.Println()
-	} else {
-		// Fallback to regular output
-		fmt.Print("✅ Logged ")
-		fmt.Printf("\"%s\"", habit.Name)
-
-		if isCountBased {
-			fmt.Printf(" for %s", parser.FormatCount(count))
-		} else {
-			fmt.Printf(" for %s", duration)
-		}
-
-		fmt.Println()
+func displaySuccessMessage(store *store.Store, habit *types.Habit, duration string, count int, isCountBased bool) {
+	data := habitMessageData(store, *habit, time.Now())
+	data.Logged = duration
+	if isCountBased {
+		data.Logged = parser.FormatCount(count)
 	}
 
-	// Show emoji and habit name
-	fmt.Printf("%s %s\n", habit.Emoji, habit.Name)
+	green := color.New(color.FgGreen, color.Bold)
+	green.Println(message.Render(message.Logged, data))
 }
 
-// checkAndShowGoalMessage checks if today's goal is reached and shows console message only
+// checkAndShowGoalMessage checks if today's goal is reached and shows console message only,
+// ringing the bell if sounds are on
 func checkAndShowGoalMessage(store *store.Store, habit *types.Habit) {
 	// Get today's logs for this habit
 	today := time.Now().Truncate(24 * time.Hour)
@@ -160,15 +145,28 @@ func checkAndShowGoalMessage(store *store.Store, habit *types.Habit) {
 	// Check if goal is reached
 	if summary.IsGoalReached(*habit, logs) {
 		// Show goal reached message (console only, no notification)
+		text := message.Render(message.GoalReached, habitMessageData(store, *habit, time.Now()))
 		if !color.NoColor {
 			yellow := color.New(color.FgYellow, color.Bold)
-			yellow.Printf("🎉 Goal reached for %s today!\n", habit.Name)
+			yellow.Println(text)
 		} else {
-			fmt.Printf("🎉 Goal reached for %s today!\n", habit.Name)
+			fmt.Println(text)
+		}
+
+		// Ring the terminal bell, unless sounds or the habit's notifications are off
+		settings := store.GetSettings()
+		if settings.SoundEnabled && notificationsOff(settings, habit.Name) == "" && isTerminal(os.Stdout) {
+			fmt.Print("\a")
 		}
 	}
 }
 
+// isTerminal checks if a file is a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 // LogHabit is a convenience function for logging habits programmatically
 func LogHabit(habitName, duration string, notes string) error {
 	args := []string{habitName}
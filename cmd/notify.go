@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/notification"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// notifiersFileName is the file the notification backends are configured in,
+// in the data directory (notifiers.json)
+const notifiersFileName = "notifiers"
+
+// notifyTimeout bounds how long sending a notification may take
+const notifyTimeout = 30 * time.Second
+
+// NewNotifyCmd creates the notify command
+func NewNotifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Show and test the notification backends",
+		Long: `Show and test where notifications are sent.
+
+By default reminders pop up on the desktop. To send them elsewhere too, list
+backends in ~/.lazytrack/notifiers.json and route notification types
+("reminder" for habit reminders, "late" for the late reminder, or "default")
+to them:
+
+  {
+    "backends": {
+      "desktop": {"type": "desktop"},
+      "phone":   {"type": "ntfy", "url": "https://ntfy.sh/my-topic"},
+      "mail":    {"type": "smtp", "host": "smtp.example.com:587", "username": "me",
+                  "password": "...", "from": "me@example.com", "to": ["me@example.com"]}
+    },
+    "routes": {"reminder": ["desktop"], "late": ["desktop", "phone", "mail"]}
+  }
+
+Backend types: desktop, terminal (with "bell"), webhook (JSON POST to "url",
+with optional "headers"), ntfy ("url", "token", "priority"), gotify ("url",
+"token", "priority"), smtp and command (runs "command" with the notification
+as JSON on stdin and in LAZYTRACK_* environment variables).
+
+Examples:
+  lazytrack notify list
+  lazytrack notify test
+  lazytrack notify test --type late
+  lazytrack notify test --via phone`,
+	}
+
+	cmd.AddCommand(newNotifyListCmd(), newNotifyTestCmd())
+	return cmd
+}
+
+// newNotifyListCmd creates the notify list command
+func newNotifyListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the notification backends and routes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNotifyList()
+		},
+	}
+}
+
+// newNotifyTestCmd creates the notify test command
+func newNotifyTestCmd() *cobra.Command {
+	var notificationType string
+	var via []string
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Send a test notification",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNotifyTest(cmd.Context(), notificationType, via)
+		},
+	}
+
+	cmd.Flags().StringVarP(&notificationType, "type", "t", "", "Send to the backends of a type (reminder or late) instead of all")
+	cmd.Flags().StringSliceVar(&via, "via", nil, "Send to these backends only")
+	return cmd
+}
+
+// runNotifyList handles the notify list command execution
+func runNotifyList() error {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	cfg, err := loadNotifierConfig(store)
+	if err != nil {
+		return err
+	}
+	dispatcher, err := notification.New(cfg)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Backends) == 0 {
+		cfg = notification.DefaultConfig()
+	}
+
+	settings := store.GetSettings()
+	if reason := notificationsOff(settings, ""); reason != "" {
+		fmt.Printf("🔕 Reminders aren't sent: %s\n\n", reason)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("🔔 Notification backends (%s)\n", filepath.Join(store.DataDir(), notifiersFileName+".json"))
+	for _, name := range dispatcher.Backends() {
+		backend := cfg.Backends[name]
+		target := backend.URL
+		switch backend.Type {
+		case "smtp":
+			target = backend.Host + " → " + strings.Join(backend.To, ", ")
+		case "command":
+			target = strings.Join(backend.Command, " ")
+		}
+		if slices.Contains(settings.DisabledNotifiers, name) {
+			target += " (disabled)"
+		}
+		fmt.Printf("  %-12s %-9s %s\n", name, backend.Type, target)
+	}
+
+	fmt.Println()
+	cyan.Println("Routes:")
+	for _, notificationType := range []string{notification.TypeReminder, notification.TypeLate} {
+		fmt.Printf("  %-12s → %s\n", notificationType, strings.Join(dispatcher.Route(notificationType), ", "))
+	}
+	return nil
+}
+
+// runNotifyTest handles the notify test command execution
+func runNotifyTest(ctx context.Context, notificationType string, via []string) error {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	dispatcher, err := getDispatcher(store)
+	if err != nil {
+		return err
+	}
+
+	// Backends named with --via are tested even if they're turned off
+	settings := store.GetSettings()
+	names := via
+	if len(names) == 0 && notificationType != "" {
+		names = enabledBackends(settings, dispatcher.Route(notificationType))
+	} else if len(names) == 0 {
+		names = enabledBackends(settings, dispatcher.Backends())
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("all notification backends are turned off")
+	}
+	if reason := notificationsOff(settings, ""); reason != "" {
+		fmt.Printf("🔕 Reminders aren't sent: %s\n", reason)
+	}
+	if notificationType == "" {
+		notificationType = notification.TypeTest
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	err = dispatcher.SendVia(ctx, notification.Notification{
+		Type:    notificationType,
+		Title:   "LazyTrack Test",
+		Message: "Notifications from LazyTrack work! 🎉",
+		Silent:  !settings.SoundEnabled,
+	}, names)
+
+	failed := make(map[string]error)
+	for _, failure := range notification.BackendErrors(err) {
+		failed[failure.Backend] = failure.Err
+	}
+	sort.Strings(names)
+	green := color.New(color.FgGreen, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+	for _, name := range names {
+		if err, ok := failed[name]; ok {
+			red.Printf("❌ %s: %v\n", name, err)
+		} else {
+			green.Printf("✅ %s\n", name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d notification backends failed", len(failed), len(names))
+	}
+	return nil
+}
+
+// loadNotifierConfig loads the notification backends, which are empty if
+// none are configured
+func loadNotifierConfig(store *store.Store) (notification.Config, error) {
+	var cfg notification.Config
+	if err := store.LoadState(notifiersFileName, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// getDispatcher returns the dispatcher of the configured notification backends
+func getDispatcher(store *store.Store) (*notification.Dispatcher, error) {
+	cfg, err := loadNotifierConfig(store)
+	if err != nil {
+		return nil, err
+	}
+	return notification.New(cfg)
+}
+
+// sendNotification sends a notification to its enabled backends, unless
+// notifications are disabled or the habit it's about is muted. Sending gives
+// up when ctx is done or after notifyTimeout.
+func sendNotification(ctx context.Context, store *store.Store, n notification.Notification) error {
+	settings := store.GetSettings()
+	if notificationsOff(settings, n.Habit) != "" {
+		return nil
+	}
+	n.Silent = !settings.SoundEnabled
+
+	dispatcher, err := getDispatcher(store)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	return dispatcher.SendVia(ctx, n, enabledBackends(settings, dispatcher.Route(n.Type)))
+}
+
+// notificationsOff returns why notifications about a habit (or about no
+// habit in particular, for "") aren't sent, or "" if they are
+func notificationsOff(settings types.Config, habitName string) string {
+	if !notification.IsNotificationEnabled(settings) {
+		return "notifications are disabled"
+	}
+	if habitName != "" && notification.IsHabitMuted(settings, habitName) {
+		return habitName + " is muted"
+	}
+	return ""
+}
+
+// enabledBackends returns the backends that aren't turned off
+func enabledBackends(settings types.Config, names []string) []string {
+	var enabled []string
+	for _, name := range names {
+		if !slices.Contains(settings.DisabledNotifiers, name) {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled
+}
+
+// unmutedHabits returns the habits whose notifications aren't muted
+func unmutedHabits(settings types.Config, habitNames []string) []string {
+	var unmuted []string
+	for _, name := range habitNames {
+		if !notification.IsHabitMuted(settings, name) {
+			unmuted = append(unmuted, name)
+		}
+	}
+	return unmuted
+}
+
+// printNotificationErrors prints the failure of each backend to send a
+// notification
+func printNotificationErrors(what string, err error) {
+	failures := notification.BackendErrors(err)
+	if len(failures) == 0 {
+		fmt.Printf("⚠️  %s notification failed: %v\n", what, err)
+		return
+	}
+	for _, failure := range failures {
+		fmt.Printf("⚠️  %s via %s failed: %v\n", what, failure.Backend, failure.Err)
+	}
+}
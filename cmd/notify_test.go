@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/notification"
+)
+
+func TestSendNotificationStopsWithContext(t *testing.T) {
+	s := newTestStore(t)
+	// The backend doesn't answer until the test ends, so only ctx ends the send
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := notification.Config{
+		Backends: map[string]notification.BackendConfig{"hook": {Type: "webhook", URL: server.URL}},
+		Routes:   map[string][]string{"default": {"hook"}},
+	}
+	if err := s.SaveState(notifiersFileName, cfg); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err := sendNotification(ctx, s, notification.Notification{Type: notification.TypeTest, Title: "Test", Message: "Test"})
+	if err == nil {
+		t.Fatal("sendNotification succeeded, want the backend to time out")
+	}
+	if elapsed := time.Since(started); elapsed > notifyTimeout/2 {
+		t.Errorf("sendNotification took %s after ctx was done, want it to stop with ctx", elapsed)
+	}
+}
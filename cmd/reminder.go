@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/master-wayne7/lazytrack/notification"
 	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
 	"github.com/spf13/cobra"
 )
 
@@ -18,23 +21,24 @@ func NewReminderCmd() *cobra.Command {
 
 This command checks all your habits and shows notifications for:
 - Pending goals that haven't been reached yet
-- Late reminders when it's getting close to 8 PM
+- Late reminders after the late-reminder time (20:00 unless set with
+  'lazytrack config --late-reminder 21:30')
 
 Examples:
   lazytrack reminder          # Check all pending goals
   lazytrack reminder --late   # Show late reminder only`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			lateOnly, _ := cmd.Flags().GetBool("late")
-			return runReminder(lateOnly)
+			return runReminder(cmd.Context(), lateOnly)
 		},
 	}
 
-	cmd.Flags().BoolP("late", "l", false, "Show late reminder only (after 8 PM)")
+	cmd.Flags().BoolP("late", "l", false, "Show late reminder only (after the late-reminder time)")
 	return cmd
 }
 
 // runReminder handles the reminder command execution
-func runReminder(lateOnly bool) error {
+func runReminder(ctx context.Context, lateOnly bool) error {
 	// Initialize store
 	store, err := store.NewStore()
 	if err != nil {
@@ -50,10 +54,12 @@ func runReminder(lateOnly bool) error {
 
 	// Get current time
 	now := time.Now()
-	currentHour := now.Hour()
 
-	// Check if it's late (after 8 PM)
-	isLate := currentHour >= 20
+	// Check if it's late (after the late-reminder time)
+	isLate, err := isPastLateReminder(store, now)
+	if err != nil {
+		return err
+	}
 
 	var pendingHabits []string
 	var pendingHabitsWithProgress []string
@@ -61,40 +67,15 @@ func runReminder(lateOnly bool) error {
 	// Check each habit for pending goals
 	for _, habit := range habits {
 		if habit.DailyGoal == 0 {
-			continue // Skip habits without goals
-		}
-
-		// Get today's logs for this habit
-		today := now.Truncate(24 * time.Hour)
-		tomorrow := today.AddDate(0, 0, 1)
-
-		logs, err := store.GetLogsByHabit(habit.Name, today, tomorrow)
-		if err != nil {
-			continue // Skip if we can't get logs
-		}
-
-		// Calculate current progress
-		var currentProgress int
-		if habit.GoalType == "count" {
-			for _, log := range logs {
-				currentProgress += log.Count
-			}
-		} else {
-			// For duration-based habits, convert to minutes
-			for _, log := range logs {
-				if log.Duration != "" {
-					// Parse duration and add to progress
-					// This is a simplified version - you might want to use the parser
-					currentProgress += 30 // Default 30 minutes for now
-				}
-			}
+			continue // Skip habits without daily goals
 		}
 
 		// Check if goal is not reached
-		if currentProgress < habit.DailyGoal {
+		progress, goal := dayProgress(store, habit, now)
+		if progress < goal {
 			pendingHabits = append(pendingHabits, habit.Name)
 			pendingHabitsWithProgress = append(pendingHabitsWithProgress,
-				fmt.Sprintf("%s (%d/%d)", habit.Name, currentProgress, habit.DailyGoal))
+				fmt.Sprintf("%s (%s/%s)", habit.Name, summary.FormatAmount(habit.GoalType, progress), summary.FormatAmount(habit.GoalType, goal)))
 		}
 	}
 
@@ -102,12 +83,16 @@ func runReminder(lateOnly bool) error {
 	if len(pendingHabits) > 0 {
 		if lateOnly && isLate {
 			// Show late reminder
-			if notification.IsNotificationEnabled() {
-				if err := notification.ShowLateReminder(pendingHabits); err != nil {
-					fmt.Printf("⚠️  Late reminder notification failed: %v\n", err)
+			paceHints := getPaceHints(store, pendingHabits, now)
+			if unmuted := unmutedHabits(store.GetSettings(), pendingHabits); len(unmuted) > 0 {
+				if err := sendNotification(ctx, store, notification.LateReminder(unmuted, getPaceHints(store, unmuted, now))); err != nil {
+					printNotificationErrors("Late reminder", err)
 				}
 			}
 			fmt.Printf("🌙 Late reminder: You still have pending goals: %s\n", joinHabits(pendingHabits))
+			if len(paceHints) > 0 {
+				fmt.Printf("🔮 You need %s today to stay on pace\n", joinHabits(paceHints))
+			}
 		} else if !lateOnly {
 			// Show general reminder for each pending habit
 			for _, habitName := range pendingHabits {
@@ -116,31 +101,8 @@ func runReminder(lateOnly bool) error {
 					continue
 				}
 
-				// Get progress for this specific habit
-				today := now.Truncate(24 * time.Hour)
-				tomorrow := today.AddDate(0, 0, 1)
-				logs, err := store.GetLogsByHabit(habitName, today, tomorrow)
-				if err != nil {
-					continue
-				}
-
-				var currentProgress int
-				if habit.GoalType == "count" {
-					for _, log := range logs {
-						currentProgress += log.Count
-					}
-				} else {
-					for _, log := range logs {
-						if log.Duration != "" {
-							currentProgress += 30 // Simplified
-						}
-					}
-				}
-
-				if notification.IsNotificationEnabled() {
-					if err := notification.ShowGoalReminder(habitName, currentProgress, habit.DailyGoal, habit.GoalType); err != nil {
-						fmt.Printf("⚠️  Goal reminder notification failed: %v\n", err)
-					}
+				if err := sendNotification(ctx, store, notification.GoalReminder(habitMessageData(store, *habit, now))); err != nil {
+					printNotificationErrors("Goal reminder", err)
 				}
 			}
 			fmt.Printf("📋 Pending goals: %s\n", joinHabits(pendingHabitsWithProgress))
@@ -154,6 +116,19 @@ func runReminder(lateOnly bool) error {
 	return nil
 }
 
+// dayProgress returns how much of a habit was logged on now's day, counted
+// from local midnight, and the habit's goal for the day
+func dayProgress(store *store.Store, habit types.Habit, now time.Time) (float64, float64) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	logs, _ := store.GetLogsByHabit(habit.Name, today, today.AddDate(0, 0, 1))
+
+	var progress float64
+	for _, log := range logs {
+		progress += summary.LogValue(habit, log)
+	}
+	return progress, summary.PeriodGoal(habit, 1)
+}
+
 // joinHabits joins habit names with commas (duplicate of sound package, but needed here)
 func joinHabits(habits []string) string {
 	if len(habits) == 0 {
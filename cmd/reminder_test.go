@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+func TestDayProgress(t *testing.T) {
+	s := newTestStore(t)
+	// Local midnight isn't UTC midnight here
+	zone := time.FixedZone("UTC+9", 9*60*60)
+	now := time.Date(2026, 10, 17, 21, 0, 0, 0, zone)
+
+	code, _ := s.GetOrCreateHabit("code")
+	code.GoalType, code.DailyGoal = "duration", 2
+	water, _ := s.GetOrCreateHabit("water")
+	water.GoalType, water.DailyGoal = "count", 8
+	logs := []types.Log{
+		{HabitName: "code", Duration: "1h", Count: 1, LoggedAt: time.Date(2026, 10, 17, 7, 0, 0, 0, zone)},
+		{HabitName: "code", Duration: "15m", LoggedAt: time.Date(2026, 10, 17, 20, 0, 0, 0, zone)},
+		{HabitName: "code", Duration: "3h", LoggedAt: time.Date(2026, 10, 16, 23, 0, 0, 0, zone)},
+		{HabitName: "water", Count: 8, LoggedAt: time.Date(2026, 10, 17, 8, 0, 0, 0, zone)},
+	}
+	for _, log := range logs {
+		if err := s.AddLogEntry(log); err != nil {
+			t.Fatalf("AddLogEntry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		habit          types.Habit
+		progress, goal float64
+	}{
+		// Durations count their hours, not a log each
+		{*code, 1.25, 2},
+		{*water, 8, 8},
+	}
+	for _, test := range tests {
+		progress, goal := dayProgress(s, test.habit, now)
+		if progress != test.progress || goal != test.goal {
+			t.Errorf("%s: got %v of %v, want %v of %v", test.habit.Name, progress, goal, test.progress, test.goal)
+		}
+	}
+}
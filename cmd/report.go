@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/report"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/spf13/cobra"
+)
+
+// NewReportCmd creates the report command
+func NewReportCmd() *cobra.Command {
+	var htmlPath string
+	var markdown bool
+	var outPath string
+	var rangeInput string
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate a shareable report",
+		Long: `Generate a shareable report for a period.
+
+The HTML report is a single self-contained file (inline CSS and SVG charts)
+with per-habit charts, heatmaps, streaks and notes. The Markdown report has a
+habit table with goal status and streaks, followed by your notes.
+
+Examples:
+  lazytrack report --html report.html                    # This week
+  lazytrack report --html out.html --range last-month
+  lazytrack report --markdown                            # Markdown to stdout
+  lazytrack report --markdown --out week.md --range last-week`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReport(htmlPath, markdown, outPath, rangeInput)
+		},
+	}
+
+	cmd.Flags().StringVar(&htmlPath, "html", "", "Write an HTML report to this file")
+	cmd.Flags().BoolVarP(&markdown, "markdown", "m", false, "Write a Markdown report")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "", "Markdown output file (default: stdout)")
+	cmd.Flags().StringVarP(&rangeInput, "range", "r", "this-week", "Period to report on")
+	return cmd
+}
+
+// runReport handles the report command execution
+func runReport(htmlPath string, markdown bool, outPath, rangeInput string) error {
+	if htmlPath == "" && !markdown {
+		return fmt.Errorf("no output selected (use --html <file> or --markdown)")
+	}
+
+	startDate, endDate, err := parser.ParseRange(rangeInput, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Get all habits
+	habits, err := store.GetAllHabits()
+	if err != nil {
+		return fmt.Errorf("failed to get habits: %w", err)
+	}
+
+	data := report.Build(habits, loadLogsByHabit(store, habits, startDate, endDate), startDate, endDate)
+
+	if htmlPath != "" {
+		if err := writeReportFile(htmlPath, data, report.WriteHTML); err != nil {
+			return err
+		}
+	}
+
+	if markdown {
+		if outPath == "" {
+			return report.WriteMarkdown(os.Stdout, data)
+		}
+		if err := writeReportFile(outPath, data, report.WriteMarkdown); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeReportFile writes a report to a file using the given writer
+func writeReportFile(path string, data report.Report, write func(io.Writer, report.Report) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+
+	if err := write(file, data); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ Report written to %s\n", path)
+	return nil
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/heartbeat"
+	"github.com/master-wayne7/lazytrack/importer"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// wakatimeSourcePrefix marks logs created from heartbeats
+const wakatimeSourcePrefix = "wakatime:"
+
+// NewServeCmd creates the serve command
+func NewServeCmd() *cobra.Command {
+	var addr string
+	var habit string
+	var mappingPath string
+	var apiKey string
+	var idleTimeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Accept WakaTime heartbeats from your editors",
+		Long: `Run a local server that accepts WakaTime-style heartbeats from editor plugins.
+
+Heartbeats are grouped into sessions per project and language; a pause longer
+than --idle starts a new session. Sessions are logged as they grow, to the
+habit given by --map (keyed by project or language) or to --habit.
+
+Point your editors at the server in ~/.wakatime.cfg:
+
+  [settings]
+  api_url = http://127.0.0.1:5152/api/v1
+  api_key = <the --key value, or any key if --key is not set>
+
+Examples:
+  lazytrack serve
+  lazytrack serve --addr 127.0.0.1:5152 --habit code --idle 10m
+  lazytrack serve --map editors.json --key 2f1c6a8e-7d1e-4c0a-9b1e-3b7f6c2d9a10`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(addr, habit, mappingPath, apiKey, idleTimeout)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:5152", "Address to listen on")
+	cmd.Flags().StringVarP(&habit, "habit", "a", "code", "Habit for sessions without a mapping")
+	cmd.Flags().StringVarP(&mappingPath, "map", "m", "", `JSON file mapping projects/languages to habits, e.g. {"blog": "write"}`)
+	cmd.Flags().StringVar(&apiKey, "key", "", "API key clients must send (default: accept any)")
+	cmd.Flags().DurationVar(&idleTimeout, "idle", heartbeat.DefaultIdleTimeout, "Pause after which a new session starts")
+	return cmd
+}
+
+// runServe handles the serve command execution
+func runServe(addr, habit, mappingPath, apiKey string, idleTimeout time.Duration) error {
+	mapper := &importer.Mapper{DefaultHabit: strings.ToLower(strings.TrimSpace(habit))}
+	if mappingPath != "" {
+		var err error
+		if mapper.Mapping, err = importer.LoadMapping(mappingPath); err != nil {
+			return err
+		}
+	}
+
+	// The server saves sessions one request at a time, in the order they changed
+	server := &heartbeat.Server{
+		Aggregator: heartbeat.NewAggregator(idleTimeout),
+		APIKey:     apiKey,
+		Sink: func(sessions []heartbeat.Session) error {
+			return saveSessions(sessions, mapper)
+		},
+		Today: heartbeatTimeToday,
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("🛰️  Listening for heartbeats at http://%s/api/v1\n", addr)
+	fmt.Println("💡 Press Ctrl+C to stop")
+
+	return http.ListenAndServe(addr, server)
+}
+
+// saveSessions logs heartbeat sessions, updating the logs of sessions that
+// grew and removing those of sessions merged into others
+func saveSessions(sessions []heartbeat.Session, mapper *importer.Mapper) error {
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	for _, session := range sessions {
+		if session.MergedInto != "" {
+			// The log of the session it was merged into covers it now
+			if existing, err := store.GetLogBySource(wakatimeSourcePrefix + session.ID); err == nil {
+				if err := store.DeleteLog(existing.ID); err != nil {
+					return fmt.Errorf("failed to remove merged session: %w", err)
+				}
+			}
+			continue
+		}
+
+		duration := importer.FormatMinutes(session.Duration())
+		if duration == "0m" {
+			continue // logged once it lasts a minute
+		}
+
+		habitName, ok := mapper.Habit(importer.Entry{Project: session.Project, Tags: []string{session.Language}})
+		if !ok {
+			continue
+		}
+		habit, err := store.GetOrCreateHabit(habitName)
+		if err != nil {
+			return fmt.Errorf("failed to get/create habit: %w", err)
+		}
+
+		notes := session.Project
+		if session.Language != "" {
+			notes += " (" + session.Language + ")"
+		}
+		log := types.Log{
+			HabitID:   habit.ID,
+			HabitName: habit.Name,
+			Duration:  duration,
+			StartedAt: session.Start,
+			LoggedAt:  session.End,
+			Notes:     notes,
+			Source:    wakatimeSourcePrefix + session.ID,
+		}
+
+		if existing, err := store.GetLogBySource(log.Source); err == nil {
+			if existing.Duration == log.Duration && existing.StartedAt.Equal(log.StartedAt) && existing.LoggedAt.Equal(log.LoggedAt) {
+				continue // only heartbeats within the session, which don't change its log
+			}
+			log.ID = existing.ID
+			err = store.UpdateLog(log)
+		} else {
+			err = store.AddLogEntry(log)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+
+		fmt.Printf("💓 %s %s: %s\n", time.Now().Format("15:04"), notes, duration)
+	}
+
+	return nil
+}
+
+// heartbeatTimeToday returns the time logged from heartbeats today
+func heartbeatTimeToday() (time.Duration, error) {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	logs, err := store.GetAllLogs()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var total time.Duration
+	for _, log := range logs {
+		if !strings.HasPrefix(log.Source, wakatimeSourcePrefix) || log.LoggedAt.Before(today) {
+			continue
+		}
+		if parsed, err := parser.ParseDuration(log.Duration); err == nil {
+			total += time.Duration(parser.GetTotalMinutes(parsed)) * time.Minute
+		}
+	}
+	return total, nil
+}
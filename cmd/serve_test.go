@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/heartbeat"
+	"github.com/master-wayne7/lazytrack/importer"
+	"github.com/master-wayne7/lazytrack/store"
+)
+
+func TestSaveSessions(t *testing.T) {
+	s := newTestStore(t)
+	logsPath := filepath.Join(s.DataDir(), "logs.json")
+	mapper := &importer.Mapper{DefaultHabit: "code"}
+	aggregator := heartbeat.NewAggregator(15 * time.Minute)
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	add := func(minutes ...int) {
+		t.Helper()
+		var heartbeats []heartbeat.Heartbeat
+		for _, m := range minutes {
+			heartbeats = append(heartbeats, heartbeat.Heartbeat{
+				Entity: "main.go", Project: "lazytrack", Language: "Go",
+				Time: float64(start.Add(time.Duration(m) * time.Minute).Unix()),
+			})
+		}
+		if err := saveSessions(aggregator.Add(heartbeats), mapper); err != nil {
+			t.Fatalf("saveSessions: %v", err)
+		}
+	}
+	// durations returns the logged durations by source
+	durations := func() map[string]string {
+		t.Helper()
+		s, err := store.NewStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		logs, _ := s.GetAllLogs()
+		result := make(map[string]string)
+		for _, log := range logs {
+			result[log.Source] = log.Duration
+		}
+		return result
+	}
+	first := "wakatime:lazytrack|go|" + strconv.FormatInt(start.Unix(), 10)
+	second := "wakatime:lazytrack|go|" + strconv.FormatInt(start.Add(35*time.Minute).Unix(), 10)
+
+	add(0, 10)
+	add(35, 50)
+	if got := durations(); len(got) != 2 || got[first] != "10m" || got[second] != "15m" {
+		t.Fatalf("logs = %v, want 10m and 15m", got)
+	}
+
+	// A heartbeat within a session doesn't change its log, so nothing is saved
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(logsPath, old, old)
+	before, _ := os.Stat(logsPath)
+	add(5)
+	if after, _ := os.Stat(logsPath); !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("logs were saved without changes")
+	}
+
+	// A heartbeat bridging the sessions joins their logs
+	add(22)
+	if got := durations(); len(got) != 1 || got[first] != "50m" {
+		t.Errorf("logs = %v, want one of 50m", got)
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/spf13/cobra"
+)
+
+// newConfigSetCmd creates the config set command
+func newConfigSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a setting",
+		Long: `Change a setting (see lazytrack config list).
+
+Examples:
+  lazytrack config set notifications.enabled false
+  lazytrack config set sound.enabled off
+  lazytrack config set habits.water.muted true
+  lazytrack config set notifiers.phone.enabled false`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigSet(args[0], args[1])
+		},
+	}
+}
+
+// newConfigGetCmd creates the config get command
+func newConfigGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Show a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigGet(args[0])
+		},
+	}
+}
+
+// newConfigListCmd creates the config list command
+func newConfigListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the settings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigList()
+		},
+	}
+}
+
+// runConfigSet handles the config set command execution
+func runConfigSet(key, value string) error {
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Backends are configured in notifiers.json, which the store doesn't check
+	if name, ok := strings.CutPrefix(key, "notifiers."); ok {
+		name = strings.TrimSuffix(name, ".enabled")
+		dispatcher, err := getDispatcher(store)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(dispatcher.Backends(), name) {
+			return fmt.Errorf("unknown notification backend: %s (see lazytrack notify list)", name)
+		}
+	}
+
+	if err := store.SetSetting(key, value); err != nil {
+		return err
+	}
+	value, _ = store.GetSetting(key)
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("✅ %s = %s\n", key, value)
+	return nil
+}
+
+// runConfigGet handles the config get command execution
+func runConfigGet(key string) error {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	value, err := store.GetSetting(key)
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+// runConfigList handles the config list command execution
+func runConfigList() error {
+	definitions := store.Settings
+
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("⚙️  Settings")
+	listed := make(map[string]bool)
+	for _, setting := range definitions {
+		listed[setting.Key] = true
+		if strings.Contains(setting.Key, "*") {
+			fmt.Printf("  %-28s %-6s %s (default %s)\n", setting.Key, "", setting.Description, setting.Default)
+			continue
+		}
+		value, _ := store.GetSetting(setting.Key)
+		fmt.Printf("  %-28s %-6s %s\n", setting.Key, value, setting.Description)
+	}
+
+	// The settings of single habits and backends
+	values := store.GetSettingValues()
+	var keys []string
+	for key := range values {
+		if !listed[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		fmt.Println()
+		for _, key := range keys {
+			fmt.Printf("  %-28s %s\n", key, values[key])
+		}
+	}
+	return nil
+}
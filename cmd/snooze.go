@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/reminder"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/spf13/cobra"
+)
+
+// defaultSnooze is how long a habit is snoozed unless given
+const defaultSnooze = "30m"
+
+// NewSnoozeCmd creates the snooze command
+func NewSnoozeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snooze <habit> [duration]",
+		Short: "Snooze a habit's reminders",
+		Long: `Snooze a habit's reminders. The daemon sends no reminders for the habit (and
+leaves it out of the late reminder) until the snooze ends, then reminds you
+once more.
+
+Examples:
+  lazytrack snooze water        # Snooze for 30 minutes
+  lazytrack snooze water 2h`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			duration := defaultSnooze
+			if len(args) == 2 {
+				duration = args[1]
+			}
+			return runSnooze(args[0], duration)
+		},
+	}
+
+	return cmd
+}
+
+// NewDismissCmd creates the dismiss command
+func NewDismissCmd() *cobra.Command {
+	var undo bool
+
+	cmd := &cobra.Command{
+		Use:   "dismiss <habit>",
+		Short: "Dismiss a habit's reminders for today",
+		Long: `Dismiss a habit's reminders for the rest of today. They start again tomorrow.
+
+Examples:
+  lazytrack dismiss water
+  lazytrack dismiss water --undo   # Clear a dismissal or snooze`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDismiss(args[0], undo)
+		},
+	}
+
+	cmd.Flags().BoolVar(&undo, "undo", false, "Clear the habit's dismissal or snooze")
+	return cmd
+}
+
+// runSnooze handles the snooze command execution
+func runSnooze(habitName, duration string) error {
+	snooze, err := time.ParseDuration(duration)
+	if err != nil || snooze < time.Minute {
+		return fmt.Errorf("invalid snooze duration: %s (use e.g. 30m or 2h)", duration)
+	}
+
+	until := time.Now().Add(snooze)
+	habit, err := updateReminderState(habitName, func(state *reminder.State, habit string) {
+		state.Snooze(habit, until)
+	})
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("😴 %s reminders snoozed until %s\n", habit, until.Format("15:04"))
+	return nil
+}
+
+// runDismiss handles the dismiss command execution
+func runDismiss(habitName string, undo bool) error {
+	now := time.Now()
+	habit, err := updateReminderState(habitName, func(state *reminder.State, habit string) {
+		if undo {
+			state.Clear(habit)
+		} else {
+			state.Dismiss(habit, now)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	if undo {
+		green.Printf("🔔 %s reminders are back on\n", habit)
+	} else {
+		green.Printf("🔕 %s reminders dismissed for today\n", habit)
+	}
+	return nil
+}
+
+// updateReminderState changes the reminder state of an existing habit and
+// returns the habit's name
+func updateReminderState(habitName string, update func(state *reminder.State, habit string)) (string, error) {
+	// Lock the store, so the daemon doesn't change the state in between
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	habit, err := store.GetHabitByName(habitName)
+	if err != nil {
+		return "", err
+	}
+
+	state := reminder.NewState()
+	if err := store.LoadState(reminder.StateName, state); err != nil {
+		return "", err
+	}
+	update(state, habit.Name)
+	if err := store.SaveState(reminder.StateName, state); err != nil {
+		return "", err
+	}
+	return habit.Name, nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/stats"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// NewStatsCmd creates the stats command
+func NewStatsCmd() *cobra.Command {
+	var rangeInput string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "stats [habit]",
+		Short: "Show detailed statistics for your habits",
+		Long: `Show detailed statistics for your habits.
+
+Reports session length distribution, sessions per day, the most productive
+weekday, when you usually log, completion rates over 30/90/365 days and
+your best and worst weeks.
+
+Examples:
+  lazytrack stats               # Statistics for all habits
+  lazytrack stats code          # Statistics for a single habit
+  lazytrack stats --range 90d   # Only look at the last 90 days
+  lazytrack stats code --json   # JSON output for further analysis`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStats(args, rangeInput, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVarP(&rangeInput, "range", "r", "365d", "Range to analyze")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output statistics as JSON")
+	return cmd
+}
+
+// runStats handles the stats command execution
+func runStats(args []string, rangeInput string, jsonOutput bool) error {
+	now := time.Now()
+	startDate, endDate, err := parser.ParseRange(rangeInput, now)
+	if err != nil {
+		return err
+	}
+
+	// Initialize store
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Close()
+
+	// Pick the habits to analyze
+	var habits []types.Habit
+	if len(args) > 0 {
+		habit, err := store.GetHabitByName(strings.ToLower(strings.TrimSpace(args[0])))
+		if err != nil {
+			return err
+		}
+		habits = append(habits, *habit)
+	} else {
+		habits, err = store.GetAllHabits()
+		if err != nil {
+			return fmt.Errorf("failed to get habits: %w", err)
+		}
+	}
+
+	if len(habits) == 0 {
+		displayEmptyState()
+		return nil
+	}
+
+	// Completion rates look back a full year regardless of the range
+	historyStart := endDate.AddDate(0, 0, -365)
+	if startDate.Before(historyStart) {
+		historyStart = startDate
+	}
+	logsByHabit := loadLogsByHabit(store, habits, historyStart, endDate)
+
+	var results []types.HabitStats
+	for _, habit := range habits {
+		results = append(results, stats.CalculateHabitStats(habit, logsByHabit[habit.Name], startDate, endDate))
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("📈 Statistics - %s to %s\n", startDate.Format("Jan 2, 2006"), endDate.AddDate(0, 0, -1).Format("Jan 2, 2006"))
+	cyan.Println(strings.Repeat("=", 50))
+	for _, result := range results {
+		fmt.Println(stats.FormatHabitStats(result))
+	}
+
+	return nil
+}
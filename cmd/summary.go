@@ -17,6 +17,7 @@ import (
 func NewSummaryCmd() *cobra.Command {
 	var weekly bool
 	var daily bool
+	var compare bool
 
 	cmd := &cobra.Command{
 		Use:   "summary",
@@ -26,19 +27,21 @@ func NewSummaryCmd() *cobra.Command {
 Examples:
   lazytrack summary          # Show weekly summary
   lazytrack summary --daily  # Show daily summary
-  lazytrack summary --weekly # Show weekly summary (default)`,
+  lazytrack summary --weekly # Show weekly summary (default)
+  lazytrack summary --compare # Compare with the previous week`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSummary(weekly, daily)
+			return runSummary(weekly, daily, compare)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&weekly, "weekly", "w", true, "Show weekly summary")
 	cmd.Flags().BoolVarP(&daily, "daily", "d", false, "Show daily summary")
+	cmd.Flags().BoolVarP(&compare, "compare", "c", false, "Compare with the previous period")
 	return cmd
 }
 
 // runSummary handles the summary command execution
-func runSummary(weekly, daily bool) error {
+func runSummary(weekly, daily, compare bool) error {
 	// Initialize store
 	store, err := store.NewStore()
 	if err != nil {
@@ -58,44 +61,63 @@ func runSummary(weekly, daily bool) error {
 	}
 
 	// Get logs for all habits
-	logsByHabit := make(map[string][]types.Log)
-	for _, habit := range habits {
-		var startDate, endDate time.Time
-		
-		if daily {
-			startDate = time.Now().Truncate(24 * time.Hour)
-			endDate = startDate.AddDate(0, 0, 1)
-		} else {
-			startDate = getWeekStart(time.Now())
-			endDate = startDate.AddDate(0, 0, 7)
-		}
-
-		logs, err := store.GetLogsByHabit(habit.Name, startDate, endDate)
-		if err != nil {
-			continue // Skip habits with errors
-		}
-		logsByHabit[habit.Name] = logs
+	var startDate, endDate time.Time
+	if daily {
+		startDate = time.Now().Truncate(24 * time.Hour)
+		endDate = startDate.AddDate(0, 0, 1)
+	} else {
+		startDate = parser.WeekStart(time.Now())
+		endDate = startDate.AddDate(0, 0, 7)
+	}
+	logsByHabit := loadLogsByHabit(store, habits, startDate, endDate)
+
+	// Summarize the previous period when comparing
+	var previous *types.WeeklySummary
+	if compare {
+		previousStart, previousEnd := parser.PreviousRange(startDate, endDate)
+		previousLogs := loadLogsByHabit(store, habits, previousStart, previousEnd)
+		previousSummary := summary.CalculateSummary(habits, previousLogs, previousStart, previousEnd)
+		previous = &previousSummary
 	}
 
 	// Calculate and display summary
 	if daily {
 		displayDailySummary(habits, logsByHabit)
 	} else {
-		displayWeeklySummary(habits, logsByHabit)
+		displayWeeklySummary(habits, logsByHabit, previous)
+	}
+
+	if previous != nil {
+		current := summary.CalculateSummary(habits, logsByHabit, startDate, endDate)
+		fmt.Println()
+		fmt.Println(summary.FormatComparison(summary.ComparePeriods(current, *previous)))
 	}
 
 	return nil
 }
 
+// loadLogsByHabit gets the logs of each habit between startDate and endDate
+func loadLogsByHabit(store *store.Store, habits []types.Habit, startDate, endDate time.Time) map[string][]types.Log {
+	logsByHabit := make(map[string][]types.Log)
+	for _, habit := range habits {
+		logs, err := store.GetLogsByHabit(habit.Name, startDate, endDate)
+		if err != nil {
+			continue // Skip habits with errors
+		}
+		logsByHabit[habit.Name] = logs
+	}
+	return logsByHabit
+}
+
 // displayWeeklySummary shows the weekly summary
-func displayWeeklySummary(habits []types.Habit, logsByHabit map[string][]types.Log) {
+func displayWeeklySummary(habits []types.Habit, logsByHabit map[string][]types.Log, previous *types.WeeklySummary) {
 	weeklySummary := summary.CalculateWeeklySummary(habits, logsByHabit)
 	
 	// Display formatted summary
 	fmt.Println(summary.FormatSummary(weeklySummary))
 	
 	// Display motivational message
-	motivationalMsg := summary.GetMotivationalMessage(weeklySummary)
+	motivationalMsg := summary.GetMotivationalMessage(weeklySummary, previous)
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Println("\n" + motivationalMsg)
 }
@@ -120,8 +142,10 @@ func displayDailySummary(habits []types.Habit, logsByHabit map[string][]types.Lo
 		// Calculate daily totals
 		var habitTime float64
 		var habitCount int
+		var habitDistance float64
 		
 		for _, log := range logs {
+			habitDistance += log.Distance
 			if habit.GoalType == "count" {
 				habitCount += log.Count
 			} else {
@@ -135,7 +159,7 @@ func displayDailySummary(habits []types.Habit, logsByHabit map[string][]types.Lo
 		}
 		
 		// Display habit summary
-		displayDailyHabitSummary(habit, habitTime, habitCount)
+		displayDailyHabitSummary(habit, habitTime, habitCount, habitDistance)
 		
 		if habit.GoalType == "count" {
 			totalCount += habitCount
@@ -155,7 +179,7 @@ func displayDailySummary(habits []types.Habit, logsByHabit map[string][]types.Lo
 }
 
 // displayDailyHabitSummary shows a single habit's daily summary
-func displayDailyHabitSummary(habit types.Habit, totalTime float64, totalCount int) {
+func displayDailyHabitSummary(habit types.Habit, totalTime float64, totalCount int, totalDistance float64) {
 	green := color.New(color.FgGreen)
 	yellow := color.New(color.FgYellow)
 	
@@ -176,6 +200,9 @@ func displayDailyHabitSummary(habit types.Habit, totalTime float64, totalCount i
 			fmt.Print("0")
 		}
 	}
+	if totalDistance > 0 {
+		green.Printf(" · %.1f km", totalDistance)
+	}
 	
 	// Goal progress
 	if habit.DailyGoal > 0 {
@@ -208,13 +235,3 @@ func displayEmptyState() {
 	fmt.Println()
 	fmt.Println("Then run 'lazytrack summary' to see your progress!")
 }
-
-// getWeekStart returns the start of the current week (Monday)
-func getWeekStart(t time.Time) time.Time {
-	weekday := int(t.Weekday())
-	if weekday == 0 { // Sunday
-		weekday = 7
-	}
-	daysToSubtract := weekday - 1 // Monday = 1
-	return t.AddDate(0, 0, -daysToSubtract).Truncate(24 * time.Hour)
-} 
\ No newline at end of file
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// syncStateName is the state file holding the snapshot of every synced file
+const syncStateName = "sync"
+
+// NewSyncCmd creates the sync command
+func NewSyncCmd() *cobra.Command {
+	var format, prefer, since string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "sync <file>",
+		Short: "Keep your logs in sync with a plain-text or org-mode file",
+		Long: `Keep your logs in sync with a plain-text (.txt) or org-mode (.org) file that
+you can edit in any editor.
+
+The first sync writes the file. After that, each sync applies the lines you
+added, edited or deleted in the file to your logs, then rewrites the file with
+all logs, including the ones logged since. A line you edit and a log changed
+in lazytrack since the last sync are a conflict: both are reported and left
+as they are until you resolve them, or pick a side with --prefer.
+
+Plain-text lines look like this (the ^id links a line to its log):
+  2026-10-17 09:00-10:30 code #work notes ^12
+  2026-10-17 18:00 read 45m ^13
+  2026-10-17 14:00 water 8x ^14
+
+Org files have a heading per habit and a subheading per log, with a CLOCK
+line for its time range.
+
+Examples:
+  lazytrack sync ~/notes/habits.txt
+  lazytrack sync ~/org/habits.org --since this-year
+  lazytrack sync habits.txt --prefer file`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync(args[0], format, prefer, since, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "File format (text or org; default: from file extension)")
+	cmd.Flags().StringVar(&prefer, "prefer", "", "Resolve conflicts with the version from the 'file' or the 'store'")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Only write logs from this date or range on")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without saving anything")
+	return cmd
+}
+
+// runSync handles the sync command execution
+func runSync(path, format, prefer, since string, dryRun bool) error {
+	if format == "" {
+		var err error
+		if format, err = exchange.DetectFormat(path); err != nil {
+			return err
+		}
+	}
+	if format != "text" && format != "org" {
+		return fmt.Errorf("invalid sync format: %s (must be 'text' or 'org')", format)
+	}
+	if prefer != "" && prefer != "file" && prefer != "store" {
+		return fmt.Errorf("invalid --prefer: %s (must be 'file' or 'store')", prefer)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	// Initialize store
+	store, err := store.NewLockedStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	defer store.Release() // without saving, unless the sync succeeds
+
+	snapshots := make(map[string]exchange.Snapshot)
+	if err := store.LoadState(syncStateName, &snapshots); err != nil {
+		return err
+	}
+
+	// A missing file is written from scratch; it's not a deletion of every entry
+	var rows []exchange.Row
+	snapshot := exchange.Snapshot{}
+	if file, err := os.Open(absPath); err == nil {
+		rows, err = exchange.Parse(file, format)
+		file.Close()
+		if err != nil {
+			return importError(err)
+		}
+		if snapshots[absPath] != nil {
+			snapshot = snapshots[absPath]
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to open sync file: %w", err)
+	}
+
+	logs, err := store.GetAllLogs()
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %w", err)
+	}
+	plan := exchange.PlanSync(rows, logs, snapshot, prefer)
+
+	if dryRun {
+		displaySyncResult(path, plan, 0, true)
+		return nil
+	}
+
+	if err := applySyncPlan(store, plan); err != nil {
+		return err
+	}
+
+	written, newSnapshot, err := writeSyncFile(store, absPath, format, since, plan, snapshot)
+	if err != nil {
+		return err
+	}
+	snapshots[absPath] = newSnapshot
+	if err := store.SaveState(syncStateName, snapshots); err != nil {
+		return err
+	}
+	if err := store.Close(); err != nil {
+		return fmt.Errorf("failed to save logs: %w", err)
+	}
+
+	displaySyncResult(path, plan, written, false)
+	return nil
+}
+
+// applySyncPlan applies the entries added, edited and deleted in the file to the store
+func applySyncPlan(store *store.Store, plan exchange.SyncPlan) error {
+	logs, err := store.GetAllLogs()
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %w", err)
+	}
+	byID := make(map[int]types.Log, len(logs))
+	for _, log := range logs {
+		byID[log.ID] = log
+	}
+
+	for _, row := range plan.Update {
+		habit, err := store.GetOrCreateHabit(row.Record.Habit)
+		if err != nil {
+			return fmt.Errorf("failed to get/create habit: %w", err)
+		}
+
+		// Keep what the file can't express, like the source and distance
+		log := byID[row.ID]
+		log.HabitID = habit.ID
+		log.HabitName = habit.Name
+		log.LoggedAt = row.Record.LoggedAt
+		log.StartedAt = row.Record.StartedAt
+		log.Duration = row.Record.Duration
+		log.Count = row.Record.Count
+		log.Notes = row.Record.Notes
+		if err := store.UpdateLog(log); err != nil {
+			return fmt.Errorf("failed to update log: %w", err)
+		}
+	}
+
+	for _, id := range plan.Delete {
+		if err := store.DeleteLog(id); err != nil {
+			return fmt.Errorf("failed to delete log: %w", err)
+		}
+	}
+
+	for _, row := range plan.Add {
+		record := row.Record
+		if store.HasLog(record.Habit, record.LoggedAt, record.Duration, record.Count) {
+			continue
+		}
+		habit, err := store.GetOrCreateHabit(record.Habit)
+		if err != nil {
+			return fmt.Errorf("failed to get/create habit: %w", err)
+		}
+		log := record.Log()
+		log.HabitID = habit.ID
+		if err := store.AddLogEntry(log); err != nil {
+			return fmt.Errorf("failed to add log: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeSyncFile rewrites the file from the store, keeping the file's version
+// of conflicting entries, and returns the number of entries and the new snapshot
+func writeSyncFile(store *store.Store, path, format, since string, plan exchange.SyncPlan, snapshot exchange.Snapshot) (int, exchange.Snapshot, error) {
+	logs, err := filterLogs(store, since, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	newSnapshot := exchange.Snapshot{}
+	written := make(map[int]bool)
+	for i, log := range logs {
+		if row, conflict := plan.Keep[log.ID]; conflict {
+			logs[i] = fileLog(row)
+			if line, exists := snapshot[log.ID]; exists {
+				newSnapshot[log.ID] = line
+			}
+		} else {
+			newSnapshot[log.ID] = exchange.TextLine(exchange.NewRecord(log))
+		}
+		written[log.ID] = true
+	}
+
+	// Conflicting entries deleted in the store are kept in the file
+	for id, row := range plan.Keep {
+		if !written[id] {
+			logs = append(logs, fileLog(row))
+			if line, exists := snapshot[id]; exists {
+				newSnapshot[id] = line
+			}
+		}
+	}
+
+	var out strings.Builder
+	if err := exchange.Export(&out, format, logs); err != nil {
+		return 0, nil, fmt.Errorf("failed to write sync file: %w", err)
+	}
+
+	// Write to a temporary file first, so an interrupted sync never leaves half a file
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(out.String()), 0644); err != nil {
+		return 0, nil, fmt.Errorf("failed to write sync file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, nil, fmt.Errorf("failed to write sync file: %w", err)
+	}
+	return len(logs), newSnapshot, nil
+}
+
+// fileLog converts a file entry back to a log with its ID
+func fileLog(row exchange.Row) types.Log {
+	log := row.Record.Log()
+	log.ID = row.ID
+	return log
+}
+
+// displaySyncResult shows what the sync changed (or would change)
+func displaySyncResult(path string, plan exchange.SyncPlan, written int, dryRun bool) {
+	green := color.New(color.FgGreen, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	cyan := color.New(color.FgCyan, color.Bold)
+
+	changes := fmt.Sprintf("%d added, %d updated, %d deleted", len(plan.Add), len(plan.Update), len(plan.Delete))
+	if dryRun {
+		cyan.Println("🔍 Dry run - nothing was saved")
+		for _, row := range plan.Add {
+			fmt.Printf("   + %s\n", exchange.TextLine(row.Record))
+		}
+		for _, row := range plan.Update {
+			fmt.Printf("   ~ %s ^%d\n", exchange.TextLine(row.Record), row.ID)
+		}
+		for _, id := range plan.Delete {
+			fmt.Printf("   - ^%d\n", id)
+		}
+		green.Printf("✅ Would apply from %s: %s\n", path, changes)
+	} else {
+		green.Printf("🔄 Synced %s: %s, %d entries written\n", path, changes, written)
+	}
+
+	if len(plan.Conflicts) == 0 {
+		return
+	}
+	yellow.Printf("⚠️  %d conflicts (edited in both places since the last sync):\n", len(plan.Conflicts))
+	for _, conflict := range plan.Conflicts {
+		fileLine, storeLine := conflict.File, conflict.Store
+		if fileLine == "" {
+			fileLine = "(deleted)"
+		}
+		if storeLine == "" {
+			storeLine = "(deleted)"
+		}
+		fmt.Printf("   ^%d file:  %s\n", conflict.ID, fileLine)
+		fmt.Printf("   ^%d store: %s\n", conflict.ID, storeLine)
+	}
+	fmt.Println("💡 Make both sides match, or run again with --prefer file or --prefer store")
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/master-wayne7/lazytrack/message"
+	"github.com/master-wayne7/lazytrack/store"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+	"github.com/spf13/cobra"
+)
+
+// streakLookback is how many days back streaks in messages are counted
+const streakLookback = 365
+
+// NewTemplatesCmd creates the templates command
+func NewTemplatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Check and customize the message templates",
+		Long: `Check and customize the messages of reminders, logs and summaries.
+
+Each message is a Go text/template in ~/.lazytrack/templates/<name>.tmpl, or
+the built-in default if there's no such file:
+
+  reminder        a habit's reminder, for habits without a goal
+  goal_reminder   a reminder of a habit's pending goal
+  late_reminder   the late reminder for pending goals
+  logged          shown when a habit is logged
+  goal_reached    shown when a log reaches the daily goal
+  motivation      the motivational message of summaries
+
+Templates can use {{.Habit}}, {{.Emoji}}, {{.GoalType}}, {{.Progress}},
+{{.Goal}}, {{.Remaining}} (printed like "3 times" or "1h30m", with the number
+in .Value, e.g. {{.Remaining.Value}}), {{.Percent}}, {{.Streak}}, {{.Logged}},
+{{.Habits}}, {{.Hints}} and {{.Trend}}, and the functions join, lower and
+upper. They're checked with sample data when they're loaded.
+
+Examples:
+  lazytrack templates init     # Write the defaults to edit them
+  lazytrack templates check    # Check them and show samples`,
+	}
+
+	cmd.AddCommand(newTemplatesCheckCmd(), newTemplatesInitCmd())
+	return cmd
+}
+
+// newTemplatesCheckCmd creates the templates check command
+func newTemplatesCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Check the templates and show what they print",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTemplatesCheck()
+		},
+	}
+}
+
+// newTemplatesInitCmd creates the templates init command
+func newTemplatesInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Write the default templates to the templates directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTemplatesInit()
+		},
+	}
+}
+
+// LoadTemplates loads the message templates, warning about broken ones
+// (which keep their defaults)
+func LoadTemplates() {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return // the command reports it
+	}
+
+	templates, err := message.Load(filepath.Join(store.DataDir(), message.DirName))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Using default messages for broken templates:\n%v\n", err)
+	}
+	message.Use(templates)
+}
+
+// runTemplatesCheck handles the templates check command execution
+func runTemplatesCheck() error {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	dir := filepath.Join(store.DataDir(), message.DirName)
+	templates, loadErr := message.Load(dir)
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("📝 Message templates (%s)\n", dir)
+	for _, name := range message.Names() {
+		source := "default"
+		if path := templates.Source(name); path != "" {
+			source = filepath.Base(path)
+		}
+		fmt.Printf("\n%s (%s)\n", name, source)
+		for _, line := range strings.Split(templates.Sample(name), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	if loadErr != nil {
+		fmt.Println()
+		return loadErr
+	}
+	return nil
+}
+
+// runTemplatesInit handles the templates init command execution
+func runTemplatesInit() error {
+	// The store is only read here, so it's not closed (which would save it)
+	store, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	dir := filepath.Join(store.DataDir(), message.DirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	for _, name := range message.Names() {
+		path := filepath.Join(dir, name+".tmpl")
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("⏭️  %s already exists\n", path)
+			continue
+		}
+		if err := os.WriteFile(path, []byte(message.DefaultText(name)+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write template: %w", err)
+		}
+		green.Printf("✅ Wrote %s\n", path)
+	}
+	return nil
+}
+
+// habitMessageData returns what the templates of messages about a habit use
+func habitMessageData(store *store.Store, habit types.Habit, now time.Time) message.Data {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := today.AddDate(0, 0, -streakLookback)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	data := message.Data{
+		Habit:    habit.Name,
+		Emoji:    habit.Emoji,
+		GoalType: habit.GoalType,
+		Goal:     message.NewAmount(habit.GoalType, float64(habit.DailyGoal)),
+	}
+	logs, err := store.GetLogsByHabit(habit.Name, start, tomorrow)
+	if err != nil {
+		return data
+	}
+	dailyTotals := summary.CalculateDailyTotals(habit, logs, start, tomorrow)
+	data.Streak, _ = summary.CalculateStreaks(dailyTotals)
+
+	progress := dailyTotals[len(dailyTotals)-1].Value
+	data.Progress = message.NewAmount(habit.GoalType, progress)
+	data.Remaining = message.NewAmount(habit.GoalType, math.Max(float64(habit.DailyGoal)-progress, 0))
+	if habit.DailyGoal > 0 {
+		data.Percent = progress / float64(habit.DailyGoal) * 100
+	}
+	return data
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Detach starts lazytrack again with args as a background process, detached
+// from the terminal, and waits until it holds the PID file
+func Detach(args []string, pidPath string, timeout time.Duration) (int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the lazytrack executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, args...)
+	cmd.SysProcAttr = detachAttr()
+	// Stdin, stdout and stderr are left unset, so they go to the null device;
+	// the daemon writes to its log file instead
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start daemon: %w", err)
+	}
+	pid := cmd.Process.Pid
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.After(timeout)
+	for {
+		if running, ok := Running(pidPath); ok && running == pid {
+			return pid, nil
+		}
+		select {
+		case err := <-exited:
+			if err == nil {
+				err = fmt.Errorf("it exited")
+			}
+			return 0, fmt.Errorf("daemon failed to start: %w (see the daemon logs)", err)
+		case <-deadline:
+			return pid, fmt.Errorf("daemon (PID %d) did not start within %s (see the daemon logs)", pid, timeout)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
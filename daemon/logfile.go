@@ -0,0 +1,201 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Log rotation defaults: daemon.log is rotated to daemon.log.1 at 1 MB, and
+// the three most recent rotated files are kept
+const (
+	DefaultMaxLogSize = 1 << 20
+	DefaultLogBackups = 3
+)
+
+// LogFile is a log file that's rotated once it reaches a size
+type LogFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// OpenLog opens a log file for appending, rotating it at maxSize bytes and
+// keeping backups rotated files
+func OpenLog(path string, maxSize int64, backups int) (*LogFile, error) {
+	l := &LogFile{path: path, maxSize: maxSize, backups: backups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write writes to the log, rotating it first if the write would make it too big
+func (l *LogFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var rotateErr error
+	if l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		rotateErr = l.rotate()
+	}
+	if l.file == nil {
+		return 0, rotateErr
+	}
+
+	// When rotating fails, the entry still goes to the current file, and
+	// rotating is tried again on the next write
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Close closes the log file
+func (l *LogFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// open opens the log file and picks up its current size
+func (l *LogFile) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// rotate moves daemon.log to daemon.log.1 (and daemon.log.1 to daemon.log.2,
+// and so on, dropping the oldest) and starts a new file. The file is reopened
+// even if it couldn't be moved, so the log keeps working.
+func (l *LogFile) rotate() error {
+	// Windows can't move an open file
+	err := l.file.Close()
+	l.file = nil
+	if err == nil {
+		err = l.shift()
+	}
+	if openErr := l.open(); openErr != nil {
+		return openErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	return nil
+}
+
+// shift moves the log file and its backups up by one
+func (l *LogFile) shift() error {
+	os.Remove(fmt.Sprintf("%s.%d", l.path, l.backups))
+	for i := l.backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	if l.backups > 0 {
+		return os.Rename(l.path, l.path+".1")
+	}
+	return os.Truncate(l.path, 0)
+}
+
+// Tail returns the last n lines of a log file, including those rotated away
+// if the current file is shorter
+func Tail(path string, n int) ([]string, error) {
+	var lines []string
+	for i := 0; i <= DefaultLogBackups && len(lines) < n; i++ {
+		name := path
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", path, i)
+		}
+		fileLines, err := readLines(name)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read log file: %w", err)
+		}
+		lines = append(fileLines, lines...)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// readLines reads the lines of a file
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// Follow copies what's appended to a log file to w until ctx is done
+func Follow(ctx context.Context, path string, w io.Writer) error {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // e.g. while the file is rotated
+		}
+		if info.Size() < offset {
+			offset = 0 // the file was rotated
+		}
+		if info.Size() == offset {
+			continue
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err == nil {
+			n, err := io.Copy(w, file)
+			offset += n
+			if err != nil {
+				file.Close()
+				return fmt.Errorf("failed to follow log file: %w", err)
+			}
+		}
+		file.Close()
+	}
+}
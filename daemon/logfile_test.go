@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogFileRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	l, err := OpenLog(path, 100, 2)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	// 19 bytes a line, so each file holds 5 lines
+	for i := 1; i <= 17; i++ {
+		if _, err := fmt.Fprintf(l, "entry %02d..........\n", i); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// 17 lines make 4 files, the oldest of which was dropped
+	for name, lines := range map[string]int{"daemon.log": 2, "daemon.log.1": 5, "daemon.log.2": 5} {
+		data, err := os.ReadFile(filepath.Join(filepath.Dir(path), name))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got := strings.Count(string(data), "\n"); got != lines {
+			t.Errorf("%s holds %d lines, want %d", name, got, lines)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("daemon.log.3 was kept: %v", err)
+	}
+
+	tail, err := Tail(path, 8)
+	if err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+	if len(tail) != 8 || tail[0] != "entry 10.........." || tail[7] != "entry 17.........." {
+		t.Errorf("Tail = %q, want entries 10 to 17", tail)
+	}
+	if all, _ := Tail(path, 100); len(all) != 12 || all[0] != "entry 06.........." {
+		t.Errorf("Tail of all = %q, want entries 06 to 17", all)
+	}
+}
+
+func TestLogFileRotateFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "daemon.log")
+	// A directory in the way of the backup keeps the log from being moved
+	if err := os.MkdirAll(filepath.Join(path+".1", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := OpenLog(path, 10, 1)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	defer l.Close()
+	if _, err := l.Write([]byte("first line\n")); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	if n, err := l.Write([]byte("second line\n")); err == nil || n != len("second line\n") {
+		t.Errorf("second write: %d, %v, want it written and the rotation error", n, err)
+	}
+
+	// The log still works once the directory is gone
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Write([]byte("third line\n")); err != nil {
+		t.Fatalf("third write: %v", err)
+	}
+	if lines, _ := Tail(path, 10); len(lines) != 3 || lines[2] != "third line" {
+		t.Errorf("Tail = %q, want all three lines", lines)
+	}
+}
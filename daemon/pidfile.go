@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/filelock"
+)
+
+// ErrRunning is returned when another daemon holds the PID file
+var ErrRunning = errors.New("daemon is already running")
+
+// PIDFile is a lock file holding the PID of the running daemon. The daemon
+// keeps it locked, so the lock goes away with the daemon even if it dies, and
+// starting daemons can't both take over a stale file.
+type PIDFile struct {
+	file *os.File
+}
+
+// Acquire locks the PID file and writes this process's PID to it. It fails
+// with ErrRunning if another daemon holds the lock.
+func Acquire(path string) (*PIDFile, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PID file: %w", err)
+	}
+	if err := filelock.TryLock(file); err != nil {
+		file.Close()
+		if !errors.Is(err, filelock.ErrLocked) {
+			return nil, fmt.Errorf("failed to lock PID file: %w", err)
+		}
+		if pid, _ := Running(path); pid > 0 {
+			return nil, fmt.Errorf("%w (PID %d)", ErrRunning, pid)
+		}
+		return nil, ErrRunning
+	}
+
+	// The file is ours now, so whatever it holds was left by a daemon that died
+	if err := writePID(file); err != nil {
+		filelock.Unlock(file)
+		file.Close()
+		return nil, fmt.Errorf("failed to write PID file: %w", err)
+	}
+	return &PIDFile{file: file}, nil
+}
+
+// writePID replaces what a file holds with this process's PID
+func writePID(file *os.File) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return err
+}
+
+// Release empties and unlocks the PID file. The file itself stays, so that a
+// daemon starting meanwhile never locks a file that's about to be removed.
+func (p *PIDFile) Release() error {
+	err := p.file.Truncate(0)
+	if unlockErr := filelock.Unlock(p.file); err == nil {
+		err = unlockErr
+	}
+	if closeErr := p.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release PID file: %w", err)
+	}
+	return nil
+}
+
+// Running returns the PID of the daemon holding a PID file, and whether one
+// does. Only the lock tells: a daemon that was killed leaves its PID in the
+// file, and the system may have given that PID to another process since. The
+// PID is 0 while a starting daemon hasn't written it yet.
+func Running(path string) (int, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+	if err := filelock.TryLock(file); !errors.Is(err, filelock.ErrLocked) {
+		if err == nil {
+			filelock.Unlock(file)
+		}
+		return 0, false
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return 0, true
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, true
+	}
+	return pid, true
+}
+
+// StartedAt returns when the daemon holding a PID file started
+func StartedAt(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// Stop asks the daemon holding a PID file to shut down and waits until it
+// has, or the timeout passes
+func Stop(path string, timeout time.Duration) error {
+	pid, running := Running(path)
+	if !running {
+		return fmt.Errorf("daemon is not running")
+	}
+	if pid == 0 {
+		return fmt.Errorf("daemon is still starting, try again")
+	}
+	if err := terminate(pid); err != nil {
+		return fmt.Errorf("failed to stop daemon (PID %d): %w", pid, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, running := Running(path); !running {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("daemon (PID %d) did not stop within %s", pid, timeout)
+}
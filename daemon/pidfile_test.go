@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+
+	pidFile, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if pid, running := Running(path); !running || pid != os.Getpid() {
+		t.Errorf("Running = %d, %v, want %d, true", pid, running, os.Getpid())
+	}
+
+	if _, err := Acquire(path); !errors.Is(err, ErrRunning) {
+		t.Errorf("second Acquire: got %v, want ErrRunning", err)
+	}
+
+	if err := pidFile.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if pid, running := Running(path); running || pid != 0 {
+		t.Errorf("Running after Release = %d, %v, want 0, false", pid, running)
+	}
+
+	pidFile, err = Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire after Release: %v", err)
+	}
+	pidFile.Release()
+}
+
+func TestAcquireStaleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+	// Left by a daemon that died, whose PID may belong to another process by now
+	if err := os.WriteFile(path, []byte("1234567\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pidFile, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer pidFile.Release()
+	if pid, _ := Running(path); pid != os.Getpid() {
+		t.Errorf("PID file holds %d, want %d", pid, os.Getpid())
+	}
+}
+
+func TestAcquireRace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+	if err := os.WriteFile(path, []byte("1234567\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const starts = 20
+	results := make(chan *PIDFile, starts)
+	var wg sync.WaitGroup
+	for i := 0; i < starts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pidFile, err := Acquire(path)
+			if err != nil && !errors.Is(err, ErrRunning) {
+				t.Errorf("Acquire: %v", err)
+			}
+			results <- pidFile
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	acquired := 0
+	for pidFile := range results {
+		if pidFile != nil {
+			acquired++
+			defer pidFile.Release()
+		}
+	}
+	if acquired != 1 {
+		t.Errorf("%d of %d daemons took over the stale PID file, want 1", acquired, starts)
+	}
+}
+
+func TestRunningIgnoresUnlockedPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+	// Left by a daemon that was killed, with a PID the system gave to a
+	// live process since
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if pid, running := Running(path); running {
+		t.Errorf("Running = %d, true, want the daemon not running", pid)
+	}
+	if err := Stop(path, time.Second); err == nil {
+		t.Error("Stop signaled the process whose PID the file holds")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package daemon
+
+import "syscall"
+
+// terminate asks a process to shut down
+func terminate(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
+
+// detachAttr starts a process in a new session, without a controlling terminal
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// Process creation flags (see CreateProcess)
+const (
+	detachedProcess       = 0x00000008
+	createNewProcessGroup = 0x00000200
+)
+
+// terminate stops a process. Windows has no SIGTERM for processes without a
+// console, so the daemon is killed; the system releases its PID file lock.
+func terminate(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	defer process.Release()
+	return process.Kill()
+}
+
+// detachAttr starts a process without a console, outside of the parent's
+// process group (so closing the terminal doesn't stop it)
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | createNewProcessGroup, HideWindow: true}
+}
@@ -0,0 +1,180 @@
+package dailynote
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Section names of the parts of a note lazytrack writes
+const (
+	LogSection    = "log"
+	HabitsSection = "habits"
+)
+
+// Path returns the note of a day from a template like "~/vault/Daily/{{date}}.md".
+// The template may use {{date}} (2026-10-17), {{year}}, {{month}} and {{day}}.
+func Path(template string, day time.Time) (string, error) {
+	path := strings.NewReplacer(
+		"{{date}}", day.Format("2006-01-02"),
+		"{{year}}", day.Format("2006"),
+		"{{month}}", day.Format("01"),
+		"{{day}}", day.Format("02"),
+	).Replace(strings.TrimSpace(template))
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+	if strings.Contains(path, "{{") {
+		return "", fmt.Errorf("unknown placeholder in daily note path: %s", template)
+	}
+	return path, nil
+}
+
+// UpdateSection replaces the content between the markers of a section, e.g.
+// <!-- lazytrack:log --> and <!-- /lazytrack:log -->. A note without the
+// section gets it appended under the heading, and a missing note is created.
+// Everything outside the markers is left as it is.
+func UpdateSection(path, name, heading, content string) error {
+	start := fmt.Sprintf("<!-- lazytrack:%s -->", name)
+	end := fmt.Sprintf("<!-- /lazytrack:%s -->", name)
+	section := start + "\n" + strings.TrimRight(content, "\n") + "\n" + end
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read daily note: %w", err)
+	}
+	note := string(data)
+
+	startIndex := strings.Index(note, start)
+	endIndex := strings.Index(note, end)
+	if startIndex >= 0 && endIndex > startIndex {
+		note = note[:startIndex] + section + note[endIndex+len(end):]
+	} else {
+		if note != "" && !strings.HasSuffix(note, "\n") {
+			note += "\n"
+		}
+		if note != "" {
+			note += "\n"
+		}
+		if heading != "" {
+			note += heading + "\n\n"
+		}
+		note += section + "\n"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create daily note directory: %w", err)
+	}
+	// Write to a temporary file first, so an editor never sees half a note
+	tmpPath := path + ".lazytrack.tmp"
+	if err := os.WriteFile(tmpPath, []byte(note), 0644); err != nil {
+		return fmt.Errorf("failed to write daily note: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write daily note: %w", err)
+	}
+	return nil
+}
+
+// LogList renders the logs of a day as a Markdown list, oldest first
+func LogList(logs []types.Log, habits []types.Habit) string {
+	emojis := make(map[string]string)
+	for _, habit := range habits {
+		emojis[habit.Name] = habit.Emoji
+	}
+
+	sorted := make([]types.Log, len(logs))
+	copy(sorted, logs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LoggedAt.Before(sorted[j].LoggedAt)
+	})
+
+	var lines []string
+	for _, log := range sorted {
+		line := fmt.Sprintf("- %s %s %s %s", log.LoggedAt.Local().Format("15:04"), emojis[log.HabitName], log.HabitName, logAmount(log))
+		if log.Distance > 0 {
+			line += fmt.Sprintf(" · %.1f km", log.Distance)
+		}
+		if notes := strings.Join(strings.Fields(log.Notes), " "); notes != "" {
+			line += " — " + notes
+		}
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	if len(lines) == 0 {
+		return "- Nothing logged yet"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HabitsTable renders a Markdown table with each habit's total and goal
+// progress for a day. Habits without a daily or weekly goal are only listed if
+// they were logged.
+func HabitsTable(habits []types.Habit, logs []types.Log) string {
+	sorted := make([]types.Habit, len(habits))
+	copy(sorted, habits)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	rows := []string{"| Habit | Today | Goal | Progress |", "| --- | --- | --- | --- |"}
+	for _, habit := range sorted {
+		var value float64
+		logged := false
+		for _, log := range logs {
+			if log.HabitName == habit.Name {
+				logged = true
+				value += summary.LogValue(habit, log)
+			}
+		}
+		// A weekly goal counts a seventh of it each day, as in reports
+		dayGoal := summary.PeriodGoal(habit, 1)
+		if !logged && dayGoal == 0 {
+			continue
+		}
+
+		today := fmt.Sprintf("%dx", int(value))
+		if habit.GoalType != "count" {
+			today = formatMinutes(int(math.Round(value * 60)))
+		}
+		goal, progress := "-", "-"
+		if dayGoal > 0 {
+			goal = summary.FormatAmount(habit.GoalType, float64(habit.DailyGoal))
+			if habit.DailyGoal == 0 {
+				goal = summary.FormatAmount(habit.GoalType, float64(habit.WeeklyGoal)) + "/week"
+			}
+			percent := int(value * 100 / dayGoal)
+			progress = fmt.Sprintf("%d%%", percent)
+			if percent >= 100 {
+				progress += " ✅"
+			}
+		}
+		rows = append(rows, fmt.Sprintf("| %s %s | %s | %s | %s |", habit.Emoji, habit.Name, today, goal, progress))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// logAmount formats the duration or count of a log
+func logAmount(log types.Log) string {
+	if log.Count > 0 {
+		return fmt.Sprintf("%dx", log.Count)
+	}
+	return log.Duration
+}
+
+// formatMinutes formats minutes like "1h30m"
+func formatMinutes(minutes int) string {
+	return parser.FormatDuration(types.ParsedDuration{Hours: minutes / 60, Minutes: minutes % 60, IsValid: true})
+}
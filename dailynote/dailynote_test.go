@@ -0,0 +1,42 @@
+package dailynote
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+func TestHabitsTable(t *testing.T) {
+	at := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+	habits := []types.Habit{
+		{Name: "code", Emoji: "💻", GoalType: "duration", DailyGoal: 2},
+		{Name: "water", Emoji: "💧", GoalType: "count", DailyGoal: 8},
+		{Name: "gym", Emoji: "🏋️", GoalType: "count", WeeklyGoal: 3},
+		{Name: "run", Emoji: "🏃", GoalType: "duration", WeeklyGoal: 7},
+		{Name: "notes", Emoji: "📝", GoalType: "count"},
+		{Name: "read", Emoji: "📚", GoalType: "duration"},
+	}
+	logs := []types.Log{
+		{HabitName: "code", Duration: "1h", Count: 1, LoggedAt: at},
+		{HabitName: "code", Duration: "30m", LoggedAt: at},
+		{HabitName: "water", Count: 8, LoggedAt: at},
+		{HabitName: "gym", Count: 1, LoggedAt: at},
+		{HabitName: "notes", Count: 2, LoggedAt: at},
+	}
+
+	want := strings.Join([]string{
+		"| Habit | Today | Goal | Progress |",
+		"| --- | --- | --- | --- |",
+		"| 💻 code | 1h30m | 2h | 75% |",
+		"| 🏋️ gym | 1x | 3x/week | 233% ✅ |",
+		"| 📝 notes | 2x | - | - |",
+		// Listed for its weekly goal, though not logged today
+		"| 🏃 run | 0m | 7h/week | 0% |",
+		"| 💧 water | 8x | 8x | 100% ✅ |",
+	}, "\n")
+	if got := HabitsTable(habits, logs); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
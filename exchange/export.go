@@ -0,0 +1,142 @@
+package exchange
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// csvHeader is the column layout of CSV exports and imports
+var csvHeader = []string{"habit", "logged_at", "duration", "count", "notes", "started_at", "source", "distance"}
+
+// Record is the portable form of a log used by exports and imports
+type Record struct {
+	Habit     string    `json:"habit"`
+	LoggedAt  time.Time `json:"logged_at"`
+	StartedAt time.Time `json:"started_at,omitzero"`
+	Duration  string    `json:"duration,omitempty"`
+	Count     int       `json:"count,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Distance  float64   `json:"distance,omitempty"` // in kilometers
+}
+
+// NewRecord converts a log to a portable record
+func NewRecord(log types.Log) Record {
+	return Record{
+		Habit:     log.HabitName,
+		LoggedAt:  log.LoggedAt,
+		StartedAt: log.StartedAt,
+		Duration:  log.Duration,
+		Count:     log.Count,
+		Notes:     log.Notes,
+		Source:    log.Source,
+		Distance:  log.Distance,
+	}
+}
+
+// Log converts a record back to a log entry
+func (r Record) Log() types.Log {
+	return types.Log{
+		HabitName: r.Habit,
+		Duration:  r.Duration,
+		Count:     r.Count,
+		LoggedAt:  r.LoggedAt,
+		StartedAt: r.StartedAt,
+		Notes:     r.Notes,
+		Source:    r.Source,
+		Distance:  r.Distance,
+	}
+}
+
+// DetectFormat guesses the format of a file from its extension
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json", nil
+	case ".ndjson", ".jsonl":
+		return "ndjson", nil
+	case ".csv":
+		return "csv", nil
+	case ".txt":
+		return "text", nil
+	case ".org":
+		return "org", nil
+	default:
+		return "", fmt.Errorf("cannot detect format of %s (use --format)", path)
+	}
+}
+
+// ValidateFormat checks that logs can be exported in a format, which
+// includes ics (see WriteICS)
+func ValidateFormat(format string) error {
+	switch format {
+	case "json", "csv", "ndjson", "text", "org", "ics":
+		return nil
+	}
+	return fmt.Errorf("invalid export format: %s (must be 'json', 'csv', 'ndjson', 'text', 'org' or 'ics')", format)
+}
+
+// Export writes logs in the given format (json, csv, ndjson, text or org)
+func Export(w io.Writer, format string, logs []types.Log) error {
+	switch format {
+	case "text":
+		return WriteText(w, logs)
+	case "org":
+		return WriteOrg(w, logs)
+	}
+
+	records := make([]Record, 0, len(logs))
+	for _, log := range logs {
+		records = append(records, NewRecord(log))
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case "ndjson":
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, record := range records {
+			count := ""
+			if record.Count > 0 {
+				count = strconv.Itoa(record.Count)
+			}
+			startedAt := ""
+			if !record.StartedAt.IsZero() {
+				startedAt = record.StartedAt.Format(time.RFC3339Nano)
+			}
+			distance := ""
+			if record.Distance > 0 {
+				distance = strconv.FormatFloat(record.Distance, 'f', -1, 64)
+			}
+			row := []string{record.Habit, record.LoggedAt.Format(time.RFC3339Nano), record.Duration, count, record.Notes, startedAt, record.Source, distance}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("invalid export format: %s (must be 'json', 'csv', 'ndjson', 'text' or 'org')", format)
+	}
+}
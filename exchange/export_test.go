@@ -0,0 +1,183 @@
+package exchange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// testLogs are logs with sub-second timestamps, as logs made with the CLI have
+func testLogs() []types.Log {
+	loggedAt := time.Date(2025, 3, 14, 9, 26, 53, 589793238, time.UTC)
+	return []types.Log{
+		{ID: 1, HabitName: "code", Duration: "1h30m", LoggedAt: loggedAt, StartedAt: loggedAt.Add(-90 * time.Minute), Notes: "parser, \"quoted\""},
+		{ID: 2, HabitName: "water", Count: 3, LoggedAt: loggedAt.Add(time.Hour + 123456789)},
+		{ID: 3, HabitName: "run", Duration: "45m", LoggedAt: loggedAt.Add(2 * time.Hour), Source: "gpx:run.gpx", Distance: 7.25},
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "ndjson", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			logs := testLogs()
+			var buf bytes.Buffer
+			if err := Export(&buf, format, logs); err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			rows, err := Parse(&buf, format)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(rows) != len(logs) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(logs))
+			}
+			for i, row := range rows {
+				want := NewRecord(logs[i])
+				got := row.Record
+				if !got.LoggedAt.Equal(want.LoggedAt) || !got.StartedAt.Equal(want.StartedAt) {
+					t.Errorf("row %d: times %v/%v, want %v/%v", i, got.LoggedAt, got.StartedAt, want.LoggedAt, want.StartedAt)
+				}
+				got.LoggedAt, got.StartedAt = want.LoggedAt, want.StartedAt
+				if got != want {
+					t.Errorf("row %d: got %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// editableLogs are logs of whole minutes, which the text and org formats keep
+func editableLogs() []types.Log {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 17, hour, minute, 0, 0, time.Local)
+	}
+	return []types.Log{
+		{ID: 1, HabitName: "run", Duration: "35m", StartedAt: at(7, 0), LoggedAt: at(7, 40), Notes: "easy"},
+		{ID: 2, HabitName: "code", Duration: "1h30m", StartedAt: at(9, 0), LoggedAt: at(10, 30), Notes: "parser,\ntests"},
+		{ID: 3, HabitName: "water", Count: 8, LoggedAt: at(14, 0)},
+		{ID: 4, HabitName: "read", Duration: "45m", LoggedAt: at(18, 0), Notes: "3 chapters"},
+		{ID: 5, HabitName: "code", Duration: "1h", StartedAt: at(23, 30), LoggedAt: at(23, 30).Add(time.Hour)},
+		{HabitName: "water", Count: 2, StartedAt: at(20, 0), LoggedAt: at(20, 15)},
+	}
+}
+
+func TestEditableRoundTrip(t *testing.T) {
+	for _, format := range []string{"text", "org"} {
+		t.Run(format, func(t *testing.T) {
+			logs := editableLogs()
+			var buf bytes.Buffer
+			if err := Export(&buf, format, logs); err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			rows, err := Parse(&buf, format)
+			if err != nil {
+				t.Fatalf("Parse: %v\n%s", err, buf.String())
+			}
+			if len(rows) != len(logs) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(logs))
+			}
+
+			// Rows come back in the format's order, so match them by line
+			want := make(map[string]int)
+			for _, log := range logs {
+				want[TextLine(NewRecord(log))] = log.ID
+			}
+			for _, row := range rows {
+				line := TextLine(row.Record)
+				id, ok := want[line]
+				if !ok {
+					t.Errorf("line %d: got %q, which wasn't exported", row.Line, line)
+					continue
+				}
+				if row.ID != id {
+					t.Errorf("line %d: %q has ID %d, want %d", row.Line, line, row.ID, id)
+				}
+				delete(want, line)
+			}
+		})
+	}
+}
+
+func TestParseTextLine(t *testing.T) {
+	local := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		line    string
+		want    Row
+		wantErr bool
+	}{
+		{line: "2026-10-17 09:00-10:30 code #work notes ^12", want: Row{ID: 12, Record: Record{
+			Habit: "code", StartedAt: local(17, 9, 0), LoggedAt: local(17, 10, 30), Duration: "1h30m", Notes: "#work notes"}}},
+		{line: "2026-10-17 07:00-07:40 run 35m easy", want: Row{Record: Record{
+			Habit: "run", StartedAt: local(17, 7, 0), LoggedAt: local(17, 7, 40), Duration: "35m", Notes: "easy"}}},
+		{line: "2026-10-17 23:30-00:30 code", want: Row{Record: Record{
+			Habit: "code", StartedAt: local(17, 23, 30), LoggedAt: local(18, 0, 30), Duration: "1h"}}},
+		{line: "2026-10-17 18:00 read 45m 3 chapters", want: Row{Record: Record{
+			Habit: "read", LoggedAt: local(17, 18, 0), Duration: "45m", Notes: "3 chapters"}}},
+		{line: "2026-10-17 14:00 water 8x", want: Row{Record: Record{Habit: "water", LoggedAt: local(17, 14, 0), Count: 8}}},
+		{line: "2026-10-17 18:00 read 3 chapters", wantErr: true},
+		{line: "2026-10-17 read 45m", wantErr: true},
+		{line: "17.10.2026 18:00 read 45m", wantErr: true},
+		{line: "2026-10-17 18:00 read 45m ^x", wantErr: true},
+		{line: "2026-10-17 18:00 read 45m ^0", wantErr: true},
+	}
+	for _, test := range tests {
+		row, err := parseTextLine(test.line)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: got %+v, want an error", test.line, row)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.line, err)
+			continue
+		}
+		if row != test.want {
+			t.Errorf("%q: got %+v, want %+v", test.line, row, test.want)
+		}
+	}
+}
+
+func TestParseOrgClocks(t *testing.T) {
+	org := `#+TITLE: lazytrack
+* Code :work:
+** parser
+:PROPERTIES:
+:LAZYTRACK_ID: 4
+:END:
+CLOCK: [2026-10-17 Sat 09:00]--[2026-10-17 Sat 10:30] =>  1:30
+CLOCK: [2026-10-17 14:00]--[2026-10-17 14:45] =>  0:45
+** notes without a time
+* water
+** water
+:PROPERTIES:
+:COUNT: 8
+:LOGGED: [2026-10-17 Sat 14:00]
+:END:
+`
+	rows, err := Parse(strings.NewReader(org), "org")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []struct {
+		id   int
+		line string
+	}{
+		{4, "2026-10-17 09:00-10:30 code parser"},
+		{0, "2026-10-17 14:00-14:45 code parser"}, // only the first clock keeps the ID
+		{0, "2026-10-17 14:00 water 8x"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		if line := TextLine(rows[i].Record); rows[i].ID != w.id || line != w.line {
+			t.Errorf("row %d: got %q with ID %d, want %q with ID %d", i, line, rows[i].ID, w.line, w.id)
+		}
+	}
+}
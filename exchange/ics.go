@@ -0,0 +1,158 @@
+package exchange
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// icsTimeFormat is the UTC date-time format used by iCalendar
+const icsTimeFormat = "20060102T150405Z"
+
+// LogSpan returns the start and end of a time-ranged log. Logs without a
+// known start are assumed to end when they were logged.
+func LogSpan(log types.Log) (time.Time, time.Time, bool) {
+	if log.Duration == "" {
+		return time.Time{}, time.Time{}, false
+	}
+	if !log.StartedAt.IsZero() {
+		return log.StartedAt, log.LoggedAt, true
+	}
+
+	parsed, err := parser.ParseDuration(log.Duration)
+	if err != nil || parser.GetTotalMinutes(parsed) == 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	return log.LoggedAt.Add(-time.Duration(parser.GetTotalMinutes(parsed)) * time.Minute), log.LoggedAt, true
+}
+
+// WriteICS writes logs as an iCalendar feed. Time-ranged logs become timed
+// events and count-based logs become one all-day event per habit and day.
+func WriteICS(w io.Writer, logs []types.Log, habits []types.Habit, now time.Time) error {
+	emojis := make(map[string]string)
+	for _, habit := range habits {
+		emojis[habit.Name] = habit.Emoji
+	}
+
+	var lines []string
+	lines = append(lines,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//LazyTrack//LazyTrack//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:LazyTrack",
+	)
+
+	stamp := now.UTC().Format(icsTimeFormat)
+
+	type dayCount struct {
+		habit string
+		day   time.Time
+		count int
+		notes []string
+	}
+	counts := make(map[string]*dayCount)
+
+	// Timed events
+	for _, log := range logs {
+		start, end, ok := LogSpan(log)
+		if !ok {
+			if log.Count == 0 {
+				continue
+			}
+			local := log.LoggedAt.Local()
+			day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+			key := log.HabitName + "|" + day.Format("20060102")
+			if counts[key] == nil {
+				counts[key] = &dayCount{habit: log.HabitName, day: day}
+			}
+			counts[key].count += log.Count
+			if log.Notes != "" {
+				counts[key].notes = append(counts[key].notes, log.Notes)
+			}
+			continue
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:log-%s-%d-%d@lazytrack", log.HabitName, start.Unix(), log.ID),
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+start.UTC().Format(icsTimeFormat),
+			"DTEND:"+end.UTC().Format(icsTimeFormat),
+			"SUMMARY:"+escapeICSText(strings.TrimSpace(fmt.Sprintf("%s %s (%s)", emojis[log.HabitName], log.HabitName, log.Duration))),
+			"CATEGORIES:"+escapeICSText(log.HabitName),
+		)
+		if log.Notes != "" {
+			lines = append(lines, "DESCRIPTION:"+escapeICSText(log.Notes))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+
+	// All-day events summarizing counts, in a stable order
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		item := counts[key]
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:count-%s-%s@lazytrack", item.habit, item.day.Format("20060102")),
+			"DTSTAMP:"+stamp,
+			"DTSTART;VALUE=DATE:"+item.day.Format("20060102"),
+			"DTEND;VALUE=DATE:"+item.day.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+escapeICSText(strings.TrimSpace(fmt.Sprintf("%s %s: %s", emojis[item.habit], item.habit, parser.FormatCount(item.count)))),
+			"CATEGORIES:"+escapeICSText(item.habit),
+			"TRANSP:TRANSPARENT",
+		)
+		if len(item.notes) > 0 {
+			lines = append(lines, "DESCRIPTION:"+escapeICSText(strings.Join(item.notes, "\n")))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICSLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeICSText escapes text values as required by RFC 5545
+func escapeICSText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+// foldICSLine folds lines longer than 75 octets without splitting UTF-8 characters
+func foldICSLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+
+	var result strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		result.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	result.WriteString(line)
+	return result.String()
+}
@@ -0,0 +1,50 @@
+package exchange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+func TestWriteICSUniqueUIDs(t *testing.T) {
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	logs := []types.Log{
+		// Two logs of a habit starting in the same second, e.g. from two imports
+		{ID: 1, HabitName: "code", Duration: "1h", StartedAt: start, LoggedAt: start.Add(time.Hour)},
+		{ID: 2, HabitName: "code", Duration: "30m", StartedAt: start.Add(500 * time.Millisecond), LoggedAt: start.Add(30 * time.Minute)},
+		{ID: 3, HabitName: "read", Duration: "1h", StartedAt: start, LoggedAt: start.Add(time.Hour)},
+		{ID: 4, HabitName: "water", Count: 2, LoggedAt: start},
+		{ID: 5, HabitName: "water", Count: 1, LoggedAt: start.Add(time.Hour)},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, logs, nil, start); err != nil {
+		t.Fatalf("WriteICS: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var uids []string
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		uid, ok := strings.CutPrefix(line, "UID:")
+		if !ok {
+			continue
+		}
+		if seen[uid] {
+			t.Errorf("duplicate UID %s", uid)
+		}
+		seen[uid] = true
+		uids = append(uids, uid)
+	}
+	want := []string{
+		"log-code-1741942800-1@lazytrack",
+		"log-code-1741942800-2@lazytrack",
+		"log-read-1741942800-3@lazytrack",
+		"count-water-20250314@lazytrack",
+	}
+	if strings.Join(uids, " ") != strings.Join(want, " ") {
+		t.Errorf("UIDs = %v, want %v", uids, want)
+	}
+}
@@ -0,0 +1,341 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/parser"
+)
+
+// Row is a validated record together with the line it came from
+type Row struct {
+	File   string // the file the row was read from, if rows are read from several
+	Line   int
+	ID     int // the log a text or org entry was exported from, if any
+	Record Record
+}
+
+// LineError describes an invalid row
+type LineError struct {
+	File string // set if rows are read from several files
+	Line int
+	Err  error
+}
+
+// Error implements the error interface
+func (e LineError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ValidationError holds every invalid row of an import
+type ValidationError struct {
+	Errors []LineError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	var lines []string
+	for _, lineErr := range e.Errors {
+		lines = append(lines, lineErr.Error())
+	}
+	return fmt.Sprintf("%d invalid rows:\n  %s", len(e.Errors), strings.Join(lines, "\n  "))
+}
+
+// Parse reads and validates records in the given format (json, csv, ndjson, text or org).
+// Either every row is returned or a *ValidationError listing all invalid rows.
+func Parse(r io.Reader, format string) ([]Row, error) {
+	var rows []Row
+	var lineErrors []LineError
+	var err error
+
+	switch format {
+	case "json":
+		rows, lineErrors, err = parseJSON(r)
+	case "ndjson":
+		rows, lineErrors, err = parseNDJSON(r)
+	case "csv":
+		rows, lineErrors, err = parseCSV(r)
+	case "text":
+		rows, lineErrors, err = parseText(r)
+	case "org":
+		rows, lineErrors, err = parseOrg(r)
+	default:
+		return nil, fmt.Errorf("invalid import format: %s (must be 'json', 'csv', 'ndjson', 'text' or 'org')", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Validate(rows, lineErrors...)
+}
+
+// Validate normalizes rows and checks that every row can be imported.
+// Errors found while reading the rows can be passed in to be reported together.
+func Validate(rows []Row, lineErrors ...LineError) ([]Row, error) {
+	for i := range rows {
+		if err := normalizeRecord(&rows[i].Record); err != nil {
+			lineErrors = append(lineErrors, LineError{File: rows[i].File, Line: rows[i].Line, Err: err})
+		}
+	}
+
+	if len(lineErrors) > 0 {
+		sort.SliceStable(lineErrors, func(i, j int) bool {
+			if lineErrors[i].File != lineErrors[j].File {
+				return lineErrors[i].File < lineErrors[j].File
+			}
+			return lineErrors[i].Line < lineErrors[j].Line
+		})
+		return nil, &ValidationError{Errors: lineErrors}
+	}
+	return rows, nil
+}
+
+// normalizeRecord cleans up and validates a single record
+func normalizeRecord(record *Record) error {
+	record.Habit = strings.ToLower(strings.TrimSpace(record.Habit))
+	record.Duration = strings.TrimSpace(record.Duration)
+
+	if record.Habit == "" {
+		return fmt.Errorf("missing habit")
+	}
+	if record.LoggedAt.IsZero() {
+		return fmt.Errorf("missing logged_at")
+	}
+	if record.Count < 0 {
+		return fmt.Errorf("negative count: %d", record.Count)
+	}
+	if record.Distance < 0 {
+		return fmt.Errorf("negative distance: %g", record.Distance)
+	}
+	if record.Duration == "" && record.Count == 0 {
+		return fmt.Errorf("missing duration or count")
+	}
+	if !record.StartedAt.IsZero() && record.StartedAt.After(record.LoggedAt) {
+		return fmt.Errorf("started_at is after logged_at")
+	}
+
+	if record.Duration != "" {
+		if parser.IsCountBased(record.Duration) {
+			return fmt.Errorf("invalid duration: %s (use the count field for counts)", record.Duration)
+		}
+		parsed, err := parser.ParseDuration(record.Duration)
+		if err != nil {
+			return err
+		}
+		record.Duration = parser.FormatDuration(parsed)
+	}
+
+	return nil
+}
+
+// parseJSON parses a JSON array of records, keeping track of line numbers
+func parseJSON(r io.Reader) ([]Row, []LineError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, nil, fmt.Errorf("line 1: expected a JSON array of logs")
+	}
+
+	var rows []Row
+	var lineErrors []LineError
+	for decoder.More() {
+		offset := int(decoder.InputOffset())
+		line := lineAt(data, offset)
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		record, err := decodeRecord(raw)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, Row{Line: line, Record: record})
+	}
+
+	return rows, lineErrors, nil
+}
+
+// parseNDJSON parses one JSON record per line
+func parseNDJSON(r io.Reader) ([]Row, []LineError, error) {
+	var rows []Row
+	var lineErrors []LineError
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		record, err := decodeRecord([]byte(text))
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, Row{Line: line, Record: record})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return rows, lineErrors, nil
+}
+
+// parseCSV parses CSV rows with a header naming the columns
+func parseCSV(r io.Reader) ([]Row, []LineError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("line 1: failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"habit", "logged_at"} {
+		if _, exists := columns[required]; !exists {
+			return nil, nil, fmt.Errorf("line 1: missing %s column", required)
+		}
+	}
+
+	var rows []Row
+	var lineErrors []LineError
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			lineErrors = append(lineErrors, LineError{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, exists := columns[name]; exists && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		record := Record{
+			Habit:    field("habit"),
+			Duration: field("duration"),
+			Notes:    field("notes"),
+			Source:   field("source"),
+		}
+		if record.LoggedAt, err = parseTime(field("logged_at")); err != nil {
+			lineErrors = append(lineErrors, LineError{Line: line, Err: err})
+			continue
+		}
+		if startedAt := field("started_at"); startedAt != "" {
+			if record.StartedAt, err = parseTime(startedAt); err != nil {
+				lineErrors = append(lineErrors, LineError{Line: line, Err: fmt.Errorf("invalid started_at: %s", startedAt)})
+				continue
+			}
+		}
+		if distance := field("distance"); distance != "" {
+			if record.Distance, err = strconv.ParseFloat(distance, 64); err != nil {
+				lineErrors = append(lineErrors, LineError{Line: line, Err: fmt.Errorf("invalid distance: %s", distance)})
+				continue
+			}
+		}
+		if count := field("count"); count != "" {
+			if record.Count, err = strconv.Atoi(count); err != nil {
+				lineErrors = append(lineErrors, LineError{Line: line, Err: fmt.Errorf("invalid count: %s", count)})
+				continue
+			}
+		}
+		rows = append(rows, Row{Line: line, Record: record})
+	}
+
+	return rows, lineErrors, nil
+}
+
+// decodeRecord decodes a single JSON record, also accepting full log objects
+func decodeRecord(data []byte) (Record, error) {
+	var raw struct {
+		Habit     string  `json:"habit"`
+		HabitName string  `json:"habit_name"`
+		LoggedAt  string  `json:"logged_at"`
+		StartedAt string  `json:"started_at"`
+		Duration  string  `json:"duration"`
+		Count     int     `json:"count"`
+		Notes     string  `json:"notes"`
+		Source    string  `json:"source"`
+		Distance  float64 `json:"distance"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Record{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	record := Record{Habit: raw.Habit, Duration: raw.Duration, Count: raw.Count, Notes: raw.Notes, Source: raw.Source, Distance: raw.Distance}
+	if record.Habit == "" {
+		record.Habit = raw.HabitName
+	}
+
+	loggedAt, err := parseTime(raw.LoggedAt)
+	if err != nil {
+		return Record{}, err
+	}
+	record.LoggedAt = loggedAt
+
+	if raw.StartedAt != "" {
+		if record.StartedAt, err = parseTime(raw.StartedAt); err != nil {
+			return Record{}, fmt.Errorf("invalid started_at: %s", raw.StartedAt)
+		}
+	}
+
+	return record, nil
+}
+
+// parseTime parses RFC 3339 timestamps and a few common local formats
+func parseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("missing logged_at")
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid logged_at: %s", value)
+}
+
+// lineAt returns the line number of the first non-space byte at or after offset
+func lineAt(data []byte, offset int) int {
+	for offset < len(data) && (data[offset] == ' ' || data[offset] == '\t' || data[offset] == '\n' || data[offset] == '\r' || data[offset] == ',') {
+		offset++
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
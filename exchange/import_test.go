@@ -0,0 +1,38 @@
+package exchange
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseDurations(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     string // the normalized duration, or the error
+	}{
+		{"30m", "30m"},
+		{"90m", "1h30m"},
+		{"1h30m", "1h30m"},
+		{"1.5h", "1h30m"},
+		{"2h", "2h"},
+		{"2", "2h"},
+		{"30x", "invalid duration: 30x (use the count field for counts)"},
+		{"30s", "invalid duration format: 30s"},
+	}
+	for _, test := range tests {
+		csv := "habit,logged_at,duration\ncode,2026-10-17T09:00:00Z," + test.duration + "\n"
+		rows, err := Parse(strings.NewReader(csv), "csv")
+		var validationErr *ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			if got := validationErr.Errors[0].Err.Error(); got != test.want {
+				t.Errorf("%s: got error %q, want %q", test.duration, got, test.want)
+			}
+		case err != nil:
+			t.Errorf("%s: %v", test.duration, err)
+		case rows[0].Record.Duration != test.want:
+			t.Errorf("%s: got %s, want %s", test.duration, rows[0].Record.Duration, test.want)
+		}
+	}
+}
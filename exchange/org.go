@@ -0,0 +1,226 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// orgTimeFormat is the layout of org-mode timestamps, without the brackets
+const orgTimeFormat = "2006-01-02 Mon 15:04"
+
+// orgIDProperty links an org entry to its log
+const orgIDProperty = "LAZYTRACK_ID"
+
+var (
+	orgHeadingPattern  = regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`)
+	orgTagsPattern     = regexp.MustCompile(`\s+:[\w@#%:]+:$`)
+	orgPropertyPattern = regexp.MustCompile(`^:([A-Za-z_]+):\s*(.*)$`)
+	orgClockPattern    = regexp.MustCompile(`^CLOCK:\s*\[([^\]]+)\]--\[([^\]]+)\]`)
+)
+
+// WriteOrg writes logs as an org-mode file with a heading per habit and a
+// subheading per log. Time-ranged logs get a CLOCK line, counts a LOGGED property.
+func WriteOrg(w io.Writer, logs []types.Log) error {
+	byHabit := make(map[string][]types.Log)
+	for _, log := range sortedLogs(logs) {
+		byHabit[log.HabitName] = append(byHabit[log.HabitName], log)
+	}
+	habits := make([]string, 0, len(byHabit))
+	for habit := range byHabit {
+		habits = append(habits, habit)
+	}
+	sort.Strings(habits)
+
+	var out strings.Builder
+	out.WriteString("#+TITLE: lazytrack\n")
+	out.WriteString("# Edit freely and run 'lazytrack sync' again. New entries need no LAZYTRACK_ID.\n")
+	for _, habit := range habits {
+		fmt.Fprintf(&out, "* %s\n", habit)
+		for _, log := range byHabit[habit] {
+			writeOrgEntry(&out, NewRecord(log), log.ID)
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// writeOrgEntry writes a single log as an org subheading
+func writeOrgEntry(out *strings.Builder, record Record, id int) {
+	title := singleLine(record.Notes)
+	if title == "" {
+		title = record.Habit
+	}
+	fmt.Fprintf(out, "** %s\n", title)
+
+	start, end, hasSpan := recordSpan(record)
+	properties := make([][2]string, 0, 4)
+	if id > 0 {
+		properties = append(properties, [2]string{orgIDProperty, strconv.Itoa(id)})
+	}
+	if record.Count > 0 {
+		properties = append(properties, [2]string{"COUNT", strconv.Itoa(record.Count)})
+	} else if parsed, err := parser.ParseDuration(record.Duration); err == nil && (!hasSpan || !sameDuration(record.Duration, spanDuration(start, end))) {
+		properties = append(properties, [2]string{"DURATION", parser.FormatDuration(parsed)})
+	}
+	if !hasSpan {
+		properties = append(properties, [2]string{"LOGGED", "[" + record.LoggedAt.Local().Format(orgTimeFormat) + "]"})
+	}
+
+	if len(properties) > 0 {
+		out.WriteString(":PROPERTIES:\n")
+		for _, property := range properties {
+			fmt.Fprintf(out, ":%s: %s\n", property[0], property[1])
+		}
+		out.WriteString(":END:\n")
+	}
+	if hasSpan {
+		minutes := int(end.Sub(start).Minutes())
+		fmt.Fprintf(out, "CLOCK: [%s]--[%s] => %2d:%02d\n", start.Format(orgTimeFormat), end.Format(orgTimeFormat), minutes/60, minutes%60)
+	}
+}
+
+// orgEntry collects the lines of an org heading
+type orgEntry struct {
+	line       int
+	notes      string
+	properties map[string]string
+	clocks     [][2]time.Time
+	clockLines []int
+}
+
+// parseOrg parses an org-mode file. Level 1 headings name the habit; CLOCK
+// lines below them (directly or under subheadings) become logs, with the
+// subheading as notes. Entries without a clock need a LOGGED property.
+func parseOrg(r io.Reader) ([]Row, []LineError, error) {
+	var rows []Row
+	var lineErrors []LineError
+	var habit string
+	var entry *orgEntry
+
+	flush := func() {
+		if entry == nil {
+			return
+		}
+		entryRows, err := orgRows(habit, entry)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{Line: entry.line, Err: err})
+		}
+		rows = append(rows, entryRows...)
+		entry = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		if match := orgHeadingPattern.FindStringSubmatch(scanner.Text()); match != nil {
+			flush()
+			title := match[2]
+			if len(match[1]) == 1 {
+				habit = strings.ToLower(orgTagsPattern.ReplaceAllString(title, ""))
+				title = ""
+			} else if strings.EqualFold(title, habit) {
+				title = ""
+			}
+			entry = &orgEntry{line: line, notes: title, properties: make(map[string]string)}
+			continue
+		}
+		if entry == nil {
+			continue
+		}
+
+		if match := orgClockPattern.FindStringSubmatch(text); match != nil {
+			start, errStart := parseOrgTime(match[1])
+			end, errEnd := parseOrgTime(match[2])
+			if errStart != nil || errEnd != nil {
+				lineErrors = append(lineErrors, LineError{Line: line, Err: fmt.Errorf("invalid CLOCK line")})
+				continue
+			}
+			entry.clocks = append(entry.clocks, [2]time.Time{start, end})
+			entry.clockLines = append(entry.clockLines, line)
+		} else if match := orgPropertyPattern.FindStringSubmatch(text); match != nil {
+			entry.properties[strings.ToUpper(match[1])] = match[2]
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return rows, lineErrors, nil
+}
+
+// orgRows converts an org heading to rows, one per CLOCK line. Only the
+// first clock keeps the heading's ID; any others are new entries.
+func orgRows(habit string, entry *orgEntry) ([]Row, error) {
+	if habit == "" {
+		return nil, nil
+	}
+
+	var id int
+	if value, exists := entry.properties[orgIDProperty]; exists {
+		var err error
+		if id, err = strconv.Atoi(value); err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid %s: %s", orgIDProperty, value)
+		}
+	}
+
+	base := Record{Habit: habit, Notes: entry.notes, Duration: entry.properties["DURATION"]}
+	if value, exists := entry.properties["COUNT"]; exists {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid COUNT: %s", value)
+		}
+		base.Count = count
+	}
+
+	if len(entry.clocks) == 0 {
+		logged, exists := entry.properties["LOGGED"]
+		if !exists {
+			return nil, nil // a heading without time
+		}
+		loggedAt, err := parseOrgTime(strings.Trim(logged, "[]<>"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOGGED: %s", logged)
+		}
+		base.LoggedAt = loggedAt
+		return []Row{{Line: entry.line, ID: id, Record: base}}, nil
+	}
+
+	var rows []Row
+	for i, clock := range entry.clocks {
+		record := base
+		record.StartedAt, record.LoggedAt = clock[0], clock[1]
+		if record.Duration == "" && record.Count == 0 {
+			record.Duration = spanDuration(clock[0], clock[1])
+		}
+		rows = append(rows, Row{Line: entry.clockLines[i], ID: id, Record: record})
+		id = 0
+	}
+	return rows, nil
+}
+
+// parseOrgTime parses an org timestamp like "2026-10-17 Sat 09:00". The
+// weekday is optional, since it's implied by the date.
+func parseOrgTime(value string) (time.Time, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 3 {
+		fields = []string{fields[0], fields[2]}
+	}
+	if len(fields) != 2 {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %s", value)
+	}
+	return time.ParseInLocation(textDateFormat+" "+textTimeFormat, fields[0]+" "+fields[1], time.Local)
+}
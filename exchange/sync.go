@@ -0,0 +1,100 @@
+package exchange
+
+import (
+	"sort"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Snapshot holds the text line of every entry as of the last sync, by log ID
+type Snapshot map[int]string
+
+// Conflict is an entry edited both in the file and in lazytrack since the last sync
+type Conflict struct {
+	ID    int
+	File  string // the entry in the file, empty if it was deleted there
+	Store string // the log in lazytrack, empty if it was deleted there
+}
+
+// SyncPlan lists the changes that bring the store in line with a file
+type SyncPlan struct {
+	Add       []Row       // entries new in the file
+	Update    []Row       // entries edited in the file
+	Delete    []int       // logs deleted from the file
+	Conflicts []Conflict  // entries edited on both sides
+	Keep      map[int]Row // file versions of conflicting entries, written back unchanged
+}
+
+// PlanSync compares the entries of a file and the logs of the store with the
+// snapshot of the last sync. An entry changed on one side only takes that
+// side's version. An entry changed on both sides (differently) is a conflict:
+// prefer "file" or "store" picks a side, otherwise both sides are left as they
+// are and the conflict is reported until it's resolved.
+func PlanSync(rows []Row, logs []types.Log, snapshot Snapshot, prefer string) SyncPlan {
+	plan := SyncPlan{Keep: make(map[int]Row)}
+
+	storeLines := make(map[int]string, len(logs))
+	for _, log := range logs {
+		storeLines[log.ID] = TextLine(NewRecord(log))
+	}
+
+	inFile := make(map[int]bool)
+	for _, row := range rows {
+		if row.ID == 0 || inFile[row.ID] {
+			// New entries, and copies of a line that kept its ID
+			row.ID = 0
+			plan.Add = append(plan.Add, row)
+			continue
+		}
+		inFile[row.ID] = true
+
+		fileLine := TextLine(row.Record)
+		storeLine, inStore := storeLines[row.ID]
+		lastLine, synced := snapshot[row.ID]
+
+		switch {
+		case !inStore && !synced:
+			// The ID doesn't belong to this store, e.g. a file copied from another machine
+			row.ID = 0
+			plan.Add = append(plan.Add, row)
+		case fileLine == storeLine:
+			// Unchanged, or changed the same way on both sides
+		case synced && fileLine == lastLine:
+			// Changed or deleted in the store only; the file is rewritten from the store
+		case synced && storeLine == lastLine:
+			plan.Update = append(plan.Update, row)
+		case prefer == "file":
+			if inStore {
+				plan.Update = append(plan.Update, row)
+			} else {
+				row.ID = 0
+				plan.Add = append(plan.Add, row)
+			}
+		case prefer == "store":
+		default:
+			plan.Conflicts = append(plan.Conflicts, Conflict{ID: row.ID, File: fileLine, Store: storeLine})
+			plan.Keep[row.ID] = row
+		}
+	}
+
+	// Entries of the last sync that are gone from the file were deleted there
+	for id, lastLine := range snapshot {
+		storeLine, inStore := storeLines[id]
+		if inFile[id] || !inStore {
+			continue
+		}
+		switch {
+		case storeLine == lastLine || prefer == "file":
+			plan.Delete = append(plan.Delete, id)
+		case prefer == "store":
+		default:
+			plan.Conflicts = append(plan.Conflicts, Conflict{ID: id, Store: storeLine})
+		}
+	}
+
+	sort.Ints(plan.Delete)
+	sort.Slice(plan.Conflicts, func(i, j int) bool {
+		return plan.Conflicts[i].ID < plan.Conflicts[j].ID
+	})
+	return plan
+}
@@ -0,0 +1,134 @@
+package exchange
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// planSummary is what a sync plan does, by line and log ID
+type planSummary struct {
+	Add       []string
+	Update    []int
+	Delete    []int
+	Conflicts []int
+	Keep      []int
+}
+
+// summarize summarizes a plan, naming added rows by their text line
+func summarize(plan SyncPlan) planSummary {
+	var s planSummary
+	for _, row := range plan.Add {
+		if row.ID != 0 {
+			s.Add = append(s.Add, "kept its ID")
+		}
+		s.Add = append(s.Add, TextLine(row.Record))
+	}
+	for _, row := range plan.Update {
+		s.Update = append(s.Update, row.ID)
+	}
+	s.Delete = plan.Delete
+	for _, conflict := range plan.Conflicts {
+		s.Conflicts = append(s.Conflicts, conflict.ID)
+	}
+	for id := range plan.Keep {
+		s.Keep = append(s.Keep, id)
+	}
+	return s
+}
+
+func TestPlanSync(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2026, 10, 17, hour, 0, 0, 0, time.Local)
+	}
+	// The store as of the last sync, which the file was written from
+	synced := []types.Log{
+		{ID: 1, HabitName: "code", Duration: "1h", LoggedAt: at(9)},
+		{ID: 2, HabitName: "read", Duration: "30m", LoggedAt: at(20)},
+	}
+	snapshot := make(Snapshot)
+	for _, log := range synced {
+		snapshot[log.ID] = TextLine(NewRecord(log))
+	}
+	row := func(id int, habit, duration string, hour int) Row {
+		return Row{ID: id, Record: Record{Habit: habit, Duration: duration, LoggedAt: at(hour)}}
+	}
+	unchanged := []Row{row(1, "code", "1h", 9), row(2, "read", "30m", 20)}
+	editedCode := []types.Log{{ID: 1, HabitName: "code", Duration: "2h", LoggedAt: at(9)}, synced[1]}
+	fileEdit := []Row{row(1, "code", "1h30m", 9), row(2, "read", "30m", 20)}
+	water := Row{Record: Record{Habit: "water", Count: 8, LoggedAt: at(14)}}
+	copied := row(2, "read", "45m", 21)
+	foreign := row(7, "run", "40m", 7)
+	line := func(row Row) string {
+		return TextLine(row.Record)
+	}
+
+	tests := []struct {
+		name   string
+		rows   []Row
+		logs   []types.Log
+		prefer string
+		want   planSummary
+	}{
+		{name: "nothing changed", rows: unchanged, logs: synced},
+		{
+			name: "new entry", rows: append(unchanged, water), logs: synced,
+			want: planSummary{Add: []string{line(water)}},
+		},
+		{
+			name: "copied line", rows: append(unchanged, copied), logs: synced,
+			want: planSummary{Add: []string{line(copied)}},
+		},
+		{
+			name: "ID of another store", rows: append(unchanged, foreign), logs: synced,
+			want: planSummary{Add: []string{line(foreign)}},
+		},
+		{name: "edited in the file", rows: fileEdit, logs: synced, want: planSummary{Update: []int{1}}},
+		{name: "edited in the store", rows: unchanged, logs: editedCode},
+		{name: "edited the same way", rows: []Row{row(1, "code", "2h", 9), unchanged[1]}, logs: editedCode},
+		{name: "edited on both sides", rows: fileEdit, logs: editedCode, want: planSummary{Conflicts: []int{1}, Keep: []int{1}}},
+		{name: "edited on both sides, file preferred", rows: fileEdit, logs: editedCode, prefer: "file", want: planSummary{Update: []int{1}}},
+		{name: "edited on both sides, store preferred", rows: fileEdit, logs: editedCode, prefer: "store"},
+		{name: "deleted from the file", rows: unchanged[1:], logs: synced, want: planSummary{Delete: []int{1}}},
+		{name: "deleted from the store", rows: unchanged, logs: synced[1:]},
+		{name: "deleted from the file, edited in the store", rows: unchanged[1:], logs: editedCode, want: planSummary{Conflicts: []int{1}}},
+		{name: "deleted from the file, edited in the store, file preferred", rows: unchanged[1:], logs: editedCode, prefer: "file", want: planSummary{Delete: []int{1}}},
+		{name: "deleted from the file, edited in the store, store preferred", rows: unchanged[1:], logs: editedCode, prefer: "store"},
+		{name: "edited in the file, deleted from the store", rows: fileEdit, logs: synced[1:], want: planSummary{Conflicts: []int{1}, Keep: []int{1}}},
+		{
+			name: "edited in the file, deleted from the store, file preferred", rows: fileEdit, logs: synced[1:], prefer: "file",
+			want: planSummary{Add: []string{line(fileEdit[0])}},
+		},
+		{name: "edited in the file, deleted from the store, store preferred", rows: fileEdit, logs: synced[1:], prefer: "store"},
+	}
+	for _, test := range tests {
+		got := summarize(PlanSync(test.rows, test.logs, snapshot, test.prefer))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPlanSyncFirstSync(t *testing.T) {
+	// Without a snapshot, lines that match the store are already synced
+	at := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+	logs := []types.Log{{ID: 1, HabitName: "code", Duration: "1h", LoggedAt: at}}
+	rows := []Row{
+		{ID: 1, Record: Record{Habit: "code", Duration: "1h", LoggedAt: at}},
+		{ID: 0, Record: Record{Habit: "code", Duration: "1h", LoggedAt: at.Add(time.Hour)}},
+	}
+	plan := PlanSync(rows, logs, nil, "")
+	if len(plan.Add) != 1 || len(plan.Update) != 0 || len(plan.Delete) != 0 || len(plan.Conflicts) != 0 {
+		t.Errorf("got %+v, want only the new entry added", plan)
+	}
+
+	// Syncing what the plan leads to again changes nothing
+	snapshot := Snapshot{1: TextLine(rows[0].Record), 2: TextLine(rows[1].Record)}
+	logs = append(logs, types.Log{ID: 2, HabitName: "code", Duration: "1h", LoggedAt: at.Add(time.Hour)})
+	rows[1].ID = 2
+	if got := summarize(PlanSync(rows, logs, snapshot, "")); !reflect.DeepEqual(got, planSummary{}) {
+		t.Errorf("syncing again: got %+v, want no changes", got)
+	}
+}
@@ -0,0 +1,250 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Layouts of the plain-text format
+const (
+	textDateFormat = "2006-01-02"
+	textTimeFormat = "15:04"
+)
+
+// textHeader explains the plain-text format at the top of exported files
+const textHeader = `# lazytrack log: one entry per line, edit freely and run 'lazytrack sync' again.
+#   2026-10-17 09:00-10:30 code #work notes   time range (the duration is the range)
+#   2026-10-17 07:00-07:40 run 35m notes      time range with a different duration
+#   2026-10-17 18:00 read 45m notes           logged at 18:00, lasting 45 minutes
+#   2026-10-17 14:00 water 8x                 count
+# The ^id at the end links a line to its log; leave it out for new entries.
+`
+
+// TextLine formats a log as a line of the plain-text format, without its ID.
+// The line holds everything the format can express, so comparing lines tells
+// whether an entry was edited.
+func TextLine(record Record) string {
+	parts := []string{record.LoggedAt.Local().Format(textDateFormat + " " + textTimeFormat)}
+	amount := record.Duration
+	if parsed, err := parser.ParseDuration(record.Duration); err == nil {
+		amount = parser.FormatDuration(parsed)
+	}
+	if record.Count > 0 {
+		amount = fmt.Sprintf("%dx", record.Count)
+	}
+
+	if start, end, ok := recordSpan(record); ok {
+		parts[0] = start.Format(textDateFormat+" "+textTimeFormat) + "-" + end.Format(textTimeFormat)
+		if record.Count == 0 && sameDuration(record.Duration, spanDuration(start, end)) {
+			amount = ""
+		}
+	}
+
+	parts = append(parts, record.Habit)
+	if amount != "" {
+		parts = append(parts, amount)
+	}
+	if notes := singleLine(record.Notes); notes != "" {
+		parts = append(parts, notes)
+	}
+	return strings.Join(parts, " ")
+}
+
+// WriteText writes logs in the plain-text format, oldest first
+func WriteText(w io.Writer, logs []types.Log) error {
+	if _, err := io.WriteString(w, textHeader); err != nil {
+		return err
+	}
+	for _, log := range sortedLogs(logs) {
+		line := TextLine(NewRecord(log))
+		if log.ID > 0 {
+			line += fmt.Sprintf(" ^%d", log.ID)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseText parses the plain-text format. Blank lines and lines starting
+// with # are skipped.
+func parseText(r io.Reader) ([]Row, []LineError, error) {
+	var rows []Row
+	var lineErrors []LineError
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		row, err := parseTextLine(text)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{Line: line, Err: err})
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return rows, lineErrors, nil
+}
+
+// parseTextLine parses a single entry: DATE TIME[-TIME] HABIT [AMOUNT] [NOTES] [^ID]
+func parseTextLine(text string) (Row, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 {
+		return Row{}, fmt.Errorf("expected 'DATE TIME HABIT [AMOUNT] [NOTES]'")
+	}
+
+	var row Row
+	if last := fields[len(fields)-1]; strings.HasPrefix(last, "^") {
+		id, err := strconv.Atoi(last[1:])
+		if err != nil || id <= 0 {
+			return Row{}, fmt.Errorf("invalid id: %s", last)
+		}
+		row.ID = id
+		fields = fields[:len(fields)-1]
+	}
+
+	day, err := time.ParseInLocation(textDateFormat, fields[0], time.Local)
+	if err != nil {
+		return Row{}, fmt.Errorf("invalid date: %s", fields[0])
+	}
+	startText, endText, isRange := strings.Cut(fields[1], "-")
+	start, err := clockTime(day, startText)
+	if err != nil {
+		return Row{}, err
+	}
+
+	record := &row.Record
+	record.Habit = fields[2]
+	rest := fields[3:]
+
+	var amount string
+	if len(rest) > 0 && isAmount(rest[0]) {
+		amount, rest = rest[0], rest[1:]
+	}
+	if isRange {
+		end, err := clockTime(day, endText)
+		if err != nil {
+			return Row{}, err
+		}
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1) // the range ends after midnight
+		}
+		record.StartedAt, record.LoggedAt = start, end
+		if amount == "" {
+			amount = spanDuration(start, end)
+		}
+	} else {
+		if amount == "" {
+			return Row{}, fmt.Errorf("missing duration or count after the habit (or use a time range)")
+		}
+		record.LoggedAt = start
+	}
+
+	if parser.IsCountBased(amount) {
+		parsed, err := parser.ParseDuration(amount)
+		if err != nil {
+			return Row{}, err
+		}
+		record.Count = parsed.Hours // counts are kept in the hours field
+	} else {
+		record.Duration = amount
+	}
+	record.Notes = strings.Join(rest, " ")
+	return row, nil
+}
+
+// clockTime parses a time of day on the given day
+func clockTime(day time.Time, value string) (time.Time, error) {
+	clock, err := time.Parse(textTimeFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %s", value)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local), nil
+}
+
+// isAmount checks if a word is a duration like 45m or a count like 8x. Bare
+// numbers are not amounts here, so notes may start with one.
+func isAmount(word string) bool {
+	if word[0] < '0' || word[0] > '9' || !strings.ContainsAny(word[len(word)-1:], "hmx") {
+		return false
+	}
+	_, err := parser.ParseDuration(word)
+	return err == nil
+}
+
+// recordSpan returns the time range of a record, rounded to minutes
+func recordSpan(record Record) (time.Time, time.Time, bool) {
+	var start, end time.Time
+	if record.Count > 0 {
+		if record.StartedAt.IsZero() {
+			return time.Time{}, time.Time{}, false
+		}
+		start, end = record.StartedAt, record.LoggedAt
+	} else {
+		var ok bool
+		if start, end, ok = LogSpan(record.Log()); !ok {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	start = start.Local().Truncate(time.Minute)
+	end = end.Local().Truncate(time.Minute)
+	if !end.After(start) || end.Sub(start) >= 24*time.Hour {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// spanDuration formats the length of a time range as a duration
+func spanDuration(start, end time.Time) string {
+	minutes := int(end.Sub(start).Minutes())
+	return parser.FormatDuration(types.ParsedDuration{Hours: minutes / 60, Minutes: minutes % 60, IsValid: true})
+}
+
+// sameDuration checks if two durations are the same number of minutes
+func sameDuration(a, b string) bool {
+	parsedA, errA := parser.ParseDuration(a)
+	parsedB, errB := parser.ParseDuration(b)
+	return errA == nil && errB == nil && parser.GetTotalMinutes(parsedA) == parser.GetTotalMinutes(parsedB)
+}
+
+// singleLine joins the lines of a note
+func singleLine(notes string) string {
+	return strings.Join(strings.Fields(notes), " ")
+}
+
+// sortedLogs returns logs ordered by when they happened
+func sortedLogs(logs []types.Log) []types.Log {
+	sorted := make([]types.Log, len(logs))
+	copy(sorted, logs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return logStart(sorted[i]).Before(logStart(sorted[j]))
+	})
+	return sorted
+}
+
+// logStart returns when a log started, or when it was logged if that's unknown
+func logStart(log types.Log) time.Time {
+	if start, _, ok := LogSpan(log); ok {
+		return start
+	}
+	return log.LoggedAt
+}
@@ -0,0 +1,28 @@
+package filelock
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrLocked is returned by TryLock when another process holds the lock
+var ErrLocked = errors.New("file is locked")
+
+// Lock locks a file exclusively, waiting until no other process holds it.
+// Locks belong to the open file: they're released by Unlock or by closing the
+// file (or when the process exits), and a second open file of the same path
+// can't take them, even in the same process.
+func Lock(f *os.File) error {
+	return lock(f, true)
+}
+
+// TryLock locks a file exclusively, failing with ErrLocked if another process
+// holds it
+func TryLock(f *os.File) error {
+	return lock(f, false)
+}
+
+// Unlock releases the lock of a file
+func Unlock(f *os.File) error {
+	return unlock(f)
+}
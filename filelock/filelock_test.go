@@ -0,0 +1,70 @@
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// open opens a lock file, closing it when the test ends
+func open(t *testing.T, path string) *os.File {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestTryLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock")
+	first, second := open(t, path), open(t, path)
+
+	if err := TryLock(first); err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if err := TryLock(second); !errors.Is(err, ErrLocked) {
+		t.Fatalf("TryLock of a locked file = %v, want ErrLocked", err)
+	}
+	if err := Unlock(first); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := TryLock(second); err != nil {
+		t.Fatalf("TryLock after Unlock: %v", err)
+	}
+
+	// Closing the file releases its lock
+	second.Close()
+	if err := TryLock(first); err != nil {
+		t.Fatalf("TryLock after Close: %v", err)
+	}
+}
+
+func TestLockWaits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock")
+	first, second := open(t, path), open(t, path)
+	if err := Lock(first); err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+
+	locked := make(chan error, 1)
+	go func() { locked <- Lock(second) }()
+	select {
+	case err := <-locked:
+		t.Fatalf("Lock of a locked file returned %v without waiting", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	Unlock(first)
+	select {
+	case err := <-locked:
+		if err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lock still waits after Unlock")
+	}
+}
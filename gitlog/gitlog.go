@@ -0,0 +1,195 @@
+package gitlog
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fieldSeparator separates the fields of each commit in git's output
+const fieldSeparator = "\x1f"
+
+// Commit is a single commit of a repository
+type Commit struct {
+	Hash    string
+	Email   string
+	When    time.Time
+	Subject string
+}
+
+// Session is a run of commits close enough together to count as one coding session
+type Session struct {
+	Start   time.Time // first commit minus the lead time
+	End     time.Time // last commit
+	Commits []Commit  // oldest first
+}
+
+// Options filters the commits read from a repository
+type Options struct {
+	Authors []string // author emails, any author if empty
+	Since   time.Time
+	Until   time.Time
+}
+
+// Run runs a git command in a repository and returns its output
+func Run(repo string, args ...string) (string, error) {
+	command := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+
+	output, err := command.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// TopLevel returns the root directory of the repository containing path
+func TopLevel(path string) (string, error) {
+	return Run(path, "rev-parse", "--show-toplevel")
+}
+
+// HooksDir returns the directory git runs hooks from, honoring core.hooksPath
+func HooksDir(repo string) (string, error) {
+	dir, err := Run(repo, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repo, dir)
+	}
+	return dir, nil
+}
+
+// UserEmail returns the email git uses for new commits in the repository
+func UserEmail(repo string) string {
+	email, err := Run(repo, "config", "user.email")
+	if err != nil {
+		return ""
+	}
+	return email
+}
+
+// ReadCommits reads the non-merge commits of a repository, oldest first
+func ReadCommits(repo string, opts Options) ([]Commit, error) {
+	args := []string{"log", "--no-merges", "--format=%H" + fieldSeparator + "%ae" + fieldSeparator + "%aI" + fieldSeparator + "%s"}
+	if !opts.Since.IsZero() {
+		args = append(args, "--since="+opts.Since.Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		args = append(args, "--until="+opts.Until.Format(time.RFC3339))
+	}
+
+	output, err := Run(repo, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make(map[string]bool)
+	for _, email := range opts.Authors {
+		authors[strings.ToLower(strings.TrimSpace(email))] = true
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, fieldSeparator, 4)
+		if len(fields) < 4 {
+			continue
+		}
+
+		commit := Commit{Hash: fields[0], Email: fields[1], Subject: fields[3]}
+		if len(authors) > 0 && !authors[strings.ToLower(commit.Email)] {
+			continue
+		}
+		if commit.When, err = time.Parse(time.RFC3339, fields[2]); err != nil {
+			return nil, fmt.Errorf("invalid commit date %q: %w", fields[2], err)
+		}
+
+		// --since and --until use the committer date, so filter by author date here
+		if (!opts.Since.IsZero() && commit.When.Before(opts.Since)) || (!opts.Until.IsZero() && !commit.When.Before(opts.Until)) {
+			continue
+		}
+		commits = append(commits, commit)
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].When.Before(commits[j].When)
+	})
+	return commits, nil
+}
+
+// Sessions groups commits into coding sessions. A commit more than gap after
+// the previous one starts a new session, and every session is assumed to have
+// started lead before its first commit.
+func Sessions(commits []Commit, gap, lead time.Duration) []Session {
+	var sessions []Session
+
+	for _, commit := range commits {
+		if n := len(sessions); n > 0 && commit.When.Sub(sessions[n-1].End) <= gap {
+			sessions[n-1].End = commit.When
+			sessions[n-1].Commits = append(sessions[n-1].Commits, commit)
+			continue
+		}
+
+		sessions = append(sessions, Session{
+			Start:   commit.When.Add(-lead),
+			End:     commit.When,
+			Commits: []Commit{commit},
+		})
+	}
+
+	return sessions
+}
+
+// ReadSessions reads the coding sessions of a repository that end in the range
+// of opts. A session running across opts.Since is read in full, so that
+// importing a recent range doesn't log just the end of a session.
+func ReadSessions(repo string, opts Options, gap, lead time.Duration) ([]Session, error) {
+	read := opts
+	for {
+		commits, err := ReadCommits(repo, read)
+		if err != nil {
+			return nil, err
+		}
+		// A commit less than gap after the start of the range may continue a
+		// session that started earlier
+		if read.Since.IsZero() || len(commits) == 0 || commits[0].When.Sub(read.Since) > gap {
+			sessions := Sessions(commits, gap, lead)
+			for len(sessions) > 0 && !opts.Since.IsZero() && sessions[0].End.Before(opts.Since) {
+				sessions = sessions[1:]
+			}
+			return sessions, nil
+		}
+		read.Since = read.Since.Add(-max(gap, 24*time.Hour))
+	}
+}
+
+// Key identifies a session by its repository, authors and start. Sessions
+// grow and merge as commits are added, so logs are matched by Prefix and
+// overlap rather than by their whole key.
+func (s Session) Key(repo string, authors []string) string {
+	return fmt.Sprintf("%s%d", Prefix(repo, authors), s.Start.Unix())
+}
+
+// Prefix starts the keys of the sessions of a repository's commits by authors
+// (any author if empty)
+func Prefix(repo string, authors []string) string {
+	emails := make([]string, 0, len(authors))
+	for _, email := range authors {
+		emails = append(emails, strings.ToLower(strings.TrimSpace(email)))
+	}
+	sort.Strings(emails)
+	return "git:" + repo + ":" + strings.Join(emails, ",") + "@"
+}
+
+// Overlaps reports whether a session overlaps the time from start to end
+func (s Session) Overlaps(start, end time.Time) bool {
+	return !start.After(s.End) && !end.Before(s.Start)
+}
@@ -0,0 +1,143 @@
+package gitlog
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// newTestRepo creates an empty repository
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	if _, err := Run(repo, "init", "-q"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	return repo
+}
+
+// commit adds an empty commit by an author at a time
+func commit(t *testing.T, repo, email string, when time.Time, subject string) {
+	t.Helper()
+	command := exec.Command("git", "-C", repo, "commit", "-q", "--allow-empty", "--no-verify", "-m", subject)
+	date := when.Format(time.RFC3339)
+	command.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL="+email, "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL="+email, "GIT_COMMITTER_DATE="+date,
+	)
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v: %s", err, output)
+	}
+}
+
+func TestReadCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	commit(t, repo, "me@example.com", base, "first")
+	commit(t, repo, "other@example.com", base.Add(time.Hour), "theirs")
+	commit(t, repo, "Me@Example.com", base.Add(2*time.Hour), "second")
+	commit(t, repo, "me@example.com", base.Add(3*time.Hour), "third")
+
+	commits, err := ReadCommits(repo, Options{Authors: []string{" me@example.com "}, Until: base.Add(3 * time.Hour)})
+	if err != nil {
+		t.Fatalf("ReadCommits: %v", err)
+	}
+	var subjects []string
+	for _, commit := range commits {
+		subjects = append(subjects, commit.Subject)
+	}
+	if len(subjects) != 2 || subjects[0] != "first" || subjects[1] != "second" {
+		t.Errorf("got %q, want [first second]", subjects)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) Commit {
+		return Commit{Hash: time.Duration(minutes).String(), When: base.Add(time.Duration(minutes) * time.Minute)}
+	}
+	commits := []Commit{at(0), at(60), at(180), at(181), at(400)}
+
+	sessions := Sessions(commits, 2*time.Hour, 30*time.Minute)
+	want := []struct {
+		start, end time.Duration
+		commits    int
+	}{
+		{-30 * time.Minute, 181 * time.Minute, 4},
+		{370 * time.Minute, 400 * time.Minute, 1},
+	}
+	if len(sessions) != len(want) {
+		t.Fatalf("got %d sessions, want %d", len(sessions), len(want))
+	}
+	for i, w := range want {
+		s := sessions[i]
+		if !s.Start.Equal(base.Add(w.start)) || !s.End.Equal(base.Add(w.end)) || len(s.Commits) != w.commits {
+			t.Errorf("session %d: got %v-%v with %d commits, want %v-%v with %d",
+				i, s.Start, s.End, len(s.Commits), base.Add(w.start), base.Add(w.end), w.commits)
+		}
+	}
+}
+
+func TestReadSessionsReadsAcrossSince(t *testing.T) {
+	repo := newTestRepo(t)
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	// A session before since, one that runs across it and one after it
+	commit(t, repo, "me@example.com", base.Add(-40*time.Hour), "old")
+	for i := 0; i < 30; i++ {
+		commit(t, repo, "me@example.com", base.Add(time.Duration(i-28)*time.Hour), "long")
+	}
+	commit(t, repo, "me@example.com", base.Add(6*time.Hour), "new")
+
+	sessions, err := ReadSessions(repo, Options{Since: base}, 2*time.Hour, 30*time.Minute)
+	if err != nil {
+		t.Fatalf("ReadSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	if n := len(sessions[0].Commits); n != 30 {
+		t.Errorf("session across since has %d commits, want all 30", n)
+	}
+	if want := base.Add(-28*time.Hour - 30*time.Minute); !sessions[0].Start.Equal(want) {
+		t.Errorf("session across since starts at %v, want %v", sessions[0].Start, want)
+	}
+	if sessions[1].Commits[0].Subject != "new" {
+		t.Errorf("second session starts with %q, want new", sessions[1].Commits[0].Subject)
+	}
+}
+
+func TestKey(t *testing.T) {
+	session := Session{Start: time.Unix(1772441000, 0)}
+	key := session.Key("/src/app", []string{"B@example.com", "a@example.com "})
+	if want := "git:/src/app:a@example.com,b@example.com@1772441000"; key != want {
+		t.Errorf("got %q, want %q", key, want)
+	}
+	if other := (Session{Start: session.Start}).Key("/src/app", nil); other == key {
+		t.Errorf("sessions of any author have the key of some authors' sessions: %q", other)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	session := Session{Start: base, End: base.Add(time.Hour)}
+	tests := []struct {
+		start, end time.Duration
+		want       bool
+	}{
+		{-time.Hour, -time.Minute, false},
+		{-time.Hour, 0, true},
+		{10 * time.Minute, 20 * time.Minute, true},
+		{-time.Hour, 2 * time.Hour, true},
+		{time.Hour, 2 * time.Hour, true},
+		{time.Hour + time.Minute, 2 * time.Hour, false},
+	}
+	for _, tt := range tests {
+		if got := session.Overlaps(base.Add(tt.start), base.Add(tt.end)); got != tt.want {
+			t.Errorf("Overlaps(%v, %v) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package heartbeat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultIdleTimeout is the longest pause between heartbeats of one session,
+// the same default WakaTime uses
+const DefaultIdleTimeout = 15 * time.Minute
+
+// sessionRetention is how long sessions are kept around to absorb late heartbeats
+const sessionRetention = 24 * time.Hour
+
+// Heartbeat is a WakaTime heartbeat sent by an editor plugin
+type Heartbeat struct {
+	Entity   string  `json:"entity"`
+	Type     string  `json:"type,omitempty"`
+	Category string  `json:"category,omitempty"`
+	Time     float64 `json:"time"` // unix seconds
+	Project  string  `json:"project,omitempty"`
+	Branch   string  `json:"branch,omitempty"`
+	Language string  `json:"language,omitempty"`
+	IsWrite  bool    `json:"is_write,omitempty"`
+}
+
+// Timestamp returns the time of the heartbeat
+func (h Heartbeat) Timestamp() time.Time {
+	seconds, fraction := math.Modf(h.Time)
+	return time.Unix(int64(seconds), int64(fraction*1e9))
+}
+
+// Validate checks that a heartbeat can be aggregated
+func (h Heartbeat) Validate() error {
+	if h.Time <= 0 {
+		return fmt.Errorf("missing time")
+	}
+	if strings.TrimSpace(h.Entity) == "" {
+		return fmt.Errorf("missing entity")
+	}
+	return nil
+}
+
+// Session is a run of heartbeats for one project and language
+type Session struct {
+	ID         string // stays the same while the session grows
+	Project    string
+	Language   string
+	Start      time.Time
+	End        time.Time
+	Heartbeats int
+	MergedInto string // the ID of the session this one was joined to, which covers it now
+}
+
+// Duration returns the time covered by the session
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Aggregator groups heartbeats into sessions per project and language.
+// It is safe for concurrent use.
+type Aggregator struct {
+	IdleTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions map[string][]*Session // by project and language
+	latest   time.Time
+}
+
+// NewAggregator creates an aggregator with the given idle timeout
+func NewAggregator(idleTimeout time.Duration) *Aggregator {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+	return &Aggregator{IdleTimeout: idleTimeout, sessions: make(map[string][]*Session)}
+}
+
+// Add adds heartbeats and returns the sessions they changed. Heartbeats may
+// arrive out of order, e.g. when a plugin sends its offline queue, so one may
+// fill the gap between two sessions; the later session is then joined to the
+// earlier one, and returned with MergedInto set.
+func (a *Aggregator) Add(heartbeats []Heartbeat) []Session {
+	sorted := make([]Heartbeat, len(heartbeats))
+	copy(sorted, heartbeats)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time < sorted[j].Time
+	})
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	changed := make(map[*Session]bool)
+	mergedInto := make(map[*Session]*Session)
+	var order []*Session
+	for _, heartbeat := range sorted {
+		session, merged := a.add(heartbeat)
+		for _, s := range append(merged, session) {
+			if !changed[s] {
+				changed[s] = true
+				order = append(order, s)
+			}
+		}
+		for _, s := range merged {
+			mergedInto[s] = session
+		}
+	}
+	a.prune()
+
+	result := make([]Session, 0, len(order))
+	for _, session := range order {
+		s := *session
+		// A session may have been merged into one that was merged in turn
+		for into := mergedInto[session]; into != nil; into = mergedInto[into] {
+			s.MergedInto = into.ID
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// add adds a single heartbeat to the session it belongs to, and returns the
+// sessions merged into that one
+func (a *Aggregator) add(heartbeat Heartbeat) (*Session, []*Session) {
+	project := strings.TrimSpace(heartbeat.Project)
+	if project == "" {
+		project = "unknown"
+	}
+	language := strings.TrimSpace(heartbeat.Language)
+	key := strings.ToLower(project + "|" + language)
+	when := heartbeat.Timestamp()
+	if when.After(a.latest) {
+		a.latest = when
+	}
+
+	var matches, others []*Session
+	for _, session := range a.sessions[key] {
+		if when.Before(session.Start.Add(-a.IdleTimeout)) || when.After(session.End.Add(a.IdleTimeout)) {
+			others = append(others, session)
+		} else {
+			matches = append(matches, session)
+		}
+	}
+
+	if len(matches) > 0 {
+		// The heartbeat is close to two sessions if it bridges the pause
+		// between them, which makes them one, keeping the earlier one's ID
+		sort.Slice(matches, func(i, j int) bool {
+			return matches[i].Start.Before(matches[j].Start)
+		})
+		session, merged := matches[0], matches[1:]
+		for _, other := range merged {
+			session.End = latest(session.End, other.End)
+			session.Heartbeats += other.Heartbeats
+		}
+		if when.Before(session.Start) {
+			session.Start = when
+		}
+		session.End = latest(session.End, when)
+		session.Heartbeats++
+		a.sessions[key] = append(others, session)
+		return session, merged
+	}
+
+	session := &Session{
+		ID:         fmt.Sprintf("%s|%d", key, when.Unix()),
+		Project:    project,
+		Language:   language,
+		Start:      when,
+		End:        when,
+		Heartbeats: 1,
+	}
+	a.sessions[key] = append(a.sessions[key], session)
+	return session, nil
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// prune forgets sessions that ended long before the latest heartbeat
+func (a *Aggregator) prune() {
+	cutoff := a.latest.Add(-sessionRetention)
+	for key, sessions := range a.sessions {
+		kept := sessions[:0]
+		for _, session := range sessions {
+			if session.End.After(cutoff) {
+				kept = append(kept, session)
+			}
+		}
+		if len(kept) == 0 {
+			delete(a.sessions, key)
+		} else {
+			a.sessions[key] = kept
+		}
+	}
+}
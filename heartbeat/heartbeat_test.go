@@ -0,0 +1,123 @@
+package heartbeat
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// base is the time of the first heartbeat in tests
+var base = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+// beat returns a heartbeat some minutes after base
+func beat(project, language string, minutes float64) Heartbeat {
+	return Heartbeat{
+		Entity:   "main.go",
+		Time:     float64(base.Unix()) + minutes*60,
+		Project:  project,
+		Language: language,
+	}
+}
+
+// span describes a session as minutes after base
+type span struct {
+	id         string
+	start, end float64
+	heartbeats int
+	mergedInto string
+}
+
+// checkSessions compares sessions with the expected spans
+func checkSessions(t *testing.T, got []Session, want []span) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d sessions %+v, want %d", len(got), got, len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		start := base.Add(time.Duration(w.start * float64(time.Minute)))
+		end := base.Add(time.Duration(w.end * float64(time.Minute)))
+		if g.ID != w.id || !g.Start.Equal(start) || !g.End.Equal(end) || g.Heartbeats != w.heartbeats || g.MergedInto != w.mergedInto {
+			t.Errorf("session %d = %s %s-%s (%d heartbeats, merged into %q), want %s %s-%s (%d, %q)",
+				i, g.ID, g.Start.Format("15:04"), g.End.Format("15:04"), g.Heartbeats, g.MergedInto,
+				w.id, start.Format("15:04"), end.Format("15:04"), w.heartbeats, w.mergedInto)
+		}
+	}
+}
+
+// id returns the ID of a session starting some minutes after base
+func id(key string, minutes int) string {
+	return fmt.Sprintf("%s|%d", key, base.Unix()+int64(minutes)*60)
+}
+
+func TestAdd(t *testing.T) {
+	a := NewAggregator(15 * time.Minute)
+
+	// Heartbeats in any order make one session while the pauses are short
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 10), beat("lazytrack", "Go", 0), beat("lazytrack", "Go", 5)}),
+		[]span{{id("lazytrack|go", 0), 0, 10, 3, ""}})
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 25)}), []span{{id("lazytrack|go", 0), 0, 25, 4, ""}})
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 12)}), []span{{id("lazytrack|go", 0), 0, 25, 5, ""}})
+
+	// A longer pause starts a new session, as does another project or language
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 41), beat("blog", "Markdown", 42), beat("lazytrack", "Markdown", 43)}),
+		[]span{{id("lazytrack|go", 41), 41, 41, 1, ""}, {id("blog|markdown", 42), 42, 42, 1, ""}, {id("lazytrack|markdown", 43), 43, 43, 1, ""}})
+
+	// A late heartbeat before a session's start extends it back
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", -5)}), []span{{id("lazytrack|go", 0), -5, 25, 6, ""}})
+}
+
+func TestAddMergesBridgedSessions(t *testing.T) {
+	a := NewAggregator(15 * time.Minute)
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 0), beat("lazytrack", "Go", 10)})
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 35), beat("lazytrack", "Go", 40)})
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 70)})
+
+	// A heartbeat from an offline queue fills the pause between the first two
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 22)}), []span{
+		{id("lazytrack|go", 35), 35, 40, 2, id("lazytrack|go", 0)},
+		{id("lazytrack|go", 0), 0, 40, 5, ""},
+	})
+
+	// Chains of merges point to the session that's left
+	a = NewAggregator(15 * time.Minute)
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 0)})
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 30)})
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 60)})
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 45), beat("lazytrack", "Go", 15)}), []span{
+		{id("lazytrack|go", 30), 30, 30, 1, id("lazytrack|go", 0)},
+		{id("lazytrack|go", 0), 0, 60, 5, ""},
+		{id("lazytrack|go", 60), 60, 60, 1, id("lazytrack|go", 0)},
+	})
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 61)}), []span{{id("lazytrack|go", 0), 0, 61, 6, ""}})
+
+	// A heartbeat close to only one session doesn't merge anything
+	a = NewAggregator(15 * time.Minute)
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 0)})
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 40)})
+	checkSessions(t, a.Add([]Heartbeat{beat("lazytrack", "Go", 14)}), []span{{id("lazytrack|go", 0), 0, 14, 2, ""}})
+}
+
+func TestAddPrunes(t *testing.T) {
+	a := NewAggregator(15 * time.Minute)
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 0)})
+	a.Add([]Heartbeat{beat("lazytrack", "Go", 25*60)})
+	if sessions := a.sessions["lazytrack|go"]; len(sessions) != 1 {
+		t.Errorf("kept %d sessions, want the old one pruned", len(sessions))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Heartbeat{Entity: "main.go", Time: 1}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if err := (Heartbeat{Entity: "main.go"}).Validate(); err == nil {
+		t.Errorf("a heartbeat without time is valid")
+	}
+	if err := (Heartbeat{Entity: " ", Time: 1}).Validate(); err == nil {
+		t.Errorf("a heartbeat without entity is valid")
+	}
+	if got := (Heartbeat{Time: 1700000000.25}).Timestamp(); !got.Equal(time.Unix(1700000000, 250000000)) {
+		t.Errorf("Timestamp = %v", got)
+	}
+}
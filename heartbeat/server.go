@@ -0,0 +1,191 @@
+package heartbeat
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodySize limits the size of a heartbeat request
+const maxBodySize = 10 << 20
+
+// Sink saves the sessions changed by a request, dropping those merged into
+// another session. Calls never overlap, and come in the order the sessions
+// changed.
+type Sink func(sessions []Session) error
+
+// TodayFunc returns the time logged today, for the editor status bar. It
+// isn't called while sessions are being saved.
+type TodayFunc func() (time.Duration, error)
+
+// Server accepts heartbeats on the WakaTime API paths
+type Server struct {
+	Aggregator *Aggregator
+	Sink       Sink
+	Today      TodayFunc // optional
+	APIKey     string    // required from clients when set
+
+	// mu orders adding heartbeats with saving the sessions they changed, so
+	// an older snapshot of a session is never saved after a newer one
+	mu sync.Mutex
+}
+
+// ServeHTTP implements http.Handler. Paths are accepted with and without the
+// /api/v1 prefix, since plugins are configured with api_url=http://host/api/v1.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+
+	if s.APIKey != "" && !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid api key"})
+		return
+	}
+
+	switch path {
+	case "/users/current/heartbeats":
+		s.handleHeartbeats(w, r, false)
+	case "/users/current/heartbeats.bulk":
+		s.handleHeartbeats(w, r, true)
+	case "/users/current/statusbar/today":
+		s.handleToday(w, r)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleHeartbeats accepts one heartbeat or a list of heartbeats
+func (s *Server) handleHeartbeats(w http.ResponseWriter, r *http.Request, bulk bool) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	heartbeats, err := decodeHeartbeats(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var accepted []Heartbeat
+	responses := make([][]any, 0, len(heartbeats))
+	for _, heartbeat := range heartbeats {
+		if err := heartbeat.Validate(); err != nil {
+			responses = append(responses, []any{map[string]string{"error": err.Error()}, http.StatusBadRequest})
+			continue
+		}
+		accepted = append(accepted, heartbeat)
+		responses = append(responses, []any{map[string]any{"data": heartbeat}, http.StatusCreated})
+	}
+
+	if len(accepted) > 0 {
+		if err := s.save(accepted); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to save sessions: %v", err)})
+			return
+		}
+	}
+
+	if bulk {
+		writeJSON(w, http.StatusCreated, map[string]any{"responses": responses})
+		return
+	}
+	if len(accepted) == 0 {
+		writeJSON(w, http.StatusBadRequest, responses[0][0])
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"data": accepted[0]})
+}
+
+// save adds heartbeats and saves the sessions they changed
+func (s *Server) save(heartbeats []Heartbeat) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Sink(s.Aggregator.Add(heartbeats))
+}
+
+// handleToday reports today's coding time in the format editor status bars expect
+func (s *Server) handleToday(w http.ResponseWriter, r *http.Request) {
+	var total time.Duration
+	if s.Today != nil {
+		s.mu.Lock()
+		var err error
+		total, err = s.Today()
+		s.mu.Unlock()
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
+	hours := int(total.Hours())
+	minutes := int(total.Minutes()) % 60
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"grand_total": map[string]any{
+				"total_seconds": total.Seconds(),
+				"hours":         hours,
+				"minutes":       minutes,
+				"text":          fmt.Sprintf("%d hrs %d mins", hours, minutes),
+				"digital":       fmt.Sprintf("%d:%02d", hours, minutes),
+			},
+		},
+	})
+}
+
+// authorized checks the API key sent as basic auth (as wakatime-cli does) or a bearer token
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	var key string
+	switch {
+	case strings.HasPrefix(header, "Basic "):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
+		if err != nil {
+			return false
+		}
+		// The key is sent as the user name, possibly followed by a colon
+		key, _, _ = strings.Cut(string(decoded), ":")
+	case strings.HasPrefix(header, "Bearer "):
+		key = strings.TrimPrefix(header, "Bearer ")
+	default:
+		key = r.URL.Query().Get("api_key")
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(s.APIKey)) == 1
+}
+
+// decodeHeartbeats decodes a heartbeat object or an array of heartbeats
+func decodeHeartbeats(r io.Reader) ([]Heartbeat, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var heartbeats []Heartbeat
+		if err := json.Unmarshal(trimmed, &heartbeats); err != nil {
+			return nil, fmt.Errorf("invalid heartbeats: %w", err)
+		}
+		if len(heartbeats) == 0 {
+			return nil, fmt.Errorf("no heartbeats")
+		}
+		return heartbeats, nil
+	}
+
+	var heartbeat Heartbeat
+	if err := json.Unmarshal(trimmed, &heartbeat); err != nil {
+		return nil, fmt.Errorf("invalid heartbeat: %w", err)
+	}
+	return []Heartbeat{heartbeat}, nil
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
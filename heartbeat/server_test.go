@@ -0,0 +1,244 @@
+package heartbeat
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer is a server whose sink records sessions
+type testServer struct {
+	*Server
+	saved   [][]Session
+	sinkErr error
+}
+
+func newTestServer(apiKey string) *testServer {
+	s := &testServer{}
+	s.Server = &Server{
+		Aggregator: NewAggregator(15 * time.Minute),
+		APIKey:     apiKey,
+		Sink: func(sessions []Session) error {
+			s.saved = append(s.saved, sessions)
+			return s.sinkErr
+		},
+		Today: func() (time.Duration, error) {
+			return 2*time.Hour + 5*time.Minute + 30*time.Second, nil
+		},
+	}
+	return s
+}
+
+// do sends a request to a server and decodes the JSON response
+func do(t *testing.T, handler http.Handler, method, target, body string, header map[string]string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("%s %s: Content-Type = %q", method, target, got)
+	}
+	var response map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s %s: invalid response %q: %v", method, target, rec.Body, err)
+	}
+	return rec.Code, response
+}
+
+const heartbeatJSON = `{"entity":"/src/main.go","type":"file","time":1792314000.5,"project":"lazytrack","language":"Go","is_write":true}`
+
+func TestServerHeartbeat(t *testing.T) {
+	for _, path := range []string{"/api/v1/users/current/heartbeats", "/users/current/heartbeats"} {
+		s := newTestServer("")
+		status, response := do(t, s, http.MethodPost, path, heartbeatJSON, nil)
+		if status != http.StatusCreated {
+			t.Fatalf("POST %s = %d %v, want 201", path, status, response)
+		}
+		data, _ := response["data"].(map[string]any)
+		if data["entity"] != "/src/main.go" || data["project"] != "lazytrack" {
+			t.Errorf("POST %s: data = %v", path, data)
+		}
+		if len(s.saved) != 1 || len(s.saved[0]) != 1 || s.saved[0][0].Project != "lazytrack" || s.saved[0][0].Language != "Go" {
+			t.Errorf("POST %s: saved %+v, want one lazytrack session", path, s.saved)
+		}
+	}
+}
+
+func TestServerBulk(t *testing.T) {
+	s := newTestServer("")
+	body := `[` + heartbeatJSON + `, {"entity":"", "time": 1792314060}, {"entity":"/src/store.go","time":1792314120,"project":"lazytrack","language":"Go"}]`
+	status, response := do(t, s, http.MethodPost, "/api/v1/users/current/heartbeats.bulk", body, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", status)
+	}
+
+	// Each heartbeat gets a response of its own, in order
+	responses, _ := response["responses"].([]any)
+	if len(responses) != 3 {
+		t.Fatalf("responses = %v, want 3", response)
+	}
+	for i, wantStatus := range []float64{201, 400, 201} {
+		pair, _ := responses[i].([]any)
+		if len(pair) != 2 || pair[1] != wantStatus {
+			t.Errorf("response %d = %v, want status %v", i, responses[i], wantStatus)
+		}
+	}
+	if len(s.saved) != 1 || len(s.saved[0]) != 1 || s.saved[0][0].Heartbeats != 2 {
+		t.Errorf("saved %+v, want one session of 2 heartbeats", s.saved)
+	}
+}
+
+func TestServerConcurrentHeartbeats(t *testing.T) {
+	// The sink keeps the latest snapshot of each session, and takes longer
+	// for smaller ones, so an older snapshot saved late would win
+	saved := make(map[string]Session)
+	server := &Server{
+		Aggregator: NewAggregator(15 * time.Minute),
+		Sink: func(sessions []Session) error {
+			for _, session := range sessions {
+				time.Sleep(time.Duration(20-session.Heartbeats) * time.Millisecond)
+				saved[session.ID] = session
+			}
+			return nil
+		},
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := `{"entity":"/src/main.go","time":` + strconv.Itoa(1792314000+60*i) + `,"project":"lazytrack","language":"Go"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users/current/heartbeats", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+			if rec.Code != http.StatusCreated {
+				t.Errorf("heartbeat %d: status %d", i, rec.Code)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(saved) != 1 {
+		t.Fatalf("saved %d sessions, want 1", len(saved))
+	}
+	for _, session := range saved {
+		if session.Heartbeats != n || session.Duration() != (n-1)*time.Minute {
+			t.Errorf("saved %d heartbeats over %s, want %d over %s", session.Heartbeats, session.Duration(), n, (n-1)*time.Minute)
+		}
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid heartbeat", http.MethodPost, "/api/v1/users/current/heartbeats", `{"entity":"main.go"}`, http.StatusBadRequest},
+		{"invalid JSON", http.MethodPost, "/api/v1/users/current/heartbeats", `{"entity":`, http.StatusBadRequest},
+		{"empty bulk", http.MethodPost, "/api/v1/users/current/heartbeats.bulk", `[]`, http.StatusBadRequest},
+		{"GET heartbeats", http.MethodGet, "/api/v1/users/current/heartbeats", "", http.StatusMethodNotAllowed},
+		{"unknown path", http.MethodGet, "/api/v1/users/current/projects", "", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		s := newTestServer("")
+		status, response := do(t, s, test.method, test.path, test.body, nil)
+		if status != test.status || response["error"] == nil {
+			t.Errorf("%s: %d %v, want %d with an error", test.name, status, response, test.status)
+		}
+		if len(s.saved) != 0 {
+			t.Errorf("%s: saved %+v", test.name, s.saved)
+		}
+	}
+
+	// A body over the limit is rejected
+	s := newTestServer("")
+	huge := `{"entity":"` + strings.Repeat("x", maxBodySize) + `","time":1}`
+	if status, _ := do(t, s, http.MethodPost, "/api/v1/users/current/heartbeats", huge, nil); status != http.StatusBadRequest {
+		t.Errorf("huge body: status %d, want 400", status)
+	}
+
+	// Failing to save is a server error
+	s = newTestServer("")
+	s.sinkErr = errors.New("disk full")
+	status, response := do(t, s, http.MethodPost, "/api/v1/users/current/heartbeats", heartbeatJSON, nil)
+	if status != http.StatusInternalServerError || !strings.Contains(response["error"].(string), "disk full") {
+		t.Errorf("sink error: %d %v, want 500 with the error", status, response)
+	}
+}
+
+func TestServerAPIKey(t *testing.T) {
+	const key = "2f1c6a8e-7d1e-4c0a-9b1e-3b7f6c2d9a10"
+	basic := func(credentials string) map[string]string {
+		return map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))}
+	}
+	tests := []struct {
+		name   string
+		query  string
+		header map[string]string
+		ok     bool
+	}{
+		{"basic auth", "", basic(key), true},
+		{"basic auth with colon", "", basic(key + ":"), true},
+		{"bearer token", "", map[string]string{"Authorization": "Bearer " + key}, true},
+		{"query", "?api_key=" + key, nil, true},
+		{"missing", "", nil, false},
+		{"wrong basic auth", "", basic("wrong"), false},
+		{"wrong bearer token", "", map[string]string{"Authorization": "Bearer " + key + "x"}, false},
+		{"invalid base64", "", map[string]string{"Authorization": "Basic %%%"}, false},
+		{"key prefix", "?api_key=" + key[:8], nil, false},
+	}
+	for _, test := range tests {
+		s := newTestServer(key)
+		status, _ := do(t, s, http.MethodPost, "/api/v1/users/current/heartbeats"+test.query, heartbeatJSON, test.header)
+		if test.ok && status != http.StatusCreated {
+			t.Errorf("%s: status %d, want 201", test.name, status)
+		}
+		if !test.ok && (status != http.StatusUnauthorized || len(s.saved) != 0) {
+			t.Errorf("%s: status %d and %d saves, want 401 and none", test.name, status, len(s.saved))
+		}
+	}
+}
+
+func TestServerToday(t *testing.T) {
+	s := newTestServer("")
+	status, response := do(t, s, http.MethodGet, "/api/v1/users/current/statusbar/today", "", nil)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	data, _ := response["data"].(map[string]any)
+	total, _ := data["grand_total"].(map[string]any)
+	want := map[string]any{"total_seconds": 7530.0, "hours": 2.0, "minutes": 5.0, "text": "2 hrs 5 mins", "digital": "2:05"}
+	for key, value := range want {
+		if total[key] != value {
+			t.Errorf("grand_total.%s = %v, want %v", key, total[key], value)
+		}
+	}
+
+	s.Today = func() (time.Duration, error) { return 0, errors.New("no store") }
+	if status, _ := do(t, s, http.MethodGet, "/api/v1/users/current/statusbar/today", "", nil); status != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", status)
+	}
+
+	// Without a Today function, nothing was logged
+	s.Today = nil
+	_, response = do(t, s, http.MethodGet, "/users/current/statusbar/today", "", nil)
+	data, _ = response["data"].(map[string]any)
+	if total, _ := data["grand_total"].(map[string]any); total["digital"] != "0:00" {
+		t.Errorf("grand_total = %v, want 0:00", total)
+	}
+}
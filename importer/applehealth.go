@@ -0,0 +1,200 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+)
+
+// appleTimeFormat is the date format used in Apple Health exports
+const appleTimeFormat = "2006-01-02 15:04:05 -0700"
+
+// ReadAppleHealth reads an Apple Health export.xml, or the export.zip holding
+// it, and converts steps, sleep and workouts into daily steps, nightly sleep
+// and workout logs. The XML is streamed, so exports of several GB work fine.
+func ReadAppleHealth(exportPath string) (Backup, error) {
+	var r io.Reader
+	if strings.EqualFold(filepath.Ext(exportPath), ".zip") {
+		archive, err := zip.OpenReader(exportPath)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to open Apple Health export: %w", err)
+		}
+		defer archive.Close()
+
+		for _, file := range archive.File {
+			if path.Base(file.Name) == "export.xml" {
+				entry, err := file.Open()
+				if err != nil {
+					return Backup{}, fmt.Errorf("failed to open export.xml: %w", err)
+				}
+				defer entry.Close()
+				r = entry
+				break
+			}
+		}
+		if r == nil {
+			return Backup{}, fmt.Errorf("no export.xml found in %s", exportPath)
+		}
+	} else {
+		file, err := os.Open(exportPath)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to open Apple Health export: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	return parseAppleHealth(r)
+}
+
+// parseAppleHealth streams the records and workouts of an export.xml
+func parseAppleHealth(r io.Reader) (Backup, error) {
+	steps := make(dailySteps)
+	asleep := make(sleepNights)
+	inBed := make(sleepNights)
+	var workouts []exchange.Record
+	var workout *exchange.Record
+	var distance float64
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Backup{}, fmt.Errorf("invalid Apple Health export: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			attrs := make(map[string]string, len(element.Attr))
+			for _, attr := range element.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+
+			switch element.Name.Local {
+			case "Record":
+				if err := addAppleRecord(attrs, steps, asleep, inBed); err != nil {
+					line, _ := decoder.InputPos()
+					return Backup{}, fmt.Errorf("line %d: %w", line, err)
+				}
+			case "Workout":
+				record, err := appleWorkout(attrs)
+				if err != nil {
+					line, _ := decoder.InputPos()
+					return Backup{}, fmt.Errorf("line %d: %w", line, err)
+				}
+				workout = &record
+				distance = appleDistance(attrs["totalDistance"], attrs["totalDistanceUnit"])
+			case "WorkoutStatistics":
+				// Newer exports list the distance as workout statistics
+				if workout != nil && strings.Contains(attrs["type"], "Distance") && distance == 0 {
+					distance = appleDistance(attrs["sum"], attrs["unit"])
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Local == "Workout" && workout != nil && workout.Duration != "0m" {
+				workout.Distance = RoundDistance(distance)
+				workouts = append(workouts, *workout)
+			}
+			if element.Name.Local == "Workout" {
+				workout = nil
+			}
+		}
+	}
+
+	// Nights without detailed sleep stages fall back to time in bed
+	for day, intervals := range inBed {
+		if _, exists := asleep[day]; !exists {
+			asleep[day] = intervals
+		}
+	}
+
+	var backup Backup
+	backup.Records = append(backup.Records, steps.records("applehealth")...)
+	backup.Records = append(backup.Records, asleep.records("applehealth")...)
+	backup.Records = append(backup.Records, workouts...)
+	backup.Habits = healthHabits(backup.Records)
+	return backup, nil
+}
+
+// addAppleRecord adds a step count or sleep analysis record
+func addAppleRecord(attrs map[string]string, steps dailySteps, asleep, inBed sleepNights) error {
+	switch attrs["type"] {
+	case "HKQuantityTypeIdentifierStepCount":
+		start, err := time.Parse(appleTimeFormat, attrs["startDate"])
+		if err != nil {
+			return fmt.Errorf("invalid startDate: %s", attrs["startDate"])
+		}
+		value, err := strconv.ParseFloat(attrs["value"], 64)
+		if err != nil {
+			return fmt.Errorf("invalid step count: %s", attrs["value"])
+		}
+		steps.add(attrs["sourceName"], start, int(value))
+	case "HKCategoryTypeIdentifierSleepAnalysis":
+		start, err := time.Parse(appleTimeFormat, attrs["startDate"])
+		if err != nil {
+			return fmt.Errorf("invalid startDate: %s", attrs["startDate"])
+		}
+		end, err := time.Parse(appleTimeFormat, attrs["endDate"])
+		if err != nil {
+			return fmt.Errorf("invalid endDate: %s", attrs["endDate"])
+		}
+		switch {
+		case strings.HasPrefix(attrs["value"], "HKCategoryValueSleepAnalysisAsleep"):
+			asleep.add(start, end)
+		case attrs["value"] == "HKCategoryValueSleepAnalysisInBed":
+			inBed.add(start, end)
+		}
+	}
+	return nil
+}
+
+// appleWorkout converts a workout to a record
+func appleWorkout(attrs map[string]string) (exchange.Record, error) {
+	start, err := time.Parse(appleTimeFormat, attrs["startDate"])
+	if err != nil {
+		return exchange.Record{}, fmt.Errorf("invalid startDate: %s", attrs["startDate"])
+	}
+	end, err := time.Parse(appleTimeFormat, attrs["endDate"])
+	if err != nil {
+		return exchange.Record{}, fmt.Errorf("invalid endDate: %s", attrs["endDate"])
+	}
+
+	activity := strings.TrimPrefix(attrs["workoutActivityType"], "HKWorkoutActivityType")
+	habit := WorkoutHabit(activity)
+	return exchange.Record{
+		Habit:     habit,
+		StartedAt: start,
+		LoggedAt:  end,
+		Duration:  FormatMinutes(end.Sub(start)),
+		Notes:     activity,
+		Source:    fmt.Sprintf("applehealth:workout:%d", start.Unix()),
+	}, nil
+}
+
+// appleDistance converts a distance to kilometers
+func appleDistance(value, unit string) float64 {
+	distance, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	switch unit {
+	case "mi":
+		return distance * 1.609344
+	case "m":
+		return distance / 1000
+	default:
+		return distance
+	}
+}
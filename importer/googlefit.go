@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+)
+
+// googleFitSession is a file of Takeout/Fit/All Sessions
+type googleFitSession struct {
+	FitnessActivity string    `json:"fitnessActivity"`
+	StartTime       time.Time `json:"startTime"`
+	EndTime         time.Time `json:"endTime"`
+	Aggregate       []struct {
+		MetricName string  `json:"metricName"`
+		FloatValue float64 `json:"floatValue"`
+	} `json:"aggregate"`
+}
+
+// googleFitDataPoints is a file of Takeout/Fit/All Data
+type googleFitDataPoints struct {
+	DataPoints []struct {
+		DataTypeName   string      `json:"dataTypeName"`
+		StartTimeNanos json.Number `json:"startTimeNanos"` // a number or a string, depending on the export
+		FitValue       []struct {
+			Value struct {
+				IntVal *int `json:"intVal"`
+			} `json:"value"`
+		} `json:"fitValue"`
+	} `json:"Data Points"`
+}
+
+// ReadGoogleFit reads a Google Fit Takeout folder (or a single file from it)
+// and converts steps, sleep and workout sessions into daily steps, nightly
+// sleep and workout logs
+func ReadGoogleFit(exportPath string) (Backup, error) {
+	info, err := os.Stat(exportPath)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to open Google Fit export: %w", err)
+	}
+
+	var files []string
+	if info.IsDir() {
+		err = filepath.WalkDir(exportPath, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := strings.ToLower(filepath.Ext(path)); !entry.IsDir() && (ext == ".json" || ext == ".csv") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return Backup{}, err
+		}
+	} else {
+		files = []string{exportPath}
+	}
+
+	steps := make(dailySteps)
+	sleep := make(sleepNights)
+	var workouts []exchange.Record
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		name := filepath.Base(file)
+
+		switch {
+		case strings.EqualFold(filepath.Ext(file), ".csv"):
+			// Only the daily summary holds one row per day, per-day files hold 15 minute buckets
+			if name == "Daily activity metrics.csv" || name == "Daily Summaries.csv" {
+				if err := addGoogleFitDailyMetrics(string(data), steps); err != nil {
+					return Backup{}, fmt.Errorf("%s: %w", name, err)
+				}
+			}
+		case strings.Contains(string(data), `"fitnessActivity"`):
+			var session googleFitSession
+			if err := json.Unmarshal(data, &session); err != nil {
+				return Backup{}, fmt.Errorf("%s: %w", name, err)
+			}
+			if strings.HasPrefix(session.FitnessActivity, "sleep") {
+				sleep.add(session.StartTime, session.EndTime)
+			} else if record, ok := googleFitWorkout(session); ok {
+				workouts = append(workouts, record)
+			}
+		case strings.Contains(string(data), `"Data Points"`):
+			var points googleFitDataPoints
+			if err := json.Unmarshal(data, &points); err != nil {
+				return Backup{}, fmt.Errorf("%s: %w", name, err)
+			}
+			for _, point := range points.DataPoints {
+				if point.DataTypeName != "com.google.step_count.delta" || len(point.FitValue) == 0 || point.FitValue[0].Value.IntVal == nil {
+					continue
+				}
+				nanos, err := point.StartTimeNanos.Int64()
+				if err != nil {
+					return Backup{}, fmt.Errorf("%s: invalid startTimeNanos: %s", name, point.StartTimeNanos)
+				}
+				steps.add(name, time.Unix(0, nanos), *point.FitValue[0].Value.IntVal)
+			}
+		}
+	}
+
+	var backup Backup
+	backup.Records = append(backup.Records, steps.records("googlefit")...)
+	backup.Records = append(backup.Records, sleep.records("googlefit")...)
+	backup.Records = append(backup.Records, workouts...)
+	backup.Habits = healthHabits(backup.Records)
+	if len(backup.Records) == 0 {
+		return Backup{}, fmt.Errorf("no steps, sleep or workouts found in %s", exportPath)
+	}
+	return backup, nil
+}
+
+// googleFitWorkout converts a session to a workout record
+func googleFitWorkout(session googleFitSession) (exchange.Record, bool) {
+	duration := FormatMinutes(session.EndTime.Sub(session.StartTime))
+	if session.StartTime.IsZero() || duration == "0m" {
+		return exchange.Record{}, false
+	}
+
+	var kilometers float64
+	for _, metric := range session.Aggregate {
+		if metric.MetricName == "com.google.distance.delta" {
+			kilometers = metric.FloatValue / 1000
+		}
+	}
+
+	return exchange.Record{
+		Habit:     WorkoutHabit(session.FitnessActivity),
+		StartedAt: session.StartTime,
+		LoggedAt:  session.EndTime,
+		Duration:  duration,
+		Distance:  RoundDistance(kilometers),
+		Notes:     session.FitnessActivity,
+		Source:    fmt.Sprintf("googlefit:workout:%d", session.StartTime.Unix()),
+	}, true
+}
+
+// addGoogleFitDailyMetrics adds the "Step count" column of the daily summary
+func addGoogleFitDailyMetrics(data string, steps dailySteps) error {
+	rows, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	dateColumn, stepsColumn := -1, -1
+	for i, name := range rows[0] {
+		switch strings.TrimSpace(name) {
+		case "Date":
+			dateColumn = i
+		case "Step count":
+			stepsColumn = i
+		}
+	}
+	if dateColumn < 0 || stepsColumn < 0 {
+		return nil
+	}
+
+	for _, row := range rows[1:] {
+		if len(row) <= max(dateColumn, stepsColumn) || row[stepsColumn] == "" {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", row[dateColumn], time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date: %s", row[dateColumn])
+		}
+		count, err := strconv.ParseFloat(row[stepsColumn], 64)
+		if err != nil {
+			return fmt.Errorf("invalid step count: %s", row[stepsColumn])
+		}
+		steps.add("daily metrics", checkmarkTime(day), int(count))
+	}
+	return nil
+}
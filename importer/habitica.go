@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// habiticaTask is a habit or daily of a Habitica export
+type habiticaTask struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	Up        *bool           `json:"up"`
+	Frequency string          `json:"frequency"`
+	EveryX    int             `json:"everyX"`
+	Repeat    map[string]bool `json:"repeat"`
+	History   []struct {
+		Date      json.RawMessage `json:"date"`
+		Value     float64         `json:"value"`
+		Completed *bool           `json:"completed"`
+		ScoredUp  *int            `json:"scoredUp"`
+	} `json:"history"`
+}
+
+// ReadHabitica reads a Habitica user data export (userdata.json) or the task
+// list returned by its API. Habits and dailies are imported with their history;
+// to-dos, rewards and habits that can only be scored down are skipped.
+func ReadHabitica(exportPath string) (Backup, error) {
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to read Habitica export: %w", err)
+	}
+
+	tasks, err := parseHabiticaTasks(data)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	var backup Backup
+	for _, task := range tasks {
+		habit := types.Habit{Name: HabitName(task.Text), GoalType: "count"}
+		if habit.Name == "" || (task.Type != "habit" && task.Type != "daily") || (task.Type == "habit" && task.Up != nil && !*task.Up) {
+			backup.Skipped++
+			continue
+		}
+		if task.Type == "daily" {
+			habit.DailyGoal, habit.WeeklyGoal = task.goals()
+		}
+		backup.Habits = append(backup.Habits, habit)
+
+		previous := 0.0
+		for i, entry := range task.History {
+			loggedAt, err := parseHabiticaDate(entry.Date)
+			if err != nil {
+				return Backup{}, fmt.Errorf("%s: %w", task.Text, err)
+			}
+
+			// Older history entries only record the task's value, which goes up when it is scored
+			count := 0
+			switch {
+			case entry.Completed != nil:
+				if *entry.Completed {
+					count = 1
+				}
+			case entry.ScoredUp != nil:
+				count = *entry.ScoredUp
+			case i > 0 && entry.Value > previous:
+				count = 1
+			}
+			previous = entry.Value
+
+			if count > 0 {
+				backup.Records = append(backup.Records, exchange.Record{Habit: habit.Name, LoggedAt: loggedAt, Count: count})
+			}
+		}
+	}
+
+	return backup, nil
+}
+
+// goals converts a daily's repeat settings into a daily or weekly goal
+func (t habiticaTask) goals() (int, int) {
+	switch t.Frequency {
+	case "weekly":
+		days := 0
+		for _, enabled := range t.Repeat {
+			if enabled {
+				days++
+			}
+		}
+		if days == 7 {
+			return 1, 0
+		}
+		return frequencyGoals(days, 7)
+	case "daily", "":
+		return frequencyGoals(1, max(1, t.EveryX))
+	default:
+		return 0, 0 // monthly and yearly dailies have no goal lazytrack can express
+	}
+}
+
+// parseHabiticaTasks finds the tasks in a user data export, {"data": [...]} or a plain array
+func parseHabiticaTasks(data []byte) ([]habiticaTask, error) {
+	var tasks []habiticaTask
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, fmt.Errorf("invalid Habitica export: %w", err)
+		}
+		return tasks, nil
+	}
+
+	var export struct {
+		Tasks struct {
+			Habits []habiticaTask `json:"habits"`
+			Dailys []habiticaTask `json:"dailys"`
+		} `json:"tasks"`
+		Data []habiticaTask `json:"data"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid Habitica export: %w", err)
+	}
+
+	for _, task := range export.Tasks.Habits {
+		task.Type = "habit"
+		tasks = append(tasks, task)
+	}
+	for _, task := range export.Tasks.Dailys {
+		task.Type = "daily"
+		tasks = append(tasks, task)
+	}
+	tasks = append(tasks, export.Data...)
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no habits or dailies found in the Habitica export")
+	}
+	return tasks, nil
+}
+
+// parseHabiticaDate parses history dates, stored as milliseconds or as a timestamp string
+func parseHabiticaDate(raw json.RawMessage) (time.Time, error) {
+	value := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	if millis, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(millis)), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid history date: %s", value)
+}
@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// workoutHabits maps normalized workout activity names to habits
+var workoutHabits = map[string]string{
+	"running":                       "run",
+	"walking":                       "walk",
+	"cycling":                       "cycle",
+	"biking":                        "cycle",
+	"swimming":                      "swim",
+	"yoga":                          "yoga",
+	"hiking":                        "hike",
+	"traditionalstrengthtraining":   "gym",
+	"functionalstrengthtraining":    "gym",
+	"strengthtraining":              "gym",
+	"weightlifting":                 "gym",
+	"highintensityintervaltraining": "hiit",
+	"dance":                         "dance",
+	"dancing":                       "dance",
+}
+
+// WorkoutHabit returns the habit for a workout type like "HKWorkoutActivityTypeRunning" or "running"
+func WorkoutHabit(activity string) string {
+	name := strings.ToLower(strings.TrimPrefix(activity, "HKWorkoutActivityType"))
+	name = strings.NewReplacer("_", "", ".", "", " ", "").Replace(name)
+	if habit, exists := workoutHabits[name]; exists {
+		return habit
+	}
+	return "workout"
+}
+
+// dailySteps collects step counts per source and day. Phones and watches record
+// the same steps, so each day uses the source with the most steps instead of the sum.
+type dailySteps map[string]map[string]int
+
+// add adds steps counted by a source on a day
+func (d dailySteps) add(source string, when time.Time, steps int) {
+	if d[source] == nil {
+		d[source] = make(map[string]int)
+	}
+	d[source][when.Local().Format("2006-01-02")] += steps
+}
+
+// records returns one steps record per day
+func (d dailySteps) records(sourcePrefix string) []exchange.Record {
+	best := make(map[string]int)
+	for _, days := range d {
+		for day, steps := range days {
+			best[day] = max(best[day], steps)
+		}
+	}
+
+	var records []exchange.Record
+	for _, day := range sortedKeys(best) {
+		date, _ := time.ParseInLocation("2006-01-02", day, time.Local)
+		if best[day] > 0 {
+			records = append(records, exchange.Record{
+				Habit:    "steps",
+				LoggedAt: checkmarkTime(date),
+				Count:    best[day],
+				Source:   fmt.Sprintf("%s:steps:%s", sourcePrefix, day),
+			})
+		}
+	}
+	return records
+}
+
+// interval is a span of time
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// sleepNights collects sleep intervals by the day they end on
+type sleepNights map[string][]interval
+
+// add adds a sleep interval
+func (s sleepNights) add(start, end time.Time) {
+	if end.After(start) {
+		day := end.Local().Format("2006-01-02")
+		s[day] = append(s[day], interval{start, end})
+	}
+}
+
+// records returns one sleep record per night, merging overlapping intervals
+// recorded by several devices
+func (s sleepNights) records(sourcePrefix string) []exchange.Record {
+	var records []exchange.Record
+	for _, day := range sortedKeys(s) {
+		intervals := s[day]
+		sort.Slice(intervals, func(i, j int) bool {
+			return intervals[i].start.Before(intervals[j].start)
+		})
+
+		var total time.Duration
+		current := intervals[0]
+		for _, next := range intervals[1:] {
+			if next.start.After(current.end) {
+				total += current.end.Sub(current.start)
+				current = next
+			} else if next.end.After(current.end) {
+				current.end = next.end
+			}
+		}
+		total += current.end.Sub(current.start)
+
+		if duration := FormatMinutes(total); duration != "0m" {
+			records = append(records, exchange.Record{
+				Habit:     "sleep",
+				StartedAt: intervals[0].start,
+				LoggedAt:  current.end,
+				Duration:  duration,
+				Source:    fmt.Sprintf("%s:sleep:%s", sourcePrefix, day),
+			})
+		}
+	}
+	return records
+}
+
+// healthHabits returns the habits used by records, with the goal type their values need
+func healthHabits(records []exchange.Record) []types.Habit {
+	goalTypes := make(map[string]string)
+	for _, record := range records {
+		if _, seen := goalTypes[record.Habit]; !seen {
+			goalTypes[record.Habit] = "duration"
+			if record.Count > 0 {
+				goalTypes[record.Habit] = "count"
+			}
+		}
+	}
+
+	var habits []types.Habit
+	for _, name := range sortedKeys(goalTypes) {
+		habits = append(habits, types.Habit{Name: name, GoalType: goalTypes[name]})
+	}
+	return habits
+}
+
+// RoundDistance rounds a distance in kilometers to meters
+func RoundDistance(kilometers float64) float64 {
+	return math.Round(kilometers*1000) / 1000
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
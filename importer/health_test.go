@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// inUTC makes UTC the local time zone for a test, since health data is
+// grouped by local day
+func inUTC(t *testing.T) {
+	t.Helper()
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+}
+
+// checkRecords compares records, ignoring time zones
+func checkRecords(t *testing.T, got, want []exchange.Record) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d:\n%+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if !g.StartedAt.Equal(w.StartedAt) || !g.LoggedAt.Equal(w.LoggedAt) {
+			t.Errorf("record %d (%s): got %v-%v, want %v-%v", i, w.Source, g.StartedAt, g.LoggedAt, w.StartedAt, w.LoggedAt)
+		}
+		g.StartedAt, g.LoggedAt, w.StartedAt, w.LoggedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+		if g != w {
+			t.Errorf("record %d: got %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestReadAppleHealth(t *testing.T) {
+	inUTC(t)
+	backup, err := ReadAppleHealth(filepath.Join("testdata", "applehealth", "export.xml"))
+	if err != nil {
+		t.Fatalf("ReadAppleHealth: %v", err)
+	}
+
+	checkRecords(t, backup.Records, []exchange.Record{
+		// The watch counted the most steps on the 1st, so its count is taken instead of the sum
+		{Habit: "steps", LoggedAt: utc(1, 12, 0), Count: 6000, Source: "applehealth:steps:2026-10-01"},
+		{Habit: "steps", LoggedAt: utc(2, 12, 0), Count: 4000, Source: "applehealth:steps:2026-10-02"},
+		// Overlapping sleep of both devices is merged, and time in bed ignored
+		{Habit: "sleep", StartedAt: utc(1, 23, 0), LoggedAt: utc(2, 7, 0), Duration: "8h", Source: "applehealth:sleep:2026-10-02"},
+		// A night with time in bed only
+		{Habit: "sleep", StartedAt: utc(2, 23, 30), LoggedAt: utc(3, 6, 30), Duration: "7h", Source: "applehealth:sleep:2026-10-03"},
+		{Habit: "run", StartedAt: utc(1, 7, 0), LoggedAt: utc(1, 7, 30), Duration: "30m", Notes: "Running", Distance: 4.989, Source: "applehealth:workout:1790838000"},
+		{Habit: "cycle", StartedAt: utc(2, 17, 0), LoggedAt: utc(2, 17, 45), Duration: "45m", Notes: "Cycling", Distance: 12, Source: "applehealth:workout:1790960400"},
+		{Habit: "yoga", StartedAt: utc(3, 8, 0), LoggedAt: utc(3, 8, 20), Duration: "20m", Notes: "Yoga", Source: "applehealth:workout:1791014400"},
+	})
+
+	wantHabits := []types.Habit{
+		{Name: "cycle", GoalType: "duration"},
+		{Name: "run", GoalType: "duration"},
+		{Name: "sleep", GoalType: "duration"},
+		{Name: "steps", GoalType: "count"},
+		{Name: "yoga", GoalType: "duration"},
+	}
+	if !reflect.DeepEqual(backup.Habits, wantHabits) {
+		t.Errorf("got habits %+v, want %+v", backup.Habits, wantHabits)
+	}
+}
+
+func TestReadAppleHealthZip(t *testing.T) {
+	inUTC(t)
+	data, err := os.ReadFile(filepath.Join("testdata", "applehealth", "export.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "export.zip")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	entry, _ := archive.Create("apple_health_export/export.xml")
+	entry.Write(data)
+	archive.Close()
+	file.Close()
+
+	backup, err := ReadAppleHealth(path)
+	if err != nil {
+		t.Fatalf("ReadAppleHealth: %v", err)
+	}
+	if len(backup.Records) != 7 {
+		t.Errorf("got %d records from the zip, want 7", len(backup.Records))
+	}
+}
+
+func TestReadAppleHealthErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+		want string
+	}{
+		{"invalid XML", `<HealthData><Record`, "invalid Apple Health export"},
+		{"invalid date", "<HealthData>\n" + `<Record type="HKQuantityTypeIdentifierStepCount" startDate="yesterday" value="10"/></HealthData>`, "line 2: invalid startDate: yesterday"},
+		{"invalid steps", `<HealthData><Record type="HKQuantityTypeIdentifierStepCount" startDate="2026-10-01 09:00:00 +0000" value="many"/></HealthData>`, "invalid step count: many"},
+		{"invalid workout", `<HealthData><Workout workoutActivityType="HKWorkoutActivityTypeRunning" startDate="2026-10-01 09:00:00 +0000" endDate=""/></HealthData>`, "invalid endDate"},
+	}
+	for _, test := range tests {
+		_, err := parseAppleHealth(strings.NewReader(test.xml))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want %q", test.name, err, test.want)
+		}
+	}
+}
+
+func TestAppleDistance(t *testing.T) {
+	tests := []struct {
+		value, unit string
+		want        float64
+	}{
+		{"3.1", "mi", 4.989},
+		{"12000", "m", 12},
+		{"4.5", "km", 4.5},
+		{"", "km", 0},
+	}
+	for _, test := range tests {
+		if got := RoundDistance(appleDistance(test.value, test.unit)); got != test.want {
+			t.Errorf("appleDistance(%s %s) = %v, want %v", test.value, test.unit, got, test.want)
+		}
+	}
+}
+
+func TestReadGoogleFit(t *testing.T) {
+	inUTC(t)
+	backup, err := ReadGoogleFit(filepath.Join("testdata", "googlefit"))
+	if err != nil {
+		t.Fatalf("ReadGoogleFit: %v", err)
+	}
+
+	checkRecords(t, backup.Records, []exchange.Record{
+		// The data points hold more steps than the daily summary on the 1st;
+		// files of 15 minute buckets are ignored
+		{Habit: "steps", LoggedAt: utc(1, 12, 0), Count: 3500, Source: "googlefit:steps:2026-10-01"},
+		{Habit: "steps", LoggedAt: utc(2, 12, 0), Count: 4200, Source: "googlefit:steps:2026-10-02"},
+		{Habit: "sleep", StartedAt: utc(1, 23, 0), LoggedAt: utc(2, 7, 0), Duration: "8h", Source: "googlefit:sleep:2026-10-02"},
+		{Habit: "run", StartedAt: utc(1, 7, 0), LoggedAt: utc(1, 7, 30), Duration: "30m", Notes: "running", Distance: 5.012, Source: "googlefit:workout:1790838000"},
+		{Habit: "yoga", StartedAt: utc(2, 18, 0), LoggedAt: utc(2, 18, 25), Duration: "25m", Notes: "yoga", Source: "googlefit:workout:1790964000"},
+	})
+
+	// A single file of the export works too
+	backup, err = ReadGoogleFit(filepath.Join("testdata", "googlefit", "Takeout", "Fit", "All Sessions", "2026-10-02T18_00_00Z_YOGA.json"))
+	if err != nil || len(backup.Records) != 1 || backup.Records[0].Habit != "yoga" {
+		t.Errorf("got %+v, %v, want the yoga session", backup.Records, err)
+	}
+
+	if _, err := ReadGoogleFit(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no steps, sleep or workouts") {
+		t.Errorf("empty folder: got %v, want nothing found", err)
+	}
+}
+
+func TestWorkoutHabit(t *testing.T) {
+	tests := map[string]string{
+		"HKWorkoutActivityTypeTraditionalStrengthTraining": "gym",
+		"Running":                          "run",
+		"biking":                           "cycle",
+		"high_intensity_interval_training": "hiit",
+		"HKWorkoutActivityTypeCurling":     "workout",
+	}
+	for activity, want := range tests {
+		if got := WorkoutHabit(activity); got != want {
+			t.Errorf("WorkoutHabit(%s) = %s, want %s", activity, got, want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Entry is a time interval read from another tracker
+type Entry struct {
+	File        string // set if entries are read from several files
+	Line        int
+	Start       time.Time
+	End         time.Time
+	Project     string
+	Tags        []string
+	Description string
+}
+
+// Backup holds the habits and history read from another habit tracker
+type Backup struct {
+	Habits  []types.Habit
+	Records []exchange.Record
+	Skipped int // habits or entries that cannot be represented
+}
+
+// Mapper maps projects and tags of other trackers to habits
+type Mapper struct {
+	Mapping      map[string]string       // lowercase key to habit, "" or "-" to skip
+	DefaultHabit string                  // used when nothing matches
+	Prompt       func(key string) string // asks for unknown keys, may be nil
+	unmapped     map[string]bool
+}
+
+// LoadMapping loads a JSON mapping file like {"project x": "code", "reading": "read"}
+func LoadMapping(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid mapping file: %w", err)
+	}
+
+	mapping := make(map[string]string)
+	for key, habit := range raw {
+		mapping[normalizeKey(key)] = strings.ToLower(strings.TrimSpace(habit))
+	}
+	return mapping, nil
+}
+
+// Habit returns the habit for an entry, checking its project first and then its tags
+func (m *Mapper) Habit(entry Entry) (string, bool) {
+	if m.Mapping == nil {
+		m.Mapping = make(map[string]string)
+	}
+
+	var unknown []string
+	keys := append([]string{entry.Project}, entry.Tags...)
+	for _, key := range keys {
+		key = normalizeKey(key)
+		if key == "" {
+			continue
+		}
+
+		habit, known := m.Mapping[key]
+		if !known && m.Prompt != nil {
+			habit = strings.ToLower(strings.TrimSpace(m.Prompt(key)))
+			m.Mapping[key] = habit
+			known = true
+		}
+		if !known {
+			unknown = append(unknown, key)
+			continue
+		}
+		if habit != "" && habit != "-" {
+			return habit, true
+		}
+	}
+
+	if m.DefaultHabit != "" {
+		return m.DefaultHabit, true
+	}
+
+	if m.unmapped == nil {
+		m.unmapped = make(map[string]bool)
+	}
+	for _, key := range unknown {
+		m.unmapped[key] = true
+	}
+	return "", false
+}
+
+// Unmapped returns the unknown keys of entries that were skipped
+func (m *Mapper) Unmapped() []string {
+	var keys []string
+	for key := range m.unmapped {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ToRows converts entries to import rows, skipping entries without a habit
+// and entries shorter than a minute. The number of skipped entries is returned as well.
+func ToRows(entries []Entry, mapper *Mapper) ([]exchange.Row, int) {
+	var rows []exchange.Row
+	skipped := 0
+
+	for _, entry := range entries {
+		// Entries ending before they start are kept, for Validate to report them
+		if entry.End.Sub(entry.Start) < time.Minute && !entry.End.Before(entry.Start) {
+			skipped++
+			continue
+		}
+
+		habit, ok := mapper.Habit(entry)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		rows = append(rows, exchange.Row{
+			File: entry.File,
+			Line: entry.Line,
+			Record: exchange.Record{
+				Habit:     habit,
+				StartedAt: entry.Start,
+				LoggedAt:  entry.End,
+				Duration:  FormatMinutes(entry.End.Sub(entry.Start)),
+				Notes:     entry.Description,
+			},
+		})
+	}
+
+	return rows, skipped
+}
+
+// FormatMinutes formats a duration rounded to whole minutes, e.g. "1h30m"
+func FormatMinutes(duration time.Duration) string {
+	minutes := int(math.Round(duration.Minutes()))
+	return parser.FormatDuration(types.ParsedDuration{Hours: minutes / 60, Minutes: minutes % 60, IsValid: true})
+}
+
+// normalizeKey normalizes a project or tag for lookups
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+// HabitName turns a habit title like "Read 📚 books" into a habit name like "read-books"
+func HabitName(title string) string {
+	var name strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && name.Len() > 0 {
+				name.WriteByte('-')
+			}
+			name.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return name.String()
+}
+
+// frequencyGoals converts "reps times every interval days" into a daily or weekly goal
+func frequencyGoals(reps, interval int) (daily, weekly int) {
+	switch {
+	case reps <= 0:
+		return 0, 0
+	case interval <= 1:
+		return reps, 0
+	case interval == 7:
+		return 0, reps
+	default:
+		return 0, max(1, int(math.Round(float64(reps)*7/float64(interval))))
+	}
+}
+
+// checkmarkTime returns the time used for a checkmark on a day without a time
+func checkmarkTime(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+)
+
+func TestToRows(t *testing.T) {
+	start := utc(1, 9, 0)
+	entries := []Entry{
+		{Line: 1, Start: start, End: start.Add(59 * time.Second), Project: "app"},
+		{Line: 2, Start: start, End: start.Add(time.Minute), Project: "app"},
+		{Line: 3, Start: start, End: start.Add(90 * time.Minute), Tags: []string{"Reading"}, Description: "a book"},
+		{Line: 4, Start: start, End: start.Add(time.Hour), Project: "unknown"},
+		{Line: 5, Start: start, End: start.Add(time.Hour), Project: "meetings", Tags: []string{"reading"}},
+		{File: "2026-10.data", Line: 6, Start: start, End: start.Add(-time.Hour), Project: "app"},
+	}
+	mapper := &Mapper{Mapping: map[string]string{"app": "code", "reading": "read", "meetings": "-"}}
+
+	rows, skipped := ToRows(entries, mapper)
+	if skipped != 2 {
+		t.Errorf("skipped %d entries, want the one under a minute and the unknown one", skipped)
+	}
+	want := []struct {
+		line     int
+		habit    string
+		duration string
+	}{
+		{2, "code", "1m"},
+		{3, "read", "1h30m"},
+		{5, "read", "1h"},
+		{6, "code", "0m"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.Line != w.line || row.Record.Habit != w.habit || row.Record.Duration != w.duration {
+			t.Errorf("row %d: got line %d, %s for %s, want line %d, %s for %s",
+				i, row.Line, row.Record.Habit, row.Record.Duration, w.line, w.habit, w.duration)
+		}
+	}
+	if unmapped := mapper.Unmapped(); len(unmapped) != 1 || unmapped[0] != "unknown" {
+		t.Errorf("Unmapped = %q, want [unknown]", unmapped)
+	}
+
+	// Entries ending before they start are reported by file and line
+	_, err := exchange.Validate(rows)
+	var validationErr *exchange.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+		t.Fatalf("Validate: got %v, want one invalid row", err)
+	}
+	if lineErr := validationErr.Errors[0]; lineErr.File != "2026-10.data" || lineErr.Line != 6 {
+		t.Errorf("got %v, want it at 2026-10.data:6", lineErr)
+	}
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{time.Minute, "1m"},
+		{89 * time.Second, "1m"},
+		{90 * time.Second, "2m"},
+		{90 * time.Minute, "1h30m"},
+		{2 * time.Hour, "2h"},
+	}
+	for _, test := range tests {
+		if got := FormatMinutes(test.duration); got != test.want {
+			t.Errorf("FormatMinutes(%s) = %s, want %s", test.duration, got, test.want)
+		}
+	}
+}
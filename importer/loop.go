@@ -0,0 +1,367 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Loop Habit Tracker stores checkmarks as 0 (no), 1 (implied by the habit's
+// frequency), 2 (entered by the user) and 3 (skipped)
+const (
+	loopYesManual  = 2
+	loopNumberUnit = 1000 // numerical habits store values in thousandths
+)
+
+// loopHabit is a habit row of Habits.csv
+type loopHabit struct {
+	Position  string
+	Name      string
+	Reps      int
+	Interval  int
+	Numerical bool
+	Unit      string
+	Target    float64
+}
+
+// ReadLoop reads a Loop Habit Tracker CSV export, either the zip file or an
+// extracted directory. Only checkmarks entered by hand are imported; days
+// Loop fills in automatically from the habit's frequency are left out.
+func ReadLoop(exportPath string) (Backup, error) {
+	info, err := os.Stat(exportPath)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to open Loop export: %w", err)
+	}
+
+	var fsys fs.FS
+	switch {
+	case info.IsDir():
+		fsys = os.DirFS(exportPath)
+	case strings.EqualFold(filepath.Ext(exportPath), ".zip"):
+		archive, err := zip.OpenReader(exportPath)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to open Loop export: %w", err)
+		}
+		defer archive.Close()
+		fsys = archive
+	case strings.EqualFold(filepath.Ext(exportPath), ".db"):
+		return Backup{}, fmt.Errorf("Loop SQLite backups are not supported; use 'Export as CSV' in Loop's settings and import the zip file")
+	default:
+		return Backup{}, fmt.Errorf("unsupported Loop export: %s (expected the CSV zip file or its directory)", exportPath)
+	}
+
+	return readLoopFS(fsys)
+}
+
+// readLoopFS reads a Loop export from a zip archive or directory
+func readLoopFS(fsys fs.FS) (Backup, error) {
+	root, err := findFile(fsys, "Habits.csv")
+	if err != nil {
+		return Backup{}, err
+	}
+
+	habits, err := readLoopHabits(fsys, path.Join(root, "Habits.csv"))
+	if err != nil {
+		return Backup{}, err
+	}
+
+	// Older exports only have one Checkmarks.csv with a column per habit
+	var wide map[string]map[string]string
+	if _, err := fs.Stat(fsys, path.Join(root, "Checkmarks.csv")); err == nil {
+		if wide, err = readLoopWideCheckmarks(fsys, path.Join(root, "Checkmarks.csv")); err != nil {
+			return Backup{}, err
+		}
+	}
+
+	var backup Backup
+	for _, loop := range habits {
+		habit := loop.habit()
+		if habit.Name == "" {
+			backup.Skipped++
+			continue
+		}
+		backup.Habits = append(backup.Habits, habit)
+
+		checkmarks, err := readLoopCheckmarks(fsys, root, loop)
+		if err != nil {
+			return Backup{}, err
+		}
+		if checkmarks == nil {
+			checkmarks = wide[loop.Name]
+		}
+
+		days := make([]string, 0, len(checkmarks))
+		for day := range checkmarks {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+
+		for _, day := range days {
+			record, ok, err := loop.record(habit, day, checkmarks[day])
+			if err != nil {
+				return Backup{}, fmt.Errorf("%s: %w", loop.Name, err)
+			}
+			if ok {
+				backup.Records = append(backup.Records, record)
+			}
+		}
+	}
+
+	return backup, nil
+}
+
+// habit converts a Loop habit to a lazytrack habit
+func (h loopHabit) habit() types.Habit {
+	habit := types.Habit{Name: HabitName(h.Name), GoalType: "count"}
+	if h.isDuration() {
+		habit.GoalType = "duration"
+	}
+
+	reps := h.Reps
+	if h.Numerical {
+		reps = int(math.Round(h.Target))
+		if h.isDuration() && strings.HasPrefix(strings.ToLower(h.Unit), "min") {
+			reps = int(math.Floor(h.Target / 60)) // goals are whole hours
+		}
+	}
+	habit.DailyGoal, habit.WeeklyGoal = frequencyGoals(reps, h.Interval)
+	return habit
+}
+
+// isDuration reports whether a numerical habit measures time
+func (h loopHabit) isDuration() bool {
+	switch strings.ToLower(strings.TrimSpace(h.Unit)) {
+	case "min", "mins", "minute", "minutes", "h", "hr", "hrs", "hour", "hours":
+		return h.Numerical
+	}
+	return false
+}
+
+// record converts a checkmark value to a log record
+func (h loopHabit) record(habit types.Habit, day, value string) (exchange.Record, bool, error) {
+	date, err := time.ParseInLocation("2006-01-02", day, time.Local)
+	if err != nil {
+		return exchange.Record{}, false, fmt.Errorf("invalid date: %s", day)
+	}
+	record := exchange.Record{Habit: habit.Name, LoggedAt: checkmarkTime(date)}
+
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "", "UNKNOWN", "NO", "SKIP", "YES_AUTO":
+		return record, false, nil
+	case "YES_MANUAL":
+		record.Count = 1
+		return record, true, nil
+	}
+
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return exchange.Record{}, false, fmt.Errorf("invalid checkmark on %s: %s", day, value)
+	}
+
+	if !h.Numerical {
+		if number != loopYesManual {
+			return record, false, nil
+		}
+		record.Count = 1
+		return record, true, nil
+	}
+
+	amount := float64(number) / loopNumberUnit
+	if amount <= 0 {
+		return record, false, nil
+	}
+	if h.isDuration() {
+		minutes := amount
+		if !strings.HasPrefix(strings.ToLower(h.Unit), "min") {
+			minutes = amount * 60
+		}
+		record.Duration = FormatMinutes(time.Duration(minutes * float64(time.Minute)))
+		return record, record.Duration != "0m", nil
+	}
+	record.Count = max(1, int(math.Round(amount)))
+	return record, true, nil
+}
+
+// readLoopHabits reads Habits.csv, accepting both the old and new column names
+func readLoopHabits(fsys fs.FS, name string) ([]loopHabit, error) {
+	rows, columns, err := readCSVFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := columns["name"]; !exists {
+		return nil, fmt.Errorf("%s: missing Name column", name)
+	}
+
+	var habits []loopHabit
+	for _, fields := range rows {
+		field := func(names ...string) string {
+			for _, column := range names {
+				if i, exists := columns[column]; exists && i < len(fields) {
+					return strings.TrimSpace(fields[i])
+				}
+			}
+			return ""
+		}
+
+		habit := loopHabit{
+			Position: field("position"),
+			Name:     field("name"),
+			Unit:     field("unit"),
+			Reps:     1,
+			Interval: 1,
+		}
+		if reps, err := strconv.Atoi(field("numrepetitions", "frequency numerator")); err == nil {
+			habit.Reps = reps
+		}
+		if interval, err := strconv.Atoi(field("interval", "frequency denominator")); err == nil {
+			habit.Interval = interval
+		}
+		if target, err := strconv.ParseFloat(field("target value"), 64); err == nil {
+			habit.Target = target
+		}
+		habitType := strings.ToLower(field("type"))
+		habit.Numerical = habitType == "1" || habitType == "numerical" || (habitType == "" && habit.Target > 0)
+
+		habits = append(habits, habit)
+	}
+
+	return habits, nil
+}
+
+// readLoopCheckmarks reads the Checkmarks.csv of a habit's own directory
+// (e.g. "001 Meditate/Checkmarks.csv"), returning nil if there is none
+func readLoopCheckmarks(fsys fs.FS, root string, habit loopHabit) (map[string]string, error) {
+	dirs, err := fs.Glob(fsys, path.Join(root, "*", "Checkmarks.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range dirs {
+		dir := path.Base(path.Dir(name))
+		position, title, _ := strings.Cut(dir, " ")
+		samePosition := habit.Position != "" && strings.TrimLeft(position, "0") == strings.TrimLeft(habit.Position, "0")
+		if title != habit.Name && !samePosition {
+			continue
+		}
+
+		// Some versions write a header, others do not
+		rows, err := readCSVRows(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		checkmarks := make(map[string]string)
+		for _, fields := range rows {
+			if _, err := time.Parse("2006-01-02", strings.TrimSpace(fields[0])); err == nil && len(fields) >= 2 {
+				checkmarks[strings.TrimSpace(fields[0])] = fields[1]
+			}
+		}
+		return checkmarks, nil
+	}
+
+	return nil, nil
+}
+
+// readLoopWideCheckmarks reads a Checkmarks.csv with a date column and one column per habit
+func readLoopWideCheckmarks(fsys fs.FS, name string) (map[string]map[string]string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read CSV header: %w", name, err)
+	}
+
+	checkmarks := make(map[string]map[string]string)
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for i := 1; i < len(fields) && i < len(header); i++ {
+			habit := strings.TrimSpace(header[i])
+			if checkmarks[habit] == nil {
+				checkmarks[habit] = make(map[string]string)
+			}
+			checkmarks[habit][strings.TrimSpace(fields[0])] = fields[i]
+		}
+	}
+
+	return checkmarks, nil
+}
+
+// readCSVFile reads a CSV file with a header, returning its rows and lowercase column names
+func readCSVFile(fsys fs.FS, name string) ([][]string, map[string]int, error) {
+	records, err := readCSVRows(fsys, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int)
+	if len(records) == 0 {
+		return nil, columns, nil
+	}
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	return records[1:], columns, nil
+}
+
+// readCSVRows reads every row of a CSV file
+func readCSVRows(fsys fs.FS, name string) ([][]string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return records, nil
+}
+
+// findFile returns the directory holding the first file with the given name
+func findFile(fsys fs.FS, name string) (string, error) {
+	found := ""
+	err := fs.WalkDir(fsys, ".", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && entry.Name() == name {
+			found = path.Dir(p)
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("no %s found in the Loop export", name)
+	}
+	return found, nil
+}
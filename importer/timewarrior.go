@@ -0,0 +1,215 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+)
+
+// timewarriorTimeFormat is the UTC timestamp format used in Timewarrior data files
+const timewarriorTimeFormat = "20060102T150405Z"
+
+// ReadTimewarrior reads a Timewarrior data directory, a single YYYY-MM.data
+// file or the JSON output of `timew export`. Open intervals are skipped.
+func ReadTimewarrior(path string) ([]Entry, []exchange.LineError, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open Timewarrior data: %w", err)
+	}
+
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read Timewarrior data: %w", err)
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			entries, err := parseTimewarriorJSON(data)
+			return entries, nil, err
+		}
+		return parseTimewarriorData(bytes.NewReader(data), "")
+	}
+
+	// A data directory holds one file per month, e.g. ~/.timewarrior/data/2026-10.data
+	files, err := filepath.Glob(filepath.Join(path, "*.data"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no .data files found in %s", path)
+	}
+	sort.Strings(files)
+
+	var entries []Entry
+	var lineErrors []exchange.LineError
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read Timewarrior data: %w", err)
+		}
+		fileEntries, fileErrors, err := parseTimewarriorData(bytes.NewReader(data), filepath.Base(file))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		entries = append(entries, fileEntries...)
+		lineErrors = append(lineErrors, fileErrors...)
+	}
+
+	return entries, lineErrors, nil
+}
+
+// parseTimewarriorData parses lines like
+// `inc 20261017T090000Z - 20261017T103000Z # code "deep work" # "refactoring"`.
+// Entries and errors are marked with file, when reading several.
+func parseTimewarriorData(r io.Reader, file string) ([]Entry, []exchange.LineError, error) {
+	var entries []Entry
+	var lineErrors []exchange.LineError
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		entry, open, err := parseTimewarriorLine(text)
+		if err != nil {
+			lineErrors = append(lineErrors, exchange.LineError{File: file, Line: line, Err: err})
+			continue
+		}
+		if open {
+			continue
+		}
+		entry.File = file
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return entries, lineErrors, nil
+}
+
+// parseTimewarriorLine parses a single interval, reporting open intervals
+func parseTimewarriorLine(text string) (Entry, bool, error) {
+	tokens := tokenizeTimewarrior(text)
+	if len(tokens) < 2 || tokens[0] != "inc" {
+		return Entry{}, false, fmt.Errorf("not a Timewarrior interval: %s", text)
+	}
+
+	var entry Entry
+	var err error
+	if entry.Start, err = time.Parse(timewarriorTimeFormat, tokens[1]); err != nil {
+		return Entry{}, false, fmt.Errorf("invalid start time: %s", tokens[1])
+	}
+
+	rest := tokens[2:]
+	if len(rest) >= 2 && rest[0] == "-" {
+		if entry.End, err = time.Parse(timewarriorTimeFormat, rest[1]); err != nil {
+			return Entry{}, false, fmt.Errorf("invalid end time: %s", rest[1])
+		}
+		rest = rest[2:]
+	}
+
+	// Tags follow the first "#", the annotation follows the second one
+	section := 0
+	var annotation []string
+	for _, token := range rest {
+		if token == "#" {
+			section++
+			continue
+		}
+		switch section {
+		case 1:
+			entry.Tags = append(entry.Tags, token)
+		case 2:
+			annotation = append(annotation, token)
+		}
+	}
+	entry.Description = strings.Join(annotation, " ")
+
+	return entry, entry.End.IsZero(), nil
+}
+
+// tokenizeTimewarrior splits a line on spaces, keeping double-quoted strings together
+func tokenizeTimewarrior(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes, escaped, quoted := false, false, false
+
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, current.String())
+		}
+		current.Reset()
+		quoted = false
+	}
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			quoted = true
+		case r == ' ' && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// parseTimewarriorJSON parses the output of `timew export`
+func parseTimewarriorJSON(data []byte) ([]Entry, error) {
+	var intervals []struct {
+		Start      string   `json:"start"`
+		End        string   `json:"end"`
+		Tags       []string `json:"tags"`
+		Annotation string   `json:"annotation"`
+	}
+	if err := json.Unmarshal(data, &intervals); err != nil {
+		return nil, fmt.Errorf("invalid Timewarrior export: %w", err)
+	}
+
+	var entries []Entry
+	for i, interval := range intervals {
+		if interval.End == "" {
+			continue
+		}
+		start, err := time.Parse(timewarriorTimeFormat, interval.Start)
+		if err != nil {
+			return nil, fmt.Errorf("interval %d: invalid start time: %s", i+1, interval.Start)
+		}
+		end, err := time.Parse(timewarriorTimeFormat, interval.End)
+		if err != nil {
+			return nil, fmt.Errorf("interval %d: invalid end time: %s", i+1, interval.End)
+		}
+		entries = append(entries, Entry{
+			Line:        i + 1,
+			Start:       start,
+			End:         end,
+			Tags:        interval.Tags,
+			Description: interval.Annotation,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// utc returns a time on a day of October 2026 (or September for day 0) in UTC
+func utc(day, hour, minute int) time.Time {
+	return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestReadTimewarriorDirectory(t *testing.T) {
+	entries, lineErrors, err := ReadTimewarrior(filepath.Join("testdata", "timewarrior"))
+	if err != nil {
+		t.Fatalf("ReadTimewarrior: %v", err)
+	}
+
+	want := []Entry{
+		{File: "2026-09.data", Line: 1, Start: utc(0, 21, 0), End: utc(0, 22, 30), Tags: []string{"code", "deep work"}, Description: `refactoring the "store"`},
+		{File: "2026-09.data", Line: 3, Start: utc(0, 23, 0), End: utc(0, 23, 0).Add(20 * time.Second), Tags: []string{"code"}},
+		{File: "2026-10.data", Line: 1, Start: utc(1, 7, 0), End: utc(1, 7, 45), Tags: []string{"reading"}},
+		{File: "2026-10.data", Line: 3, Start: utc(1, 8, 0), End: utc(1, 8, 30), Tags: []string{"walk", "music"}, Description: "around the park"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got entries\n%+v\nwant\n%+v", entries, want)
+	}
+
+	// Each error names its file and the line in that file
+	var got []string
+	for _, lineErr := range lineErrors {
+		got = append(got, lineErr.Error())
+	}
+	wantErrors := []string{
+		"2026-10.data:2: not a Timewarrior interval: this is not an interval",
+		"2026-10.data:4: invalid start time: 20261001T09000Z",
+	}
+	if !reflect.DeepEqual(got, wantErrors) {
+		t.Errorf("got errors %q, want %q", got, wantErrors)
+	}
+}
+
+func TestReadTimewarriorFile(t *testing.T) {
+	entries, lineErrors, err := ReadTimewarrior(filepath.Join("testdata", "timewarrior", "2026-10.data"))
+	if err != nil {
+		t.Fatalf("ReadTimewarrior: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Line != 3 || entries[1].File != "" {
+		t.Errorf("got entries %+v, want 2 without a file name", entries)
+	}
+	if len(lineErrors) != 2 || lineErrors[0].Error() != "line 2: not a Timewarrior interval: this is not an interval" {
+		t.Errorf("got errors %v, want them by line", lineErrors)
+	}
+}
+
+func TestReadTimewarriorExport(t *testing.T) {
+	entries, lineErrors, err := ReadTimewarrior(filepath.Join("testdata", "timewarrior-export.json"))
+	if err != nil {
+		t.Fatalf("ReadTimewarrior: %v", err)
+	}
+	want := []Entry{
+		{Line: 1, Start: utc(1, 7, 0), End: utc(1, 7, 45), Tags: []string{"reading"}},
+		{Line: 2, Start: utc(1, 8, 0), End: utc(1, 8, 30), Tags: []string{"walk", "music"}, Description: "around the park"},
+	}
+	if !reflect.DeepEqual(entries, want) || len(lineErrors) != 0 {
+		t.Errorf("got %+v, %v, want\n%+v", entries, lineErrors, want)
+	}
+}
+
+func TestReadTimewarriorMissing(t *testing.T) {
+	if _, _, err := ReadTimewarrior(filepath.Join("testdata", "missing")); err == nil {
+		t.Error("read a missing directory")
+	}
+	if _, _, err := ReadTimewarrior(t.TempDir()); err == nil {
+		t.Error("read a directory without .data files")
+	}
+}
+
+func TestTokenizeTimewarrior(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{`inc 20261001T070000Z # reading`, []string{"inc", "20261001T070000Z", "#", "reading"}},
+		{`# "deep work" ""`, []string{"#", "deep work", ""}},
+		{`# "a \"quoted\" tag"`, []string{"#", `a "quoted" tag`}},
+	}
+	for _, test := range tests {
+		if got := tokenizeTimewarrior(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("tokenizeTimewarrior(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/exchange"
+)
+
+// ReadToggl reads a Toggl Track CSV export or a JSON export of time entries.
+// The format is detected from the file extension. Running entries are skipped.
+func ReadToggl(path string) ([]Entry, []exchange.LineError, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read Toggl export: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseTogglCSV(bytes.NewReader(data))
+	case ".json":
+		entries, err := parseTogglJSON(data)
+		return entries, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported Toggl export: %s (expected .csv or .json)", path)
+	}
+}
+
+// parseTogglCSV parses the detailed CSV report with "Start date", "Start time",
+// "End date", "End time", "Project", "Description" and "Tags" columns
+func parseTogglCSV(r io.Reader) ([]Entry, []exchange.LineError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("line 1: failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		// Strip the byte order mark Toggl puts in front of the first column
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"start date", "start time", "end date", "end time"} {
+		if _, exists := columns[required]; !exists {
+			return nil, nil, fmt.Errorf("line 1: missing %q column", required)
+		}
+	}
+
+	var entries []Entry
+	var lineErrors []exchange.LineError
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			lineErrors = append(lineErrors, exchange.LineError{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, exists := columns[name]; exists && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		start, err := parseTogglTime(field("start date"), field("start time"))
+		if err != nil {
+			lineErrors = append(lineErrors, exchange.LineError{Line: line, Err: err})
+			continue
+		}
+		end, err := parseTogglTime(field("end date"), field("end time"))
+		if err != nil {
+			lineErrors = append(lineErrors, exchange.LineError{Line: line, Err: err})
+			continue
+		}
+
+		entry := Entry{
+			Line:        line,
+			Start:       start,
+			End:         end,
+			Project:     field("project"),
+			Description: field("description"),
+		}
+		for _, tag := range strings.Split(field("tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				entry.Tags = append(entry.Tags, tag)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, lineErrors, nil
+}
+
+// parseTogglTime parses the local date and time columns of a CSV export
+func parseTogglTime(date, clock string) (time.Time, error) {
+	value := strings.TrimSpace(date + " " + clock)
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "01/02/2006 15:04:05", "02.01.2006 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date/time: %s", value)
+}
+
+// parseTogglJSON parses time entries from the Toggl API or a detailed report,
+// either as a plain array or wrapped in {"data": [...]}
+func parseTogglJSON(data []byte) ([]Entry, error) {
+	type togglEntry struct {
+		Description string    `json:"description"`
+		Start       time.Time `json:"start"`
+		Stop        time.Time `json:"stop"`
+		End         time.Time `json:"end"`
+		Project     string    `json:"project"`
+		ProjectName string    `json:"project_name"`
+		Tags        []string  `json:"tags"`
+	}
+
+	var items []togglEntry
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var report struct {
+			Data []togglEntry `json:"data"`
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("invalid Toggl export: %w", err)
+		}
+		items = report.Data
+	} else if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid Toggl export: %w", err)
+	}
+
+	var entries []Entry
+	for i, item := range items {
+		end := item.Stop
+		if end.IsZero() {
+			end = item.End
+		}
+		if end.IsZero() {
+			continue
+		}
+
+		project := item.Project
+		if project == "" {
+			project = item.ProjectName
+		}
+		entries = append(entries, Entry{
+			Line:        i + 1,
+			Start:       item.Start,
+			End:         end,
+			Project:     project,
+			Tags:        item.Tags,
+			Description: item.Description,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadTogglCSV(t *testing.T) {
+	local := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.Local)
+	}
+
+	entries, lineErrors, err := ReadToggl(filepath.Join("testdata", "toggl.csv"))
+	if err != nil {
+		t.Fatalf("ReadToggl: %v", err)
+	}
+	want := []Entry{
+		{Line: 2, Start: local(1, 9, 0), End: local(1, 10, 30), Project: "LazyTrack", Tags: []string{"deep work", "focus"}, Description: "Parser, tests"},
+		{Line: 3, Start: local(1, 18, 0), End: local(1, 18, 40), Tags: []string{"exercise"}, Description: "Evening run"},
+		{Line: 5, Start: local(2, 9, 0), End: local(2, 9, 20), Project: "LazyTrack", Description: "Multi\nline"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got entries\n%+v\nwant\n%+v", entries, want)
+	}
+	if len(lineErrors) != 1 || lineErrors[0].Error() != "line 4: invalid date/time: yesterday 09:00:00" {
+		t.Errorf("got errors %v, want line 4 to be invalid", lineErrors)
+	}
+}
+
+func TestReadTogglCSVMissingColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "toggl.csv")
+	if err := os.WriteFile(path, []byte("Project,Start date,Start time,End date\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadToggl(path); err == nil || err.Error() != `line 1: missing "end time" column` {
+		t.Errorf("got %v, want the missing column", err)
+	}
+}
+
+func TestReadTogglJSON(t *testing.T) {
+	tests := []struct {
+		file string
+		want []Entry
+	}{
+		{"toggl.json", []Entry{
+			{Line: 1, Start: utc(1, 7, 0), End: utc(1, 8, 30), Project: "LazyTrack", Tags: []string{"deep work"}, Description: "Parser"},
+			{Line: 2, Start: utc(1, 18, 0), End: utc(1, 18, 40), Tags: []string{"exercise"}, Description: "Evening run"},
+		}},
+		// Running entries have no stop time and are skipped
+		{"toggl-api.json", []Entry{
+			{Line: 1, Start: utc(1, 7, 0), End: utc(1, 8, 30), Project: "LazyTrack", Tags: []string{}, Description: "Parser"},
+		}},
+	}
+
+	for _, test := range tests {
+		entries, _, err := ReadToggl(filepath.Join("testdata", test.file))
+		if err != nil {
+			t.Fatalf("ReadToggl(%s): %v", test.file, err)
+		}
+		if len(entries) != len(test.want) {
+			t.Fatalf("%s: got %d entries, want %d", test.file, len(entries), len(test.want))
+		}
+		for i, entry := range entries {
+			want := test.want[i]
+			if !entry.Start.Equal(want.Start) || !entry.End.Equal(want.End) {
+				t.Errorf("%s entry %d: got %v-%v, want %v-%v", test.file, i, entry.Start, entry.End, want.Start, want.End)
+			}
+			entry.Start, entry.End, want.Start, want.End = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+			if !reflect.DeepEqual(entry, want) {
+				t.Errorf("%s entry %d: got %+v, want %+v", test.file, i, entry, want)
+			}
+		}
+	}
+}
+
+func TestReadTogglUnsupported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "toggl.xlsx")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadToggl(path); err == nil {
+		t.Error("read an .xlsx export")
+	}
+}
@@ -0,0 +1,242 @@
+package insights
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// MinScore is the minimum absolute t statistic for a relationship to be reported,
+// roughly a 95% confidence level for the sample sizes involved
+const MinScore = 2.0
+
+// habitSeries holds a habit's daily totals
+type habitSeries struct {
+	habit    types.Habit
+	values   []float64
+	firstDay int // index of the first logged day, -1 if never logged
+}
+
+// Calculate finds meaningful relationships between habits between startDate and endDate.
+// Only pairs with at least minSamples shared days are considered.
+func Calculate(habits []types.Habit, logsByHabit map[string][]types.Log, startDate, endDate time.Time, minSamples int) []types.Insight {
+	if minSamples < 3 {
+		minSamples = 3
+	}
+
+	// Build one daily series per habit
+	var series []habitSeries
+	for _, habit := range habits {
+		item := habitSeries{habit: habit, firstDay: -1}
+		for i, day := range summary.CalculateDailyTotals(habit, logsByHabit[habit.Name], startDate, endDate) {
+			item.values = append(item.values, day.Value)
+			if item.firstDay < 0 && day.Sessions > 0 {
+				item.firstDay = i
+			}
+		}
+		if item.firstDay >= 0 {
+			series = append(series, item)
+		}
+	}
+
+	var insights []types.Insight
+	for i, a := range series {
+		for j, b := range series {
+			if i == j {
+				continue
+			}
+
+			// Only compare days on which both habits were being tracked
+			first := a.firstDay
+			if b.firstDay > first {
+				first = b.firstDay
+			}
+			x, y := a.values[first:], b.values[first:]
+			if len(x) < minSamples {
+				continue
+			}
+
+			if i < j {
+				if insight, ok := correlationInsight(a.habit, b.habit, x, y); ok {
+					insights = append(insights, insight)
+				}
+			}
+			if insight, ok := conditionalInsight(a.habit, b.habit, x, y, minSamples); ok {
+				insights = append(insights, insight)
+			}
+		}
+	}
+
+	sort.SliceStable(insights, func(i, j int) bool {
+		return math.Abs(insights[i].Score) > math.Abs(insights[j].Score)
+	})
+
+	return insights
+}
+
+// correlationInsight checks whether two habits' daily values move together
+func correlationInsight(a, b types.Habit, x, y []float64) (types.Insight, bool) {
+	r, ok := Pearson(x, y)
+	if !ok || math.Abs(r) >= 1 {
+		return types.Insight{}, false
+	}
+
+	n := len(x)
+	score := r * math.Sqrt(float64(n-2)/(1-r*r))
+	if math.Abs(score) < MinScore {
+		return types.Insight{}, false
+	}
+
+	direction := "tend to go up together"
+	if r < 0 {
+		direction = "tend to move in opposite directions"
+	}
+
+	return types.Insight{
+		Kind:        "correlation",
+		Habit:       a.Name,
+		Target:      b.Name,
+		Correlation: r,
+		Samples:     n,
+		Score:       score,
+		Message:     fmt.Sprintf("%s %s and %s %s %s (r=%.2f over %d days)", a.Emoji, a.Name, b.Emoji, b.Name, direction, r, n),
+	}, true
+}
+
+// conditionalInsight compares b's daily average on days with and without a.
+// Habits with a daily goal are split on whether the goal was reached.
+func conditionalInsight(a, b types.Habit, x, y []float64, minSamples int) (types.Insight, bool) {
+	condition := "logged"
+	threshold := 0.0
+	if a.DailyGoal > 0 {
+		condition = "goal"
+		threshold = float64(a.DailyGoal)
+	}
+
+	var with, without []float64
+	for i := range x {
+		met := x[i] > 0
+		if condition == "goal" {
+			met = x[i] >= threshold
+		}
+		if met {
+			with = append(with, y[i])
+		} else {
+			without = append(without, y[i])
+		}
+	}
+
+	// Each group needs enough days to be meaningful
+	minGroup := minSamples / 4
+	if minGroup < 3 {
+		minGroup = 3
+	}
+	if len(with) < minGroup || len(without) < minGroup {
+		return types.Insight{}, false
+	}
+
+	withAverage, withoutAverage := average(with), average(without)
+	if withoutAverage == 0 {
+		return types.Insight{}, false
+	}
+
+	score, ok := WelchT(with, without)
+	if !ok || math.Abs(score) < MinScore {
+		return types.Insight{}, false
+	}
+
+	change := (withAverage - withoutAverage) / withoutAverage * 100
+	more := "more"
+	if change < 0 {
+		more = "less"
+	}
+
+	when := fmt.Sprintf("log %s", a.Name)
+	if condition == "goal" {
+		when = fmt.Sprintf("reach your %s goal", a.Name)
+	}
+
+	return types.Insight{
+		Kind:           "conditional",
+		Habit:          a.Name,
+		Target:         b.Name,
+		Condition:      condition,
+		WithAverage:    withAverage,
+		WithoutAverage: withoutAverage,
+		Change:         change,
+		Samples:        len(x),
+		Score:          score,
+		Message: fmt.Sprintf("%s On days you %s, you log %.0f%% %s %s (%s vs %s per day)",
+			b.Emoji, when, math.Abs(change), more, b.Name, formatValue(b, withAverage), formatValue(b, withoutAverage)),
+	}, true
+}
+
+// Pearson returns the Pearson correlation coefficient of x and y
+func Pearson(x, y []float64) (float64, bool) {
+	if len(x) != len(y) || len(x) < 3 {
+		return 0, false
+	}
+
+	meanX, meanY := average(x), average(y)
+	var covariance, varianceX, varianceY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+
+	if varianceX == 0 || varianceY == 0 {
+		return 0, false
+	}
+
+	return covariance / math.Sqrt(varianceX*varianceY), true
+}
+
+// WelchT returns Welch's t statistic for the difference between two sample means
+func WelchT(a, b []float64) (float64, bool) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, false
+	}
+
+	standardError := math.Sqrt(variance(a)/float64(len(a)) + variance(b)/float64(len(b)))
+	if standardError == 0 {
+		return 0, false
+	}
+
+	return (average(a) - average(b)) / standardError, true
+}
+
+// average returns the arithmetic mean of values
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+// variance returns the sample variance of values
+func variance(values []float64) float64 {
+	mean := average(values)
+	var total float64
+	for _, value := range values {
+		total += (value - mean) * (value - mean)
+	}
+	return total / float64(len(values)-1)
+}
+
+// formatValue formats a daily average in the habit's unit
+func formatValue(habit types.Habit, value float64) string {
+	if habit.GoalType == "count" {
+		return fmt.Sprintf("%.1fx", value)
+	}
+	return fmt.Sprintf("%.1fh", value)
+}
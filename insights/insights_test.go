@@ -0,0 +1,147 @@
+package insights
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+		ok   bool
+	}{
+		{"perfect", []float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 10}, 1, true},
+		{"perfectly opposite", []float64{1, 2, 3, 4, 5}, []float64{10, 8, 6, 4, 2}, -1, true},
+		{"known value", []float64{1, 2, 3, 4, 5}, []float64{2, 4, 5, 4, 5}, 0.7745967, true},
+		{"uncorrelated", []float64{1, 0, 1, 0}, []float64{1, 1, 2, 2}, 0, true},
+		{"different lengths", []float64{1, 2, 3}, []float64{1, 2}, 0, false},
+		{"too few values", []float64{1, 2}, []float64{1, 2}, 0, false},
+		{"constant", []float64{1, 2, 3}, []float64{4, 4, 4}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := Pearson(tt.x, tt.y)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s: got %v (%v), want %v (%v)", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWelchT(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+		ok   bool
+	}{
+		{"equal variances", []float64{1, 2, 3, 4, 5}, []float64{3, 4, 5, 6, 7}, -2, true},
+		{"unequal sizes", []float64{2, 4, 4, 5}, []float64{1, 1, 2, 2, 3, 3}, 2.4057019, true},
+		{"same means", []float64{1, 3}, []float64{0, 4}, 0, true},
+		{"too few values", []float64{1}, []float64{1, 2, 3}, 0, false},
+		{"no variance", []float64{2, 2, 2}, []float64{3, 3}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := WelchT(tt.a, tt.b)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s: got %v (%v), want %v (%v)", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCorrelationInsightMinScore(t *testing.T) {
+	a := types.Habit{Name: "sleep", GoalType: "duration"}
+	b := types.Habit{Name: "code", GoalType: "duration"}
+	x := []float64{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name  string
+		y     []float64
+		score float64
+		ok    bool
+	}{
+		{"above the threshold", []float64{2, 4, 5, 4, 5}, 2.1213203, true}, // r=0.77
+		{"below the threshold", []float64{1, 1, 2, 1, 5}, 0, false},        // r=0.73, t=1.85
+		{"perfect correlation", []float64{2, 4, 6, 8, 10}, 0, false},       // no t statistic
+		{"negative", []float64{5, 4, 5, 4, 2}, -2.1213203, true},           // r=-0.77
+	}
+	for _, tt := range tests {
+		insight, ok := correlationInsight(a, b, x, tt.y)
+		if ok != tt.ok || math.Abs(insight.Score-tt.score) > 1e-6 {
+			t.Errorf("%s: got score %v (%v), want %v (%v)", tt.name, insight.Score, ok, tt.score, tt.ok)
+		}
+	}
+}
+
+func TestConditionalInsightMinScore(t *testing.T) {
+	a := types.Habit{Name: "meditate", GoalType: "duration"}
+	b := types.Habit{Name: "code", GoalType: "duration"}
+	x := []float64{1, 1, 1, 1, 1, 0, 0, 0, 0, 0}
+
+	tests := []struct {
+		name       string
+		y          []float64
+		minSamples int
+		score      float64
+		change     float64
+		ok         bool
+	}{
+		// Means of 3 and 5 with a standard error of 1
+		{"at the threshold", []float64{1, 2, 3, 4, 5, 3, 4, 5, 6, 7}, 10, -2, -40, true},
+		{"below the threshold", []float64{1, 2, 3, 4, 5, 2.9, 3.9, 4.9, 5.9, 6.9}, 10, 0, 0, false},
+		{"groups too small", []float64{1, 2, 3, 4, 5, 3, 4, 5, 6, 7}, 24, 0, 0, false},
+	}
+	for _, tt := range tests {
+		insight, ok := conditionalInsight(a, b, x, tt.y, tt.minSamples)
+		if ok != tt.ok || math.Abs(insight.Score-tt.score) > 1e-6 || math.Abs(insight.Change-tt.change) > 1e-6 {
+			t.Errorf("%s: got score %v and change %v (%v), want %v and %v (%v)",
+				tt.name, insight.Score, insight.Change, ok, tt.score, tt.change, tt.ok)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 32)
+	habits := []types.Habit{
+		{Name: "meditate", GoalType: "count"},
+		{Name: "code", GoalType: "count"},
+		{Name: "water", GoalType: "count"},
+	}
+
+	// Meditating every other day goes with more code, while water follows
+	// a pattern unrelated to either
+	logs := make(map[string][]types.Log)
+	for i := 0; i < 32; i++ {
+		loggedAt := start.AddDate(0, 0, i).Add(12 * time.Hour)
+		code := []int{2, 1, 3, 1}[i%4]
+		water := 1 + i%8/4
+		if i%2 == 0 {
+			logs["meditate"] = append(logs["meditate"], types.Log{HabitName: "meditate", Count: 1, LoggedAt: loggedAt})
+		}
+		logs["code"] = append(logs["code"], types.Log{HabitName: "code", Count: code, LoggedAt: loggedAt})
+		logs["water"] = append(logs["water"], types.Log{HabitName: "water", Count: water, LoggedAt: loggedAt})
+	}
+
+	insights := Calculate(habits, logs, start, end, 14)
+	found := make(map[string]types.Insight)
+	for _, insight := range insights {
+		found[insight.Kind+" "+insight.Habit+" "+insight.Target] = insight
+	}
+	if len(found) != 2 {
+		t.Fatalf("got %d insights, want 2: %+v", len(insights), insights)
+	}
+	if insight, ok := found["correlation meditate code"]; !ok || math.Abs(insight.Correlation-0.9045340) > 1e-6 {
+		t.Errorf("correlation: got %+v, want r=0.90", insight)
+	}
+	if insight, ok := found["conditional meditate code"]; !ok || insight.Change != 150 || insight.Samples != 32 {
+		t.Errorf("conditional: got %+v, want 150%% more over 32 days", insight)
+	}
+
+	if insights := Calculate(habits, logs, start, end, 40); len(insights) != 0 {
+		t.Errorf("got %d insights with too few shared days, want none", len(insights))
+	}
+}
@@ -25,6 +25,30 @@ Perfect for developers, students, and anyone who loves the command line!`,
 	rootCmd.AddCommand(cmd.NewConfigCmd())
 	rootCmd.AddCommand(cmd.NewReminderCmd())
 	rootCmd.AddCommand(cmd.NewDaemonCmd())
+	rootCmd.AddCommand(cmd.NewCompareCmd())
+	rootCmd.AddCommand(cmd.NewStatsCmd())
+	rootCmd.AddCommand(cmd.NewInsightsCmd())
+	rootCmd.AddCommand(cmd.NewForecastCmd())
+	rootCmd.AddCommand(cmd.NewReportCmd())
+	rootCmd.AddCommand(cmd.NewChartCmd())
+	rootCmd.AddCommand(cmd.NewExportCmd())
+	rootCmd.AddCommand(cmd.NewImportCmd())
+	rootCmd.AddCommand(cmd.NewGitImportCmd())
+	rootCmd.AddCommand(cmd.NewGitHookCmd())
+	rootCmd.AddCommand(cmd.NewServeCmd())
+	rootCmd.AddCommand(cmd.NewImportActivityCmd())
+	rootCmd.AddCommand(cmd.NewSyncCmd())
+	rootCmd.AddCommand(cmd.NewDailyNoteCmd())
+	rootCmd.AddCommand(cmd.NewSnoozeCmd())
+	rootCmd.AddCommand(cmd.NewDismissCmd())
+	rootCmd.AddCommand(cmd.NewNotifyCmd())
+	rootCmd.AddCommand(cmd.NewTemplatesCmd())
+
+	// Load the message templates before any message is shown (templates
+	// check reports broken ones itself)
+	if len(os.Args) < 2 || os.Args[1] != "templates" {
+		cmd.LoadTemplates()
+	}
 
 	// Set up default behavior for logging habits
 	rootCmd.SetHelpCommand(&cobra.Command{
@@ -35,7 +59,7 @@ Perfect for developers, students, and anyone who loves the command line!`,
 	// Handle case where no subcommand is provided (treat as log command)
 	if len(os.Args) > 1 && os.Args[1] != "help" && os.Args[1] != "--help" && os.Args[1] != "-h" && os.Args[1] != "version" && os.Args[1] != "--version" && os.Args[1] != "-v" {
 		// Check if it's not a known subcommand
-		knownCommands := []string{"summary", "config", "reminder", "daemon", "help", "version"}
+		knownCommands := []string{"summary", "config", "reminder", "daemon", "compare", "stats", "insights", "forecast", "report", "chart", "export", "import", "git-import", "git-hook", "serve", "import-activity", "sync", "daily-note", "snooze", "dismiss", "notify", "templates", "help", "version"}
 		isKnownCommand := false
 		for _, cmd := range knownCommands {
 			if os.Args[1] == cmd {
@@ -0,0 +1,247 @@
+package message
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Names of the templates, each of which can be overridden by a <name>.tmpl
+// file in the templates directory
+const (
+	Reminder     = "reminder"      // a habit's reminder, for habits without a goal
+	GoalReminder = "goal_reminder" // a reminder of a habit's pending goal
+	LateReminder = "late_reminder" // the late reminder for pending goals
+	Logged       = "logged"        // shown when a habit is logged
+	GoalReached  = "goal_reached"  // shown when a log reaches the daily goal
+	Motivation   = "motivation"    // the motivational message of summaries
+)
+
+// DirName is the directory of the templates, in the data directory
+const DirName = "templates"
+
+// Data is what templates can use. Which fields are set depends on the template.
+type Data struct {
+	Habit     string
+	Emoji     string
+	GoalType  string // "count" or "duration"
+	Progress  Amount // today's progress
+	Goal      Amount // the daily goal
+	Remaining Amount // what's left of the goal today, 0 once it's reached
+	Percent   float64
+	Streak    int      // days in a row with logs
+	Logged    string   // the logged amount, e.g. "30m" or "2 times" (logged)
+	Habits    []string // the pending habits (late_reminder), or the habits with progress (motivation)
+	Hints     []string // what's needed to stay on pace, e.g. "45m more code" (late_reminder)
+	Trend     string   // the biggest change from the previous period, if any (motivation)
+}
+
+// Amount is a count or a number of hours. It's printed like "3 times" or
+// "1h30m", and .Value is the number.
+type Amount struct {
+	Value float64
+	Count bool
+}
+
+// NewAmount returns an amount in a habit's unit
+func NewAmount(goalType string, value float64) Amount {
+	return Amount{Value: value, Count: goalType == "count"}
+}
+
+func (a Amount) String() string {
+	if a.Count {
+		return parser.FormatCount(int(a.Value))
+	}
+	minutes := int(a.Value*60 + 0.5)
+	return parser.FormatDuration(types.ParsedDuration{Hours: minutes / 60, Minutes: minutes % 60, IsValid: true})
+}
+
+// defaults are the templates used unless they're overridden
+var defaults = map[string]string{
+	Reminder: `Time for {{.Emoji}} {{.Habit}}!`,
+	GoalReminder: `{{if eq .GoalType "count" -}}
+You've completed {{.Progress.Value}}/{{.Goal.Value}} {{.Habit}} today. Don't forget to reach your goal!
+{{- else -}}
+You've logged {{printf "%.1f" .Progress.Value}} hours of {{.Habit}} today. Keep going!
+{{- end}}`,
+	LateReminder: `It's getting late! You still have pending goals: {{join .Habits}}
+{{- if .Hints}}. You need {{join .Hints}} today to stay on pace.{{end}}`,
+	Logged: `✅ Logged "{{.Habit}}" for {{.Logged}}
+{{.Emoji}} {{.Habit}}`,
+	GoalReached: `🎉 Goal reached for {{.Habit}} today!`,
+	Motivation: `{{if .Trend}}{{.Trend}}
+{{- else if not .Habits}}🌟 Every journey starts with a single step! Log your first habit today!
+{{- else if ge .Percent 100.0}}🎉 Amazing! You're crushing your goals this week!
+{{- else if ge .Percent 80.0}}🚀 Great progress! You're so close to your goals!
+{{- else if ge .Percent 60.0}}💪 Good work! Keep up the momentum!
+{{- else if ge .Percent 40.0}}👍 You're making progress! Every bit counts!
+{{- else if ge .Percent 20.0}}🌱 Getting started is the hardest part. You're doing great!
+{{- else}}🌟 Every small step counts! Keep going!{{end}}`,
+}
+
+// samples are the data templates are checked with when they're loaded
+var samples = []Data{
+	{
+		Habit: "water", Emoji: "💧", GoalType: "count",
+		Progress: NewAmount("count", 3), Goal: NewAmount("count", 8), Remaining: NewAmount("count", 5),
+		Percent: 37.5, Streak: 4, Logged: "1 time",
+		Habits: []string{"water", "code"}, Hints: []string{"5x more water"}, Trend: "📈 You logged 20% more water than last week! Keep it up!",
+	},
+	{
+		Habit: "code", Emoji: "💻", GoalType: "duration",
+		Progress: NewAmount("duration", 1.5), Goal: NewAmount("duration", 2), Remaining: NewAmount("duration", 0.5),
+		Percent: 75, Logged: "30m",
+	},
+	{},
+}
+
+// funcs are the functions templates can use besides the built-in ones
+var funcs = template.FuncMap{
+	"join":  Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Templates are the message templates
+type Templates struct {
+	templates map[string]*template.Template
+	custom    map[string]string // name -> file, for the overridden ones
+}
+
+// current are the templates Render uses
+var current = Default()
+
+// Default returns the default templates
+func Default() *Templates {
+	t := &Templates{templates: make(map[string]*template.Template), custom: make(map[string]string)}
+	for name, text := range defaults {
+		t.templates[name] = template.Must(parse(name, text))
+	}
+	return t
+}
+
+// Load loads the templates in a directory over the defaults. Each file is
+// checked against sample data; the returned templates keep the default of
+// files that fail, and the error describes every failure.
+func Load(dir string) (*Templates, error) {
+	t := Default()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return t, fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".tmpl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if _, known := defaults[name]; !known {
+			errs = append(errs, fmt.Errorf("%s: unknown template (use one of %s)", path, strings.Join(Names(), ", ")))
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read template: %w", err))
+			continue
+		}
+		tmpl, err := check(name, string(data))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		t.templates[name] = tmpl
+		t.custom[name] = path
+	}
+	return t, errors.Join(errs...)
+}
+
+// check parses a template and executes it on the sample data
+func check(name, text string) (*template.Template, error) {
+	tmpl, err := parse(name, text)
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range samples {
+		if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// parse parses a template
+func parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+}
+
+// Names returns the names of the templates
+func Names() []string {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultText returns the text of a template's default
+func DefaultText(name string) string {
+	return defaults[name]
+}
+
+// Source returns the file a template was loaded from, or "" for a default
+func (t *Templates) Source(name string) string {
+	return t.custom[name]
+}
+
+// Sample renders a template with sample data
+func (t *Templates) Sample(name string) string {
+	return t.Render(name, samples[0])
+}
+
+// Render renders a template, falling back to its default if it fails
+func (t *Templates) Render(name string, data Data) string {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return ""
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil && t.custom[name] != "" {
+		return Default().Render(name, data)
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// Use makes Render use templates, e.g. ones loaded from the templates directory
+func Use(t *Templates) {
+	current = t
+}
+
+// Render renders a template of the templates in use
+func Render(name string, data Data) string {
+	return current.Render(name, data)
+}
+
+// Join joins words like "a, b and c"
+func Join(words []string) string {
+	switch len(words) {
+	case 0:
+		return "none"
+	case 1:
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
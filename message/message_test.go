@@ -0,0 +1,131 @@
+package message
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemplates writes files to a temporary templates directory
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"reminder.tmpl":      `Go {{upper .Habit}}!`,
+		"unknown.tmpl":       `{{.Habit}}`,
+		"goal_reached.tmpl":  `{{.Habit`,            // doesn't parse
+		"logged.tmpl":        `{{.Mood}}`,           // no such field
+		"late_reminder.tmpl": `{{index .Habits 0}}`, // fails for samples without habits
+		"motivation.txt":     `{{.Habit`,            // not a template file
+	})
+	if err := os.Mkdir(filepath.Join(dir, "old.tmpl"), 0755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+
+	templates, err := Load(dir)
+	if err == nil {
+		t.Fatal("got no error, want one for each invalid template")
+	}
+	for _, want := range []string{
+		filepath.Join(dir, "unknown.tmpl") + ": unknown template (use one of " + strings.Join(Names(), ", ") + ")",
+		filepath.Join(dir, "goal_reached.tmpl") + ": template: goal_reached",
+		filepath.Join(dir, "logged.tmpl") + ": template: logged",
+		filepath.Join(dir, "late_reminder.tmpl") + ": template: late_reminder",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+	if lines := strings.Count(err.Error(), "\n") + 1; lines != 4 {
+		t.Errorf("got %d errors, want 4:\n%v", lines, err)
+	}
+
+	if got, want := templates.Render(Reminder, Data{Habit: "code"}), "Go CODE!"; got != want {
+		t.Errorf("reminder: got %q, want %q", got, want)
+	}
+	if got, want := templates.Source(Reminder), filepath.Join(dir, "reminder.tmpl"); got != want {
+		t.Errorf("reminder source: got %q, want %q", got, want)
+	}
+
+	// Templates that failed keep their defaults
+	defaults := Default()
+	data := samples[0]
+	for _, name := range []string{GoalReached, Logged, LateReminder, Motivation} {
+		if source := templates.Source(name); source != "" {
+			t.Errorf("%s: got source %q, want the default", name, source)
+		}
+		if got, want := templates.Render(name, data), defaults.Render(name, data); got != want {
+			t.Errorf("%s: got %q, want the default %q", name, got, want)
+		}
+	}
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	templates, err := Load(filepath.Join(t.TempDir(), DirName))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, name := range Names() {
+		if source := templates.Source(name); source != "" {
+			t.Errorf("%s: got source %q, want the default", name, source)
+		}
+	}
+}
+
+func TestRenderFallsBackToDefault(t *testing.T) {
+	// Works for the sample data, but not for a habit without hints
+	dir := writeTemplates(t, map[string]string{
+		"reminder.tmpl": `{{if eq .Habit "gym"}}{{index .Hints 0}}{{else}}Time for {{.Habit}}{{end}}`,
+	})
+	templates, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	Use(templates)
+	t.Cleanup(func() { Use(Default()) })
+
+	tests := []struct {
+		name string
+		data Data
+		want string
+	}{
+		{"custom", Data{Habit: "code", Emoji: "💻"}, "Time for code"},
+		{"failing custom", Data{Habit: "gym", Emoji: "🏋️"}, "Time for 🏋️ gym!"},
+	}
+	for _, tt := range tests {
+		if got := Render(Reminder, tt.data); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := Render("unknown", Data{Habit: "code"}); got != "" {
+		t.Errorf("unknown template: got %q, want nothing", got)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{nil, "none"},
+		{[]string{"code"}, "code"},
+		{[]string{"code", "gym"}, "code and gym"},
+		{[]string{"code", "gym", "water"}, "code, gym and water"},
+	}
+	for _, tt := range tests {
+		if got := Join(tt.words); got != tt.want {
+			t.Errorf("Join(%v): got %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
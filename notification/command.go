@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command runs a command for each notification. The notification is passed
+// as JSON on stdin and in the LAZYTRACK_TYPE, LAZYTRACK_TITLE,
+// LAZYTRACK_MESSAGE and LAZYTRACK_HABIT environment variables.
+type Command struct {
+	Argv []string
+}
+
+// Notify runs the command
+func (c *Command) Notify(ctx context.Context, n Notification) error {
+	input, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, expandHome(c.Argv[0]), c.Argv[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"LAZYTRACK_TYPE="+n.Type,
+		"LAZYTRACK_TITLE="+n.Title,
+		"LAZYTRACK_MESSAGE="+n.Message,
+		"LAZYTRACK_HABIT="+n.Habit,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+	return nil
+}
+
+// expandHome expands a leading ~/ to the home directory
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return homeDir + path[1:]
+		}
+	}
+	return path
+}
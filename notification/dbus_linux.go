@@ -0,0 +1,361 @@
+//go:build linux
+
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The notification service of the Desktop Notifications Specification
+const (
+	dbusNotificationsName = "org.freedesktop.Notifications"
+	dbusNotificationsPath = "/org/freedesktop/Notifications"
+)
+
+// D-Bus message types and header fields used here
+const (
+	dbusMethodCall   = 1
+	dbusMethodReturn = 2
+	dbusError        = 3
+
+	dbusFieldPath        = 1
+	dbusFieldInterface   = 2
+	dbusFieldMember      = 3
+	dbusFieldErrorName   = 4
+	dbusFieldReplySerial = 5
+	dbusFieldDestination = 6
+	dbusFieldSignature   = 8
+)
+
+// dbusMaxMessage bounds the size of messages read from the bus
+const dbusMaxMessage = 1 << 20
+
+// notifyDBus shows a notification by calling Notify of the
+// org.freedesktop.Notifications service on the session bus. Silent
+// notifications ask the server not to play a sound.
+func notifyDBus(ctx context.Context, title, message string, silent bool) error {
+	conn, err := dialSessionBus(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	r := bufio.NewReader(conn)
+	if err := dbusAuth(conn, r); err != nil {
+		return err
+	}
+
+	// Every connection has to say hello before it may call anything
+	if err := dbusCall(conn, r, 1, "org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello", "", nil); err != nil {
+		return err
+	}
+
+	return dbusCall(conn, r, 2, dbusNotificationsName, dbusNotificationsPath, dbusNotificationsName, "Notify", "susssasa{sv}i", notifyBody(title, message, silent))
+}
+
+// notifyBody marshals the arguments of Notify(app_name, replaces_id,
+// app_icon, summary, body, actions, hints, expire_timeout)
+func notifyBody(title, message string, silent bool) []byte {
+	body := &dbusEncoder{}
+	body.string("LazyTrack")
+	body.uint32(0)
+	body.string("")
+	body.string(title)
+	body.string(escapeMarkup(message))
+	body.uint32(0) // no actions
+	body.uint32(0) // length of the hints, filled in below
+	hintsLength := len(body.buf) - 4
+	body.align(8) // an empty array is still padded to its (dict entry) elements
+	hintsStart := len(body.buf)
+	if silent {
+		body.align(8)
+		body.string("suppress-sound")
+		body.signature("b")
+		body.uint32(1)
+	}
+	binary.LittleEndian.PutUint32(body.buf[hintsLength:], uint32(len(body.buf)-hintsStart))
+	body.uint32(0xFFFFFFFF)
+	return body.buf
+}
+
+// dialSessionBus connects to the session bus of DBUS_SESSION_BUS_ADDRESS, or
+// the usual socket in /run/user if it isn't set
+func dialSessionBus(ctx context.Context) (net.Conn, error) {
+	address := os.Getenv("DBUS_SESSION_BUS_ADDRESS")
+	if address == "" {
+		address = fmt.Sprintf("unix:path=/run/user/%d/bus", os.Getuid())
+	}
+
+	lastErr := fmt.Errorf("no supported D-Bus address in %q", address)
+	for _, entry := range strings.Split(address, ";") {
+		transport, params, ok := strings.Cut(entry, ":")
+		if !ok || transport != "unix" {
+			continue
+		}
+		var socket string
+		for _, param := range strings.Split(params, ",") {
+			key, value, _ := strings.Cut(param, "=")
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			switch key {
+			case "path":
+				socket = value
+			case "abstract":
+				socket = "@" + value
+			}
+		}
+		if socket == "" {
+			continue
+		}
+		conn, err := (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// dbusAuth authenticates as the current user (SASL EXTERNAL)
+func dbusAuth(conn net.Conn, r *bufio.Reader) error {
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := fmt.Fprintf(conn, "\x00AUTH EXTERNAL %s\r\n", uid); err != nil {
+		return err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		return fmt.Errorf("D-Bus authentication failed: %s", strings.TrimSpace(line))
+	}
+	_, err = io.WriteString(conn, "BEGIN\r\n")
+	return err
+}
+
+// dbusCall calls a method and waits for its reply
+func dbusCall(conn net.Conn, r *bufio.Reader, serial uint32, destination, path, iface, member, signature string, body []byte) error {
+	if _, err := conn.Write(dbusMethodCallMessage(serial, destination, path, iface, member, signature, body)); err != nil {
+		return err
+	}
+
+	// Skip signals and other replies until the call's reply arrives
+	for {
+		reply, err := readDBusMessage(r)
+		if err != nil {
+			return err
+		}
+		if reply.replySerial != serial {
+			continue
+		}
+		switch reply.msgType {
+		case dbusMethodReturn:
+			return nil
+		case dbusError:
+			if reply.errorMessage != "" {
+				return fmt.Errorf("%s: %s", reply.errorName, reply.errorMessage)
+			}
+			return fmt.Errorf("%s", reply.errorName)
+		}
+	}
+}
+
+// dbusMethodCallMessage marshals a method call
+func dbusMethodCallMessage(serial uint32, destination, path, iface, member, signature string, body []byte) []byte {
+	msg := &dbusEncoder{}
+	msg.buf = append(msg.buf, 'l', dbusMethodCall, 0, 1)
+	msg.uint32(uint32(len(body)))
+	msg.uint32(serial)
+	msg.uint32(0) // length of the header fields, filled in below
+	msg.field(dbusFieldPath, "o", path)
+	msg.field(dbusFieldDestination, "s", destination)
+	msg.field(dbusFieldInterface, "s", iface)
+	msg.field(dbusFieldMember, "s", member)
+	if signature != "" {
+		msg.field(dbusFieldSignature, "g", signature)
+	}
+	binary.LittleEndian.PutUint32(msg.buf[12:], uint32(len(msg.buf)-16))
+	msg.align(8)
+	return append(msg.buf, body...)
+}
+
+// dbusEncoder marshals values in little endian D-Bus wire format. Values are
+// aligned relative to the start of buf, which must be the start of a message
+// or its body.
+type dbusEncoder struct {
+	buf []byte
+}
+
+func (e *dbusEncoder) align(n int) {
+	for len(e.buf)%n != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *dbusEncoder) uint32(v uint32) {
+	e.align(4)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+func (e *dbusEncoder) string(s string) {
+	e.uint32(uint32(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+func (e *dbusEncoder) signature(s string) {
+	e.buf = append(e.buf, byte(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+// field appends a header field, a struct of its code and a variant
+func (e *dbusEncoder) field(code byte, signature, value string) {
+	e.align(8)
+	e.buf = append(e.buf, code)
+	e.signature(signature)
+	if signature == "g" {
+		e.signature(value)
+	} else {
+		e.string(value)
+	}
+}
+
+// dbusMessage is the part of a message read from the bus that matters here
+type dbusMessage struct {
+	msgType      byte
+	replySerial  uint32
+	errorName    string
+	errorMessage string
+}
+
+// readDBusMessage reads a message from the bus
+func readDBusMessage(r *bufio.Reader) (*dbusMessage, error) {
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder
+	switch fixed[0] {
+	case 'l':
+		order = binary.LittleEndian
+	case 'B':
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid D-Bus message")
+	}
+	bodyLength := int(order.Uint32(fixed[4:8]))
+	fieldsEnd := 16 + int(order.Uint32(fixed[12:16]))
+	bodyStart := (fieldsEnd + 7) &^ 7
+	if bodyStart+bodyLength > dbusMaxMessage {
+		return nil, fmt.Errorf("D-Bus message too large")
+	}
+	buf := make([]byte, bodyStart+bodyLength)
+	copy(buf, fixed)
+	if _, err := io.ReadFull(r, buf[16:]); err != nil {
+		return nil, err
+	}
+
+	msg := &dbusMessage{msgType: fixed[1]}
+	d := &dbusDecoder{buf: buf[:fieldsEnd], pos: 16, order: order}
+	var bodySignature string
+	for d.pos < fieldsEnd && d.err == nil {
+		d.align(8)
+		code := d.byte()
+		switch signature := d.signature(); signature {
+		case "s", "o":
+			value := d.string()
+			if code == dbusFieldErrorName {
+				msg.errorName = value
+			}
+		case "u":
+			value := d.uint32()
+			if code == dbusFieldReplySerial {
+				msg.replySerial = value
+			}
+		case "g":
+			value := d.signature()
+			if code == dbusFieldSignature {
+				bodySignature = value
+			}
+		default:
+			return nil, fmt.Errorf("unexpected D-Bus header field type %s", signature)
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	// An error's body starts with its message
+	if msg.msgType == dbusError && strings.HasPrefix(bodySignature, "s") {
+		body := &dbusDecoder{buf: buf[bodyStart:], order: order}
+		msg.errorMessage = body.string()
+	}
+	return msg, nil
+}
+
+// dbusDecoder unmarshals values in D-Bus wire format
+type dbusDecoder struct {
+	buf   []byte
+	pos   int
+	order binary.ByteOrder
+	err   error
+}
+
+func (d *dbusDecoder) align(n int) {
+	d.pos = (d.pos + n - 1) / n * n
+}
+
+func (d *dbusDecoder) next(n int) []byte {
+	if d.err != nil || d.pos+n > len(d.buf) {
+		d.err = fmt.Errorf("truncated D-Bus message")
+		return make([]byte, n)
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *dbusDecoder) byte() byte {
+	return d.next(1)[0]
+}
+
+func (d *dbusDecoder) uint32() uint32 {
+	d.align(4)
+	return d.order.Uint32(d.next(4))
+}
+
+func (d *dbusDecoder) string() string {
+	n := int(d.uint32())
+	if n > len(d.buf) {
+		d.err = fmt.Errorf("truncated D-Bus message")
+		return ""
+	}
+	s := string(d.next(n))
+	d.next(1)
+	return s
+}
+
+func (d *dbusDecoder) signature() string {
+	n := int(d.byte())
+	s := string(d.next(n))
+	d.next(1)
+	return s
+}
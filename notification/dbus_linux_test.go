@@ -0,0 +1,248 @@
+//go:build linux
+
+package notification
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// unhex decodes hex with spaces and comments (from "#" to the end of a line)
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	var digits strings.Builder
+	for _, line := range strings.Split(s, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		digits.WriteString(strings.Join(strings.Fields(line), ""))
+	}
+	b, err := hex.DecodeString(digits.String())
+	if err != nil {
+		t.Fatalf("bad hex: %v", err)
+	}
+	return b
+}
+
+func TestNotifyBody(t *testing.T) {
+	// The arguments of Notify, laid out by hand from the D-Bus specification
+	prefix := `
+		09000000 4c617a79547261636b00 0000  # "LazyTrack", padded to 4
+		00000000                            # replaces_id
+		00000000 00 000000                  # app_icon "", padded to 4
+		01000000 5400 0000                  # summary "T", padded to 4
+		06000000 61266c743b6200 00          # body "a&lt;b", escaped, padded to 4
+		00000000                            # actions: empty array of strings
+	`
+	// With a longer title, the hints array starts off an 8 byte boundary
+	longPrefix := `
+		09000000 4c617a79547261636b00 0000  # "LazyTrack", padded to 4
+		00000000                            # replaces_id
+		00000000 00 000000                  # app_icon "", padded to 4
+		05000000 5469746c6500 0000          # summary "Title", padded to 4
+		06000000 61266c743b6200 00          # body "a&lt;b", escaped, padded to 4
+		00000000                            # actions: empty array of strings
+	`
+	tests := []struct {
+		name   string
+		title  string
+		silent bool
+		want   string
+	}{
+		{"padded with sound", "Title", false, longPrefix + `
+			00000000 00000000                   # hints: empty, still padded to 8
+			ffffffff                            # expire_timeout -1
+		`},
+		{"padded silent", "Title", true, longPrefix + `
+			1c000000 00000000                   # hints: 28 bytes, padded to 8
+			0e000000 73757070726573732d736f756e6400  # "suppress-sound"
+			01 6200 0000                        # variant signature "b", padded to 4
+			01000000                            # true
+			ffffffff                            # expire_timeout -1
+		`},
+		{"with sound", "T", false, prefix + `
+			00000000                            # hints: empty (already aligned to 8)
+			ffffffff                            # expire_timeout -1
+		`},
+		{"silent", "T", true, prefix + `
+			1c000000                            # hints: 28 bytes (already aligned to 8)
+			0e000000 73757070726573732d736f756e6400  # "suppress-sound"
+			01 6200 0000                        # variant signature "b", padded to 4
+			01000000                            # true
+			ffffffff                            # expire_timeout -1
+		`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := notifyBody(test.title, "a<b", test.silent)
+			if want := unhex(t, test.want); !bytes.Equal(got, want) {
+				t.Errorf("notifyBody =\n%s\nwant\n%s", hex.Dump(got), hex.Dump(want))
+			}
+		})
+	}
+}
+
+func TestDBusMethodCallMessage(t *testing.T) {
+	body := []byte{1, 2, 3}
+	got := dbusMethodCallMessage(2, dbusNotificationsName, dbusNotificationsPath, dbusNotificationsName, "Notify", "susssasa{sv}i", body)
+
+	want := unhex(t, `
+		6c 01 00 01                         # little endian, method call, no flags, version 1
+		03000000                            # body length
+		02000000                            # serial
+		9b000000                            # header fields length
+		01 016f00 1e000000 2f6f72672f667265656465736b746f702f4e6f74696669636174696f6e7300 00  # path
+		06 017300 1d000000 6f72672e667265656465736b746f702e4e6f74696669636174696f6e7300 0000  # destination
+		02 017300 1d000000 6f72672e667265656465736b746f702e4e6f74696669636174696f6e7300 0000  # interface
+		03 017300 06000000 4e6f7469667900 00  # member
+		08 016700 0d 7375737373617361 7b73767d6900  # signature
+		0000000000                          # header padded to 8
+		010203                              # body
+	`)
+	if !bytes.Equal(got, want) {
+		t.Errorf("dbusMethodCallMessage =\n%s\nwant\n%s", hex.Dump(got), hex.Dump(want))
+	}
+}
+
+// fakeBus is a session bus stand-in that records the method calls it gets
+// and answers them
+type fakeBus struct {
+	calls  chan []byte
+	answer func(serial uint32) []byte
+}
+
+// serve accepts a connection, authenticates it and answers its calls
+func (b *fakeBus) serve(t *testing.T, listener net.Listener) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	if line, err := r.ReadString('\n'); err != nil || !strings.HasPrefix(line, "\x00AUTH EXTERNAL ") {
+		t.Errorf("bad auth %q: %v", line, err)
+		return
+	}
+	io.WriteString(conn, "OK 0123456789abcdef\r\n")
+	if line, _ := r.ReadString('\n'); line != "BEGIN\r\n" {
+		t.Errorf("got %q, want BEGIN", line)
+		return
+	}
+
+	for {
+		fixed := make([]byte, 16)
+		if _, err := io.ReadFull(r, fixed); err != nil {
+			return
+		}
+		bodyLength := int(binary.LittleEndian.Uint32(fixed[4:]))
+		fieldsEnd := 16 + int(binary.LittleEndian.Uint32(fixed[12:]))
+		msg := make([]byte, (fieldsEnd+7)&^7+bodyLength)
+		copy(msg, fixed)
+		if _, err := io.ReadFull(r, msg[16:]); err != nil {
+			return
+		}
+		b.calls <- msg
+
+		// A signal comes first, which has to be skipped
+		conn.Write(dbusTestMessage(4, 0, "", ""))
+		conn.Write(b.answer(binary.LittleEndian.Uint32(fixed[8:])))
+	}
+}
+
+// dbusTestMessage marshals a reply, error or (with reply serial 0) signal
+func dbusTestMessage(msgType byte, replySerial uint32, errorName, errorMessage string) []byte {
+	body := &dbusEncoder{}
+	if errorMessage != "" {
+		body.string(errorMessage)
+	}
+
+	msg := &dbusEncoder{}
+	msg.buf = append(msg.buf, 'l', msgType, 0, 1)
+	msg.uint32(uint32(len(body.buf)))
+	msg.uint32(1000 + replySerial)
+	msg.uint32(0)
+	if replySerial != 0 {
+		msg.align(8)
+		msg.buf = append(msg.buf, dbusFieldReplySerial)
+		msg.signature("u")
+		msg.uint32(replySerial)
+	} else {
+		msg.field(dbusFieldPath, "o", "/org/freedesktop/DBus")
+		msg.field(dbusFieldMember, "s", "NameAcquired")
+	}
+	if errorName != "" {
+		msg.field(dbusFieldErrorName, "s", errorName)
+	}
+	if errorMessage != "" {
+		msg.field(dbusFieldSignature, "g", "s")
+	}
+	binary.LittleEndian.PutUint32(msg.buf[12:], uint32(len(msg.buf)-16))
+	msg.align(8)
+	return append(msg.buf, body.buf...)
+}
+
+// startFakeBus starts a fake session bus and points DBUS_SESSION_BUS_ADDRESS at it
+func startFakeBus(t *testing.T, answer func(serial uint32) []byte) *fakeBus {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "bus")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path="+strings.ReplaceAll(socket, "/", "%2f")+",guid=0123")
+
+	bus := &fakeBus{calls: make(chan []byte, 4), answer: answer}
+	go bus.serve(t, listener)
+	return bus
+}
+
+func TestNotifyDBus(t *testing.T) {
+	bus := startFakeBus(t, func(serial uint32) []byte {
+		return dbusTestMessage(dbusMethodReturn, serial, "", "")
+	})
+
+	if err := notifyDBus(context.Background(), "Title", "<msg>", true); err != nil {
+		t.Fatalf("notifyDBus: %v", err)
+	}
+
+	hello := <-bus.calls
+	if !bytes.Contains(hello, []byte("Hello\x00")) {
+		t.Errorf("first call isn't Hello:\n%s", hex.Dump(hello))
+	}
+	notify := <-bus.calls
+	want := dbusMethodCallMessage(2, dbusNotificationsName, dbusNotificationsPath, dbusNotificationsName, "Notify", "susssasa{sv}i", notifyBody("Title", "<msg>", true))
+	if !bytes.Equal(notify, want) {
+		t.Errorf("Notify call =\n%s\nwant\n%s", hex.Dump(notify), hex.Dump(want))
+	}
+}
+
+func TestNotifyDBusError(t *testing.T) {
+	startFakeBus(t, func(serial uint32) []byte {
+		if serial == 1 {
+			return dbusTestMessage(dbusMethodReturn, serial, "", "")
+		}
+		return dbusTestMessage(dbusError, serial, "org.freedesktop.DBus.Error.ServiceUnknown", "The name is not activatable")
+	})
+
+	err := notifyDBus(context.Background(), "Title", "message", false)
+	if err == nil || err.Error() != "org.freedesktop.DBus.Error.ServiceUnknown: The name is not activatable" {
+		t.Errorf("notifyDBus = %v, want the service's error", err)
+	}
+}
+
+func TestReadDBusMessageTruncated(t *testing.T) {
+	msg := dbusTestMessage(dbusError, 7, "org.example.Error", "boom")
+	// Claim a longer header than the message has
+	binary.LittleEndian.PutUint32(msg[12:], uint32(len(msg)))
+	if _, err := readDBusMessage(bufio.NewReader(bytes.NewReader(msg))); err == nil {
+		t.Errorf("readDBusMessage accepted a truncated message")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package notification
+
+import (
+	"context"
+	"fmt"
+)
+
+// notifyDBus is only supported on Linux
+func notifyDBus(ctx context.Context, title, message string, silent bool) error {
+	return fmt.Errorf("D-Bus notifications are only supported on Linux")
+}
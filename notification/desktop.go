@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Desktop shows notifications as desktop popups
+type Desktop struct{}
+
+// Notify shows a popup notification
+func (Desktop) Notify(ctx context.Context, n Notification) error {
+	switch runtime.GOOS {
+	case "darwin": // macOS
+		return showMacNotification(ctx, n.Title, n.Message, n.Silent)
+	case "linux":
+		return showLinuxNotification(ctx, n.Title, n.Message, n.Silent)
+	case "windows":
+		return showWindowsNotification(ctx, n.Title, n.Message)
+	default:
+		// Fallback: just print a message
+		fmt.Printf("📢 %s: %s\n", n.Title, n.Message)
+		return nil
+	}
+}
+
+// showMacNotification shows notification on macOS
+func showMacNotification(ctx context.Context, title, message string, silent bool) error {
+	argv := macCommand(title, message, silent)
+	return exec.CommandContext(ctx, argv[0], argv[1:]...).Run()
+}
+
+// showLinuxNotification shows notification on Linux
+func showLinuxNotification(ctx context.Context, title, message string, silent bool) error {
+	// Talk to the notification service directly, which needs no other tools
+	dbusErr := notifyDBus(ctx, title, message, silent)
+	if dbusErr == nil {
+		return nil
+	}
+
+	// Try different notification systems
+	notifiers := []string{"notify-send", "zenity", "kdialog"}
+
+	for _, notifier := range notifiers {
+		if _, err := exec.LookPath(notifier); err == nil {
+			argv := linuxCommand(notifier, title, message, silent)
+			return exec.CommandContext(ctx, argv[0], argv[1:]...).Run()
+		}
+	}
+
+	// Fallback: just print a message
+	fmt.Printf("📢 %s: %s\n", title, message)
+	return nil
+}
+
+// showWindowsNotification shows notification on Windows
+func showWindowsNotification(ctx context.Context, title, message string) error {
+	argv, env := windowsCommand(title, message)
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.Run()
+}
+
+// macCommand returns the osascript command showing a notification. The title
+// and message are arguments of the script's run handler, so they're never
+// parsed as AppleScript.
+func macCommand(title, message string, silent bool) []string {
+	display := "display notification (item 2 of argv) with title (item 1 of argv)"
+	if !silent {
+		display += ` sound name "default"`
+	}
+	return []string{
+		"osascript",
+		"-e", "on run argv",
+		"-e", display,
+		"-e", "end run",
+		title, message,
+	}
+}
+
+// windowsNotificationScript shows a balloon notification with the title and
+// message from the environment, so they're never parsed as PowerShell
+const windowsNotificationScript = `
+Add-Type -AssemblyName System.Windows.Forms
+$notification = New-Object System.Windows.Forms.NotifyIcon
+$notification.Icon = [System.Drawing.SystemIcons]::Information
+$notification.Visible = $true
+$notification.ShowBalloonTip(5000, $env:LAZYTRACK_TITLE, $env:LAZYTRACK_MESSAGE, [System.Windows.Forms.ToolTipIcon]::Info)
+Start-Sleep -Seconds 6
+$notification.Dispose()
+`
+
+// windowsCommand returns the PowerShell command showing a notification, and
+// the environment variables passing the title and message to it
+func windowsCommand(title, message string) ([]string, []string) {
+	argv := []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", windowsNotificationScript}
+	env := []string{"LAZYTRACK_TITLE=" + title, "LAZYTRACK_MESSAGE=" + message}
+	return argv, env
+}
+
+// linuxCommand returns the command showing a notification with notify-send,
+// zenity or kdialog. Text that starts with a dash is never taken as an option,
+// and the message is escaped where it would be read as markup. Only
+// notify-send can leave out the sound.
+func linuxCommand(notifier, title, message string, silent bool) []string {
+	switch notifier {
+	case "notify-send":
+		if silent {
+			return []string{"notify-send", "--hint=boolean:suppress-sound:true", "--", title, escapeMarkup(message)}
+		}
+		return []string{"notify-send", "--", title, escapeMarkup(message)}
+	case "zenity":
+		return []string{"zenity", "--info", "--no-markup", "--title=" + title, "--text=" + message}
+	case "kdialog":
+		return []string{"kdialog", "--title", title, "--msgbox", message}
+	}
+	return nil
+}
+
+// escapeMarkup escapes the characters of the markup notification bodies may
+// contain (see the Desktop Notifications Specification)
+func escapeMarkup(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
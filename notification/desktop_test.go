@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// hostileNames are habit names and messages that would break out of a script
+// or be read as options if they were pasted into a command
+var hostileNames = []string{
+	`water`,
+	`"quoted" habit`,
+	`it's`,
+	"back`tick`",
+	`$(rm -rf ~)`,
+	`${HOME}`,
+	`-rf`,
+	`--help`,
+	`a & b <c> &amp;`,
+	"two\nlines",
+	`end"; do shell script "touch /tmp/pwned`,
+	`'; Remove-Item -Recurse C:\; '`,
+	`back\slash`,
+}
+
+func TestMacCommand(t *testing.T) {
+	for _, silent := range []bool{false, true} {
+		for _, name := range hostileNames {
+			title, message := "LazyTrack "+name, "Time for "+name+"!"
+			argv := macCommand(title, message, silent)
+
+			// The script is fixed, and the text follows as its arguments
+			script := []string{"osascript", "-e", "on run argv", "-e", argv[4], "-e", "end run"}
+			if !slices.Equal(argv[:7], script) {
+				t.Errorf("macCommand(%q) = %q, want the script %q first", name, argv, script)
+			}
+			if !strings.HasPrefix(argv[4], "display notification (item 2 of argv) with title (item 1 of argv)") {
+				t.Errorf("macCommand(%q) script = %q", name, argv[4])
+			}
+			if strings.Contains(argv[4], `sound name`) == silent {
+				t.Errorf("macCommand(%q, silent %v) script = %q", name, silent, argv[4])
+			}
+			if len(argv) != 9 || argv[7] != title || argv[8] != message {
+				t.Errorf("macCommand(%q) arguments = %q, want %q and %q", name, argv[7:], title, message)
+			}
+		}
+	}
+}
+
+func TestWindowsCommand(t *testing.T) {
+	for _, name := range hostileNames {
+		title, message := "LazyTrack "+name, "Time for "+name+"!"
+		argv, env := windowsCommand(title, message)
+
+		want := []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", windowsNotificationScript}
+		if !slices.Equal(argv, want) {
+			t.Errorf("windowsCommand(%q) = %q, want the fixed script", name, argv)
+		}
+		wantEnv := []string{"LAZYTRACK_TITLE=" + title, "LAZYTRACK_MESSAGE=" + message}
+		if !slices.Equal(env, wantEnv) {
+			t.Errorf("windowsCommand(%q) env = %q, want %q", name, env, wantEnv)
+		}
+	}
+	if strings.Contains(windowsNotificationScript, "LazyTrack") {
+		t.Errorf("the script should only read the text from the environment")
+	}
+}
+
+func TestLinuxCommand(t *testing.T) {
+	for _, name := range hostileNames {
+		title, message := name, "Time for "+name+"!"
+
+		tests := []struct {
+			notifier string
+			silent   bool
+			want     []string
+		}{
+			{"notify-send", false, []string{"notify-send", "--", title, escapeMarkup(message)}},
+			{"notify-send", true, []string{"notify-send", "--hint=boolean:suppress-sound:true", "--", title, escapeMarkup(message)}},
+			{"zenity", false, []string{"zenity", "--info", "--no-markup", "--title=" + title, "--text=" + message}},
+			{"kdialog", false, []string{"kdialog", "--title", title, "--msgbox", message}},
+		}
+		for _, test := range tests {
+			if got := linuxCommand(test.notifier, title, message, test.silent); !slices.Equal(got, test.want) {
+				t.Errorf("linuxCommand(%s, %q) = %q, want %q", test.notifier, name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestEscapeMarkup(t *testing.T) {
+	tests := map[string]string{
+		"plain":            "plain",
+		"a & b":            "a &amp; b",
+		"<b>bold</b>":      "&lt;b&gt;bold&lt;/b&gt;",
+		"&amp; stays text": "&amp;amp; stays text",
+		"\"quotes\" 'ok'":  "\"quotes\" 'ok'",
+		"two\nlines":       "two\nlines",
+	}
+	for input, want := range tests {
+		if got := escapeMarkup(input); got != want {
+			t.Errorf("escapeMarkup(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
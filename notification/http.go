@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Webhook posts notifications as JSON to a URL
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+}
+
+// Notify posts the notification, e.g.
+// {"type":"late","title":"...","message":"...","time":"2026-10-18T20:00:00+02:00"}
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	for key, value := range w.Headers {
+		headers[key] = value
+	}
+	return post(ctx, w.URL, headers, body)
+}
+
+// Ntfy pushes notifications to an ntfy topic, e.g. https://ntfy.sh/my-topic
+type Ntfy struct {
+	URL      string
+	Token    string // access token, for protected topics
+	Priority int    // 1 (min) to 5 (max), 0 for the server's default
+}
+
+// Notify publishes the notification to the topic
+func (p *Ntfy) Notify(ctx context.Context, n Notification) error {
+	headers := map[string]string{
+		"Content-Type": "text/plain; charset=utf-8",
+		// Headers must be ASCII, so the title is encoded if it's not
+		"Title": mime.QEncoding.Encode("utf-8", n.Title),
+		"Tags":  n.Type,
+	}
+	if p.Priority > 0 {
+		headers["Priority"] = strconv.Itoa(p.Priority)
+	}
+	if p.Token != "" {
+		headers["Authorization"] = "Bearer " + p.Token
+	}
+	return post(ctx, p.URL, headers, []byte(n.Message))
+}
+
+// Gotify pushes notifications to a Gotify server
+type Gotify struct {
+	URL      string // server URL, e.g. https://gotify.example.com
+	Token    string // application token
+	Priority int
+}
+
+// Notify creates a message on the server
+func (g *Gotify) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(map[string]any{
+		"title":    n.Title,
+		"message":  n.Message,
+		"priority": g.Priority,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	headers := map[string]string{"Content-Type": "application/json", "X-Gotify-Key": g.Token}
+	return post(ctx, strings.TrimSuffix(g.URL, "/")+"/message", headers, body)
+}
+
+// post sends a POST request and checks that it succeeded
+func post(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if message := strings.TrimSpace(string(detail)); message != "" {
+			return fmt.Errorf("server returned %s: %s", resp.Status, message)
+		}
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return nil
+}
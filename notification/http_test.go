@@ -0,0 +1,192 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// request is what a test server received
+type request struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+// newTestServer starts a server that records requests and replies with a status
+func newTestServer(t *testing.T, status int, reply string) (*httptest.Server, <-chan request) {
+	t.Helper()
+	requests := make(chan request, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{method: r.Method, path: r.URL.Path, header: r.Header, body: string(body)}
+		w.WriteHeader(status)
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// testNotification has a title that isn't ASCII and one with a line break
+var testNotification = Notification{
+	Type:    TypeLate,
+	Title:   "Late – “reminder”\r\nX-Injected: yes",
+	Message: "You still have pending goals: code\nand water",
+	Habit:   "code",
+	Time:    time.Date(2026, 10, 18, 20, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+}
+
+func TestWebhook(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusNoContent, "")
+	webhook := &Webhook{URL: server.URL + "/hook", Headers: map[string]string{"Authorization": "Bearer secret"}}
+	if err := webhook.Notify(context.Background(), testNotification); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	req := <-requests
+	if req.method != http.MethodPost || req.path != "/hook" {
+		t.Errorf("request = %s %s, want POST /hook", req.method, req.path)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if req.header.Get("X-Injected") != "" {
+		t.Errorf("title injected a header")
+	}
+	var got Notification
+	if err := json.Unmarshal([]byte(req.body), &got); err != nil {
+		t.Fatalf("invalid body %s: %v", req.body, err)
+	}
+	if !got.Time.Equal(testNotification.Time) {
+		t.Errorf("time = %v, want %v", got.Time, testNotification.Time)
+	}
+	got.Time = testNotification.Time
+	if got != testNotification {
+		t.Errorf("body = %+v, want %+v", got, testNotification)
+	}
+	if !strings.Contains(req.body, `"time":"2026-10-18T20:00:00+02:00"`) {
+		t.Errorf("body %s doesn't have the time in RFC 3339", req.body)
+	}
+}
+
+func TestNtfy(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusOK, `{"id":"x"}`)
+	ntfy := &Ntfy{URL: server.URL + "/my-topic", Token: "tk_secret", Priority: 4}
+	if err := ntfy.Notify(context.Background(), testNotification); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	req := <-requests
+	if req.method != http.MethodPost || req.path != "/my-topic" {
+		t.Errorf("request = %s %s, want POST /my-topic", req.method, req.path)
+	}
+	title := req.header.Get("Title")
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(title); err != nil || decoded != testNotification.Title {
+		t.Errorf("Title = %q, decoded %q (%v), want %q", title, decoded, err, testNotification.Title)
+	}
+	if req.header.Get("X-Injected") != "" {
+		t.Errorf("title injected a header")
+	}
+	want := map[string]string{
+		"Content-Type":  "text/plain; charset=utf-8",
+		"Tags":          TypeLate,
+		"Priority":      "4",
+		"Authorization": "Bearer tk_secret",
+	}
+	for key, value := range want {
+		if got := req.header.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if req.body != testNotification.Message {
+		t.Errorf("body = %q, want %q", req.body, testNotification.Message)
+	}
+
+	// Without a token or priority, the headers are left out
+	if err := (&Ntfy{URL: server.URL + "/my-topic"}).Notify(context.Background(), testNotification); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	req = <-requests
+	for _, key := range []string{"Authorization", "Priority"} {
+		if got := req.header.Get(key); got != "" {
+			t.Errorf("%s = %q, want none", key, got)
+		}
+	}
+}
+
+func TestGotify(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusOK, `{"id":1}`)
+	gotify := &Gotify{URL: server.URL + "/", Token: "app-token", Priority: 8}
+	if err := gotify.Notify(context.Background(), testNotification); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	req := <-requests
+	if req.method != http.MethodPost || req.path != "/message" {
+		t.Errorf("request = %s %s, want POST /message", req.method, req.path)
+	}
+	if got := req.header.Get("X-Gotify-Key"); got != "app-token" {
+		t.Errorf("X-Gotify-Key = %q", got)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	var body struct {
+		Title    string `json:"title"`
+		Message  string `json:"message"`
+		Priority int    `json:"priority"`
+	}
+	if err := json.Unmarshal([]byte(req.body), &body); err != nil {
+		t.Fatalf("invalid body %s: %v", req.body, err)
+	}
+	if body.Title != testNotification.Title || body.Message != testNotification.Message || body.Priority != 8 {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestHTTPBackendErrors(t *testing.T) {
+	statuses := []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError, http.StatusMultipleChoices}
+	for _, status := range statuses {
+		server, _ := newTestServer(t, status, "  topic is forbidden\n")
+		backends := map[string]Notifier{
+			"webhook": &Webhook{URL: server.URL},
+			"ntfy":    &Ntfy{URL: server.URL},
+			"gotify":  &Gotify{URL: server.URL, Token: "x"},
+		}
+		for name, backend := range backends {
+			err := backend.Notify(context.Background(), testNotification)
+			if err == nil {
+				t.Errorf("%s: status %d isn't an error", name, status)
+				continue
+			}
+			want := fmt.Sprintf("server returned %d %s", status, http.StatusText(status))
+			if !strings.Contains(err.Error(), want) || !strings.HasSuffix(err.Error(), ": topic is forbidden") {
+				t.Errorf("%s: error %q, want %q with the reply", name, err, want)
+			}
+		}
+	}
+
+	// Without a reply, the status is enough
+	server, _ := newTestServer(t, http.StatusBadGateway, "")
+	err := (&Webhook{URL: server.URL}).Notify(context.Background(), testNotification)
+	if err == nil || err.Error() != "server returned 502 Bad Gateway" {
+		t.Errorf("error = %v, want server returned 502 Bad Gateway", err)
+	}
+
+	// Nothing listening
+	server.Close()
+	if err := (&Webhook{URL: server.URL}).Notify(context.Background(), testNotification); err == nil {
+		t.Errorf("a closed server isn't an error")
+	}
+}
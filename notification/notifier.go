@@ -0,0 +1,227 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Types of notifications, which can be routed to different backends
+const (
+	TypeReminder = "reminder" // a habit's scheduled reminder
+	TypeLate     = "late"     // the late reminder for pending goals
+	TypeTest     = "test"     // sent by lazytrack notify test
+)
+
+// defaultRoute is the route of types without their own
+const defaultRoute = "default"
+
+// httpClient is used by the HTTP backends
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// Notification is a message to send
+type Notification struct {
+	Type    string    `json:"type"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Habit   string    `json:"habit,omitempty"`
+	Time    time.Time `json:"time"`
+	Silent  bool      `json:"silent,omitempty"` // without sound
+}
+
+// Notifier is a backend that delivers notifications
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// BackendError is the failure of one backend to deliver a notification
+type BackendError struct {
+	Backend string
+	Err     error
+}
+
+func (e *BackendError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Backend, e.Err)
+}
+
+func (e *BackendError) Unwrap() error {
+	return e.Err
+}
+
+// Config configures the backends and which notification types go to which
+type Config struct {
+	Backends map[string]BackendConfig `json:"backends"`
+	Routes   map[string][]string      `json:"routes"` // type (or "default") -> backend names
+}
+
+// BackendConfig configures a backend. Which fields are used depends on the type.
+type BackendConfig struct {
+	Type     string            `json:"type"` // desktop, terminal, webhook, ntfy, gotify, smtp or command
+	URL      string            `json:"url,omitempty"`
+	Token    string            `json:"token,omitempty"`
+	Priority int               `json:"priority,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Host     string            `json:"host,omitempty"` // SMTP server, host:port
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	From     string            `json:"from,omitempty"`
+	To       []string          `json:"to,omitempty"`
+	Command  []string          `json:"command,omitempty"`
+	Bell     bool              `json:"bell,omitempty"`
+}
+
+// DefaultConfig sends every notification to the desktop
+func DefaultConfig() Config {
+	return Config{
+		Backends: map[string]BackendConfig{"desktop": {Type: "desktop"}},
+		Routes:   map[string][]string{defaultRoute: {"desktop"}},
+	}
+}
+
+// Dispatcher sends notifications to the backends routed for their type
+type Dispatcher struct {
+	backends map[string]Notifier
+	routes   map[string][]string
+}
+
+// New creates a dispatcher, checking the config
+func New(cfg Config) (*Dispatcher, error) {
+	if len(cfg.Backends) == 0 {
+		cfg = DefaultConfig()
+	}
+
+	d := &Dispatcher{backends: make(map[string]Notifier), routes: cfg.Routes}
+	for name, backend := range cfg.Backends {
+		notifier, err := newBackend(backend)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification backend %s: %w", name, err)
+		}
+		d.backends[name] = notifier
+	}
+	for route, names := range cfg.Routes {
+		for _, name := range names {
+			if _, ok := d.backends[name]; !ok {
+				return nil, fmt.Errorf("notification route %s uses unknown backend %s", route, name)
+			}
+		}
+	}
+	return d, nil
+}
+
+// newBackend creates the notifier of a backend config
+func newBackend(cfg BackendConfig) (Notifier, error) {
+	switch cfg.Type {
+	case "desktop":
+		return Desktop{}, nil
+	case "terminal":
+		return &Terminal{Bell: cfg.Bell}, nil
+	case "webhook":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("webhook needs a url")
+		}
+		return &Webhook{URL: cfg.URL, Headers: cfg.Headers}, nil
+	case "ntfy":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("ntfy needs a url, e.g. https://ntfy.sh/my-topic")
+		}
+		return &Ntfy{URL: cfg.URL, Token: cfg.Token, Priority: cfg.Priority}, nil
+	case "gotify":
+		if cfg.URL == "" || cfg.Token == "" {
+			return nil, fmt.Errorf("gotify needs a url and an application token")
+		}
+		return &Gotify{URL: cfg.URL, Token: cfg.Token, Priority: cfg.Priority}, nil
+	case "smtp":
+		if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("smtp needs a host, from and to")
+		}
+		return &Email{Host: cfg.Host, Username: cfg.Username, Password: cfg.Password, From: cfg.From, To: cfg.To}, nil
+	case "command":
+		if len(cfg.Command) == 0 {
+			return nil, fmt.Errorf("command needs a command, e.g. [\"~/bin/notify.sh\"]")
+		}
+		return &Command{Argv: cfg.Command}, nil
+	case "":
+		return nil, fmt.Errorf("missing type")
+	}
+	return nil, fmt.Errorf("unknown type %s (use desktop, terminal, webhook, ntfy, gotify, smtp or command)", cfg.Type)
+}
+
+// Route returns the names of the backends a notification type is sent to
+func (d *Dispatcher) Route(notificationType string) []string {
+	if names, ok := d.routes[notificationType]; ok {
+		return names
+	}
+	if names, ok := d.routes[defaultRoute]; ok {
+		return names
+	}
+	// Without routes, everything goes everywhere
+	return d.Backends()
+}
+
+// Backends returns the names of all backends, sorted
+func (d *Dispatcher) Backends() []string {
+	names := make([]string, 0, len(d.backends))
+	for name := range d.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Send sends a notification to the backends routed for its type at once. The
+// error joins a *BackendError for each backend that failed.
+func (d *Dispatcher) Send(ctx context.Context, n Notification) error {
+	return d.SendVia(ctx, n, d.Route(n.Type))
+}
+
+// SendVia sends a notification to the given backends at once
+func (d *Dispatcher) SendVia(ctx context.Context, n Notification, names []string) error {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		notifier, ok := d.backends[name]
+		if !ok {
+			errs[i] = &BackendError{Backend: name, Err: fmt.Errorf("unknown backend")}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, name string, notifier Notifier) {
+			defer wg.Done()
+			if err := notifier.Notify(ctx, n); err != nil {
+				errs[i] = &BackendError{Backend: name, Err: err}
+			}
+		}(i, name, notifier)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// BackendErrors returns the per-backend failures in an error from Send
+func BackendErrors(err error) []*BackendError {
+	var failures []*BackendError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			failures = append(failures, BackendErrors(e)...)
+		}
+		return failures
+	}
+	var backendErr *BackendError
+	if errors.As(err, &backendErr) {
+		failures = append(failures, backendErr)
+	}
+	return failures
+}
+
+// describe summarizes a notification in one line, for text backends
+func describe(n Notification) string {
+	return strings.TrimSpace(n.Title + ": " + n.Message)
+}
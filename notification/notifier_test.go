@@ -0,0 +1,200 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNotifier records notifications, failing with err if it's set
+type fakeNotifier struct {
+	mu    sync.Mutex
+	sent  []Notification
+	err   error
+	start chan<- struct{} // signalled when Notify starts, if set
+	wait  <-chan struct{} // Notify waits for it to be closed, if set
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n Notification) error {
+	if f.start != nil {
+		f.start <- struct{}{}
+	}
+	if f.wait != nil {
+		select {
+		case <-f.wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, n)
+	return f.err
+}
+
+func (f *fakeNotifier) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.sent)
+}
+
+// newTestDispatcher creates a dispatcher with fake backends
+func newTestDispatcher(routes map[string][]string, backends map[string]*fakeNotifier) *Dispatcher {
+	d := &Dispatcher{backends: make(map[string]Notifier), routes: routes}
+	for name, backend := range backends {
+		d.backends[name] = backend
+	}
+	return d
+}
+
+func TestSendViaFansOut(t *testing.T) {
+	// Each backend waits until all have started, so this only returns if
+	// they're called at once
+	start := make(chan struct{})
+	release := make(chan struct{})
+	backends := map[string]*fakeNotifier{}
+	for _, name := range []string{"a", "b", "c"} {
+		backends[name] = &fakeNotifier{start: start, wait: release}
+	}
+	d := newTestDispatcher(nil, backends)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		for range backends {
+			select {
+			case <-start:
+			case <-ctx.Done():
+				return
+			}
+		}
+		close(release)
+	}()
+
+	if err := d.SendVia(ctx, Notification{Type: TypeTest, Title: "t"}, []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("SendVia: %v", err)
+	}
+	for name, backend := range backends {
+		if backend.count() != 1 {
+			t.Errorf("%s got %d notifications, want 1", name, backend.count())
+		}
+		if backend.sent[0].Time.IsZero() {
+			t.Errorf("%s got a notification without a time", name)
+		}
+	}
+}
+
+func TestSendViaBackendErrors(t *testing.T) {
+	errDown := errors.New("server down")
+	errAuth := errors.New("unauthorized")
+	backends := map[string]*fakeNotifier{
+		"desktop": {},
+		"ntfy":    {err: errDown},
+		"smtp":    {err: errAuth},
+	}
+	d := newTestDispatcher(nil, backends)
+
+	err := d.SendVia(context.Background(), Notification{Type: TypeTest}, []string{"ntfy", "desktop", "smtp", "gone"})
+	if err == nil {
+		t.Fatal("SendVia succeeded, want the failures")
+	}
+	if backends["desktop"].count() != 1 {
+		t.Errorf("the working backend wasn't sent to despite the others failing")
+	}
+
+	failures := BackendErrors(err)
+	var names []string
+	for _, failure := range failures {
+		names = append(names, failure.Backend)
+	}
+	if want := []string{"ntfy", "smtp", "gone"}; !slices.Equal(names, want) {
+		t.Fatalf("failed backends = %v, want %v", names, want)
+	}
+	if !errors.Is(failures[0], errDown) || !errors.Is(failures[1], errAuth) {
+		t.Errorf("failures = %v, want the backends' errors", failures)
+	}
+	if !errors.Is(err, errDown) || !errors.Is(err, errAuth) {
+		t.Errorf("error %v doesn't wrap the backends' errors", err)
+	}
+	if got := failures[2].Error(); got != "gone: unknown backend" {
+		t.Errorf("unknown backend error = %q", got)
+	}
+	if got := failures[0].Error(); got != "ntfy: server down" {
+		t.Errorf("error = %q, want it prefixed with the backend", got)
+	}
+
+	// Nothing to send to isn't an error
+	if err := d.SendVia(context.Background(), Notification{}, nil); err != nil {
+		t.Errorf("SendVia without backends: %v", err)
+	}
+	if failures := BackendErrors(nil); failures != nil {
+		t.Errorf("BackendErrors(nil) = %v", failures)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	backends := map[string]*fakeNotifier{"desktop": {}, "phone": {}, "mail": {}}
+	tests := []struct {
+		name   string
+		routes map[string][]string
+		typ    string
+		want   []string
+	}{
+		{"own route", map[string][]string{TypeLate: {"phone", "mail"}, defaultRoute: {"desktop"}}, TypeLate, []string{"phone", "mail"}},
+		{"default route", map[string][]string{TypeLate: {"phone"}, defaultRoute: {"desktop"}}, TypeReminder, []string{"desktop"}},
+		{"empty route", map[string][]string{TypeLate: {}, defaultRoute: {"desktop"}}, TypeLate, []string{}},
+		{"no default", map[string][]string{TypeLate: {"phone"}}, TypeReminder, []string{"desktop", "mail", "phone"}},
+		{"no routes", nil, TypeTest, []string{"desktop", "mail", "phone"}},
+	}
+	for _, test := range tests {
+		d := newTestDispatcher(test.routes, backends)
+		if got := d.Route(test.typ); !slices.Equal(got, test.want) {
+			t.Errorf("%s: Route(%s) = %v, want %v", test.name, test.typ, got, test.want)
+		}
+	}
+
+	// Send uses the route of the notification's type
+	sent := map[string]*fakeNotifier{"desktop": {}, "phone": {}}
+	d := newTestDispatcher(map[string][]string{TypeLate: {"phone"}, defaultRoute: {"desktop"}}, sent)
+	d.Send(context.Background(), Notification{Type: TypeLate})
+	d.Send(context.Background(), Notification{Type: TypeReminder})
+	d.Send(context.Background(), Notification{Type: TypeReminder})
+	if sent["phone"].count() != 1 || sent["desktop"].count() != 2 {
+		t.Errorf("phone got %d and desktop %d notifications, want 1 and 2", sent["phone"].count(), sent["desktop"].count())
+	}
+}
+
+func TestNew(t *testing.T) {
+	d, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := d.Route(TypeReminder); !slices.Equal(got, []string{"desktop"}) {
+		t.Errorf("default route = %v, want desktop", got)
+	}
+
+	invalid := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{Backends: map[string]BackendConfig{"x": {}}}, "missing type"},
+		{Config{Backends: map[string]BackendConfig{"x": {Type: "pager"}}}, "unknown type pager"},
+		{Config{Backends: map[string]BackendConfig{"x": {Type: "webhook"}}}, "needs a url"},
+		{Config{Backends: map[string]BackendConfig{"x": {Type: "gotify", URL: "https://gotify.example.com"}}}, "application token"},
+		{Config{Backends: map[string]BackendConfig{"x": {Type: "smtp", Host: "mail:587"}}}, "needs a host, from and to"},
+		{Config{Backends: map[string]BackendConfig{"x": {Type: "command"}}}, "needs a command"},
+		{Config{
+			Backends: map[string]BackendConfig{"desktop": {Type: "desktop"}},
+			Routes:   map[string][]string{TypeLate: {"desktop", "phone"}},
+		}, "unknown backend phone"},
+	}
+	for _, test := range invalid {
+		if _, err := New(test.cfg); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("New(%+v) = %v, want an error with %q", test.cfg, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// smtpSession is what a fake SMTP server received
+type smtpSession struct {
+	commands []string
+	data     string
+}
+
+// newSMTPServer starts a fake SMTP server for one session, which rejects
+// commands starting with reject
+func newSMTPServer(t *testing.T, reject string) (string, <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var session smtpSession
+		defer func() { sessions <- session }()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP fake")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			session.commands = append(session.commands, command)
+			if reject != "" && strings.HasPrefix(command, reject) {
+				reply("550 5.7.1 rejected")
+				continue
+			}
+			switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+			case "EHLO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				reply("235 2.7.0 authenticated")
+			case "MAIL", "RCPT":
+				reply("250 2.1.0 ok")
+			case "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				session.data = data.String()
+				reply("250 2.0.0 queued")
+			case "QUIT":
+				reply("221 2.0.0 bye")
+				return
+			default:
+				reply("502 5.5.2 unknown command")
+			}
+		}
+	}()
+	return listener.Addr().String(), sessions
+}
+
+func TestEmail(t *testing.T) {
+	addr, sessions := newSMTPServer(t, "")
+	email := &Email{
+		Host:     addr,
+		Username: "jo",
+		Password: "secret",
+		From:     "lazytrack@example.com",
+		To:       []string{"jo@example.com", "sam@example.com"},
+	}
+	if err := email.Notify(context.Background(), testNotification); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	session := <-sessions
+
+	want := []string{
+		"AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00jo\x00secret")),
+		"MAIL FROM:<lazytrack@example.com>",
+		"RCPT TO:<jo@example.com>",
+		"RCPT TO:<sam@example.com>",
+		"DATA",
+		"QUIT",
+	}
+	if len(session.commands) != len(want)+1 || strings.Join(session.commands[1:], "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands = %q, want EHLO and %q", session.commands, want)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatalf("invalid message %q: %v", session.data, err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != testNotification.Title {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, testNotification.Title)
+	}
+	// The line break in the title mustn't start a header of its own
+	for key := range msg.Header {
+		switch key {
+		case "From", "To", "Subject", "Date", "Mime-Version", "Content-Type", "Content-Transfer-Encoding":
+		default:
+			t.Errorf("unexpected header %s: %q", key, msg.Header.Get(key))
+		}
+	}
+	if got := msg.Header.Get("To"); got != "jo@example.com, sam@example.com" {
+		t.Errorf("To = %q", got)
+	}
+	if got := msg.Header.Get("Date"); got != "Sun, 18 Oct 2026 20:00:00 +0200" {
+		t.Errorf("Date = %q", got)
+	}
+	body, _ := io.ReadAll(msg.Body)
+	if want := "You still have pending goals: code\r\nand water\r\n"; string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestEmailHeaderInjection(t *testing.T) {
+	titles := []string{
+		"Reminder\r\nBcc: victim@example.com",
+		"Reminder\nBcc: victim@example.com",
+		"Reminder\rBcc: victim@example.com",
+		"Reminder\r\n\r\nfake body",
+		"=?utf-8?q?Reminder?=\r\nBcc: victim@example.com",
+	}
+	email := &Email{From: "lazytrack@example.com", To: []string{"jo@example.com"}}
+	for _, title := range titles {
+		n := testNotification
+		n.Title = title
+		data := string(email.message(n))
+		header, _, _ := strings.Cut(data, "\r\n\r\n")
+		for _, line := range strings.Split(header, "\r\n") {
+			if strings.ContainsAny(line, "\r\n") {
+				t.Errorf("title %q: bare line break in header line %q", title, line)
+			}
+			if strings.HasPrefix(line, "Bcc:") {
+				t.Errorf("title %q: injected header %q", title, line)
+			}
+		}
+		msg, err := mail.ReadMessage(strings.NewReader(data))
+		if err != nil {
+			t.Errorf("title %q: invalid message: %v", title, err)
+			continue
+		}
+		if _, ok := msg.Header["Bcc"]; ok {
+			t.Errorf("title %q: injected a Bcc header", title)
+		}
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		if err != nil || subject != title {
+			t.Errorf("Subject = %q (%v), want %q", subject, err, title)
+		}
+	}
+}
+
+func TestEmailRejected(t *testing.T) {
+	addr, sessions := newSMTPServer(t, "RCPT")
+	email := &Email{Host: addr, From: "lazytrack@example.com", To: []string{"jo@example.com"}}
+	err := email.Notify(context.Background(), testNotification)
+	if err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("error = %v, want the rejection", err)
+	}
+	email.Host = "localhost" // no port
+	if err := email.Notify(context.Background(), testNotification); err == nil || !strings.Contains(err.Error(), "use host:port") {
+		t.Errorf("error = %v, want invalid host", err)
+	}
+	<-sessions
+}
@@ -0,0 +1,29 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Terminal prints notifications, optionally ringing the terminal bell for
+// notifications with sound
+type Terminal struct {
+	Bell bool
+	Out  io.Writer // os.Stdout if nil
+}
+
+// Notify prints the notification
+func (t *Terminal) Notify(ctx context.Context, n Notification) error {
+	out := t.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	bell := ""
+	if t.Bell && !n.Silent {
+		bell = "\a"
+	}
+	_, err := fmt.Fprintf(out, "%s📢 %s\n", bell, describe(n))
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/master-wayne7/lazytrack/types"
 )
@@ -34,14 +35,21 @@ func ParseDuration(input string) (types.ParsedDuration, error) {
 	}, nil
 }
 
+// bareNumberRegex matches durations given as a plain number of hours
+var bareNumberRegex = regexp.MustCompile(`^\d+(?:\.\d+)?$`)
+
 // parseTimeDuration handles time-based durations
 func parseTimeDuration(input string) (int, int, error) {
 	// Regex to match patterns like "2h", "30m", "1h30m", "1.5h"
-	timeRegex := regexp.MustCompile(`^(\d+(?:\.\d+)?)?h?(\d+)?m?$`)
+	timeRegex := regexp.MustCompile(`^(?:(\d+(?:\.\d+)?)h)?(?:(\d+)m)?$`)
 	matches := timeRegex.FindStringSubmatch(input)
 
-	if len(matches) == 0 {
-		return 0, 0, fmt.Errorf("invalid duration format: %s", input)
+	if len(matches) == 0 || (matches[1] == "" && matches[2] == "") {
+		// Bare numbers are treated as hours (e.g., "2")
+		if !bareNumberRegex.MatchString(input) {
+			return 0, 0, fmt.Errorf("invalid duration format: %s", input)
+		}
+		matches = []string{input, input, ""}
 	}
 
 	hours := 0
@@ -145,3 +153,93 @@ func GetTotalMinutes(duration types.ParsedDuration) int {
 func GetTotalHours(duration types.ParsedDuration) float64 {
 	return float64(duration.Hours) + float64(duration.Minutes)/60.0
 }
+
+// ParseRange parses date range strings like "this-week", "last-month", "30d"
+// or "2026-10-01..2026-10-07" relative to now. The returned end is exclusive.
+func ParseRange(input string, now time.Time) (time.Time, time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch input {
+	case "", "this-week", "week":
+		start := WeekStart(now)
+		return start, start.AddDate(0, 0, 7), nil
+	case "last-week":
+		start := WeekStart(now).AddDate(0, 0, -7)
+		return start, start.AddDate(0, 0, 7), nil
+	case "this-month", "month":
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0), nil
+	case "last-month":
+		start := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0), nil
+	case "this-year", "year":
+		start := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(1, 0, 0), nil
+	case "today", "day":
+		return today, today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, nil
+	}
+
+	// Handle rolling windows (e.g., "30d", "12w")
+	if matches := regexp.MustCompile(`^(\d+)([dw])$`).FindStringSubmatch(input); matches != nil {
+		n, _ := strconv.Atoi(matches[1])
+		if n <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid range: %s", input)
+		}
+		if matches[2] == "w" {
+			n *= 7
+		}
+		end := today.AddDate(0, 0, 1)
+		return end.AddDate(0, 0, -n), end, nil
+	}
+
+	// Handle explicit dates (e.g., "2026-10-01..2026-10-07" or "2026-10-01")
+	parts := strings.SplitN(input, "..", 2)
+	start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(parts[0]), now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range: %s", input)
+	}
+	end := start
+	if len(parts) == 2 {
+		end, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(parts[1]), now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid range end: %s", parts[1])
+		}
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("range end is before start: %s", input)
+	}
+
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// PreviousRange returns the range of equal length immediately before start and end.
+// Whole calendar months map to the previous calendar month.
+func PreviousRange(start, end time.Time) (time.Time, time.Time) {
+	if start.Day() == 1 && end.Equal(start.AddDate(0, 1, 0)) {
+		return start.AddDate(0, -1, 0), start
+	}
+	days := DaysInRange(start, end)
+	return start.AddDate(0, 0, -days), start
+}
+
+// DaysInRange returns the number of calendar days between start and end
+func DaysInRange(start, end time.Time) int {
+	days := 0
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		days++
+	}
+	return days
+}
+
+// WeekStart returns the start of the week (Monday) containing t
+func WeekStart(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 { // Sunday
+		weekday = 7
+	}
+	monday := t.AddDate(0, 0, -(weekday - 1))
+	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,85 @@
+package parser
+
+import "testing"
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		hours   int
+		minutes int
+		valid   bool
+	}{
+		// Minutes were once read as hours, so 30m became 30 hours
+		{"30m", 0, 30, true},
+		{"90m", 1, 30, true},
+		{"1h30m", 1, 30, true},
+		{"1.5h", 1, 30, true},
+		{"2h", 2, 0, true},
+		{" 45m ", 0, 45, true},
+		{"30s", 0, 0, false},
+		{"h", 0, 0, false},
+		{"30m1h", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseDuration(test.input)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %+v, want an error", test.input, parsed)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", test.input, err)
+			continue
+		}
+		if parsed.Hours != test.hours || parsed.Minutes != test.minutes {
+			t.Errorf("ParseDuration(%q) = %dh%dm, want %dh%dm", test.input, parsed.Hours, parsed.Minutes, test.hours, test.minutes)
+		}
+	}
+
+	// 30x is a count, which imports reject as a duration
+	for input, want := range map[string]bool{"30x": true, "3 times": true, "30m": false, "30": false} {
+		if got := IsCountBased(input); got != want {
+			t.Errorf("IsCountBased(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestParseDurationBareNumbers(t *testing.T) {
+	tests := []struct {
+		input   string
+		hours   int
+		minutes int
+		valid   bool
+	}{
+		{"2", 2, 0, true},
+		{"1.5", 1, 30, true},
+		{"0.25", 0, 15, true},
+		{"-2", 0, 0, false},
+		{"1e9", 0, 0, false},
+		{"NaN", 0, 0, false},
+		{"Inf", 0, 0, false},
+		{"+Inf", 0, 0, false},
+		{"0x10", 0, 0, false},
+		{"1.", 0, 0, false},
+		{".5", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseDuration(test.input)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %+v, want an error", test.input, parsed)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", test.input, err)
+			continue
+		}
+		if parsed.Hours != test.hours || parsed.Minutes != test.minutes {
+			t.Errorf("ParseDuration(%q) = %dh%dm, want %dh%dm", test.input, parsed.Hours, parsed.Minutes, test.hours, test.minutes)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package reminder
+
+import (
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/schedule"
+)
+
+// StateName is the name of the state file reminders are kept in
+const StateName = "reminders"
+
+// dayFormat is how days are stored in the state
+const dayFormat = "2006-01-02"
+
+// State is what the daemon remembers about reminders between runs: the last
+// slot each reminder was delivered for, and the habits snoozed or dismissed
+type State struct {
+	Sent      map[string]time.Time `json:"sent"`      // reminder key -> last slot delivered
+	Snoozed   map[string]time.Time `json:"snoozed"`   // habit -> snoozed until
+	Dismissed map[string]string    `json:"dismissed"` // habit -> day dismissed (YYYY-MM-DD)
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{
+		Sent:      make(map[string]time.Time),
+		Snoozed:   make(map[string]time.Time),
+		Dismissed: make(map[string]string),
+	}
+}
+
+// Key returns the key of a reminder, e.g. "late" or "habit:water"
+func Key(kind, habit string) string {
+	if habit == "" {
+		return kind
+	}
+	return kind + ":" + strings.ToLower(habit)
+}
+
+// WasSent checks if a reminder was delivered for a slot (or a later one)
+func (s *State) WasSent(key string, slot time.Time) bool {
+	last, ok := s.Sent[key]
+	return ok && !slot.After(last)
+}
+
+// MarkSent records that a reminder was delivered for a slot
+func (s *State) MarkSent(key string, slot time.Time) {
+	if !s.WasSent(key, slot) {
+		s.Sent[key] = slot
+	}
+}
+
+// Snooze silences a habit's reminders until a time
+func (s *State) Snooze(habit string, until time.Time) {
+	s.Snoozed[strings.ToLower(habit)] = until
+	delete(s.Dismissed, strings.ToLower(habit))
+}
+
+// Dismiss silences a habit's reminders for the rest of a day
+func (s *State) Dismiss(habit string, day time.Time) {
+	s.Dismissed[strings.ToLower(habit)] = day.Format(dayFormat)
+	delete(s.Snoozed, strings.ToLower(habit))
+}
+
+// Clear removes a habit's snooze and dismissal
+func (s *State) Clear(habit string) {
+	delete(s.Snoozed, strings.ToLower(habit))
+	delete(s.Dismissed, strings.ToLower(habit))
+}
+
+// SnoozedUntil returns when a habit's snooze ends. A snooze is kept after it
+// ends until it's cleared, once its reminder is delivered.
+func (s *State) SnoozedUntil(habit string) (time.Time, bool) {
+	until, ok := s.Snoozed[strings.ToLower(habit)]
+	return until, ok
+}
+
+// IsDismissed checks if a habit was dismissed for the day of t
+func (s *State) IsDismissed(habit string, t time.Time) bool {
+	return s.Dismissed[strings.ToLower(habit)] == t.Format(dayFormat)
+}
+
+// IsMuted checks if a habit is snoozed or dismissed at t
+func (s *State) IsMuted(habit string, t time.Time) bool {
+	until, snoozed := s.SnoozedUntil(habit)
+	return s.IsDismissed(habit, t) || (snoozed && until.After(t))
+}
+
+// Prune drops dismissals of past days, so the state doesn't grow forever
+func (s *State) Prune(now time.Time) {
+	today := now.Format(dayFormat)
+	for habit, day := range s.Dismissed {
+		if day != today {
+			delete(s.Dismissed, habit)
+		}
+	}
+}
+
+// AfterQuiet returns t, or if t falls into one of the windows (quiet hours or
+// do-not-disturb), the time the windows end
+func AfterQuiet(t time.Time, windows []*schedule.Window) time.Time {
+	// Windows may overlap or follow each other, so move on until t is free
+	// (giving up after a while if they cover the whole week)
+	for moved, i := true, 0; moved && i < 100; i++ {
+		moved = false
+		for _, window := range windows {
+			if end := window.End(t); !end.IsZero() {
+				t, moved = end, true
+			}
+		}
+	}
+	return t
+}
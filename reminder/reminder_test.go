@@ -0,0 +1,148 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/schedule"
+)
+
+// at returns a time on Saturday, March 7th 2026 (or days later)
+func at(days, hour, minute int) time.Time {
+	return time.Date(2026, 3, 7+days, hour, minute, 0, 0, time.UTC)
+}
+
+func TestSent(t *testing.T) {
+	s := NewState()
+	key := Key("habit", "Water")
+	if key != "habit:water" {
+		t.Errorf("Key = %q, want habit:water", key)
+	}
+	if s.WasSent(key, at(0, 9, 0)) {
+		t.Error("WasSent before anything was sent")
+	}
+
+	s.MarkSent(key, at(0, 11, 0))
+	for _, test := range []struct {
+		slot time.Time
+		want bool
+	}{
+		{at(0, 9, 0), true}, // an earlier slot is covered by a later one
+		{at(0, 11, 0), true},
+		{at(0, 13, 0), false},
+	} {
+		if got := s.WasSent(key, test.slot); got != test.want {
+			t.Errorf("WasSent(%s) = %v, want %v", test.slot.Format("15:04"), got, test.want)
+		}
+	}
+	if s.WasSent(Key("late", ""), at(0, 9, 0)) {
+		t.Error("WasSent for another reminder")
+	}
+
+	// Marking an earlier slot, e.g. a missed one delivered late, keeps the later one
+	s.MarkSent(key, at(0, 9, 0))
+	if !s.Sent[key].Equal(at(0, 11, 0)) {
+		t.Errorf("last slot = %s, want 11:00", s.Sent[key].Format("15:04"))
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	s := NewState()
+	s.Snooze("Water", at(0, 10, 30))
+
+	if until, ok := s.SnoozedUntil("water"); !ok || !until.Equal(at(0, 10, 30)) {
+		t.Errorf("SnoozedUntil = %s, %v, want 10:30, true", until, ok)
+	}
+	if !s.IsMuted("water", at(0, 10, 29)) {
+		t.Error("not muted before the snooze ends")
+	}
+	if s.IsMuted("water", at(0, 10, 30)) {
+		t.Error("muted when the snooze ends")
+	}
+	// The snooze is kept once it ends, until its reminder clears it
+	if _, ok := s.SnoozedUntil("water"); !ok {
+		t.Error("snooze forgotten after it ended")
+	}
+	s.Clear("water")
+	if _, ok := s.SnoozedUntil("water"); ok {
+		t.Error("snooze kept after Clear")
+	}
+}
+
+func TestDismiss(t *testing.T) {
+	s := NewState()
+	s.Snooze("water", at(0, 10, 0))
+	s.Dismiss("Water", at(0, 8, 0))
+
+	if _, ok := s.SnoozedUntil("water"); ok {
+		t.Error("snooze kept after Dismiss")
+	}
+	if !s.IsMuted("water", at(0, 23, 59)) {
+		t.Error("not muted for the rest of the day")
+	}
+	if s.IsMuted("water", at(1, 0, 0)) {
+		t.Error("still muted the next day")
+	}
+
+	// Snoozing takes back a dismissal
+	s.Snooze("water", at(0, 12, 0))
+	if s.IsDismissed("water", at(0, 11, 0)) {
+		t.Error("dismissal kept after Snooze")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := NewState()
+	s.Dismiss("water", at(0, 8, 0))
+	s.Dismiss("code", at(1, 8, 0))
+	s.Snooze("walk", at(0, 9, 0))
+	s.MarkSent("late", at(0, 20, 0))
+
+	s.Prune(at(1, 9, 0))
+	if _, ok := s.Dismissed["water"]; ok {
+		t.Error("yesterday's dismissal kept")
+	}
+	if !s.IsDismissed("code", at(1, 9, 0)) {
+		t.Error("today's dismissal dropped")
+	}
+	// Snoozes and sent slots are left alone
+	if _, ok := s.SnoozedUntil("walk"); !ok {
+		t.Error("snooze dropped")
+	}
+	if !s.WasSent("late", at(0, 20, 0)) {
+		t.Error("sent slot dropped")
+	}
+}
+
+func TestAfterQuiet(t *testing.T) {
+	windows := func(spec string) []*schedule.Window {
+		w, err := schedule.ParseWindows(spec)
+		if err != nil {
+			t.Fatalf("ParseWindows(%q): %v", spec, err)
+		}
+		return w
+	}
+
+	tests := []struct {
+		name    string
+		windows string
+		t       time.Time
+		want    time.Time
+	}{
+		{"outside", "22:00-07:00", at(0, 12, 0), at(0, 12, 0)},
+		{"inside", "22:00-07:00", at(0, 23, 0), at(1, 7, 0)},
+		{"at the end", "22:00-07:00", at(1, 7, 0), at(1, 7, 0)},
+		{"overlapping", "12:00-14:00; 13:00-15:00", at(0, 12, 30), at(0, 15, 0)},
+		{"overlapping in any order", "13:00-15:00; 12:00-14:00", at(0, 12, 30), at(0, 15, 0)},
+		{"nested", "12:00-18:00; 13:00-14:00", at(0, 13, 30), at(0, 18, 0)},
+		{"back to back", "22:00-07:00; 07:00-08:00; 08:00-09:00 sun", at(0, 23, 0), at(1, 9, 0)},
+		{"chained on another day", "22:00-07:00; 07:00-08:00 mon", at(0, 23, 0), at(1, 7, 0)},
+		{"whole day", "00:00-24:00 sat; 22:00-07:00", at(0, 10, 0), at(1, 7, 0)},
+	}
+
+	for _, test := range tests {
+		if got := AfterQuiet(test.t, windows(test.windows)); !got.Equal(test.want) {
+			t.Errorf("%s: AfterQuiet(%s) = %s, want %s", test.name, test.t.Format("Mon 15:04"), got.Format("Mon 15:04"), test.want.Format("Mon 15:04"))
+		}
+	}
+}
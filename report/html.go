@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/chart"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// htmlTemplate is the self-contained HTML report layout
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Format("Jan 2, 2006") },
+	"datetime": func(t time.Time) string { return t.Format("Mon, Jan 2 15:04") },
+	"amount":   formatSummaryAmount,
+	"percent":  func(value float64) string { return fmt.Sprintf("%.0f%%", value) },
+	"hasGoal":  hasGoal,
+	"barChart": func(habit HabitReport) template.HTML {
+		return template.HTML(chart.BarSVG(habit.DailyTotals, chartOptions(habit)))
+	},
+	"heatmap": func(habit HabitReport) template.HTML {
+		return template.HTML(chart.HeatmapSVG(habit.DailyTotals, chartOptions(habit)))
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>LazyTrack Report {{date .StartDate}} - {{date .LastDay}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292e; max-width: 900px; margin: 2em auto; padding: 0 1em; }
+h1 { border-bottom: 2px solid #40c463; padding-bottom: .3em; }
+.meta { color: #666; }
+.totals { display: flex; gap: 1em; flex-wrap: wrap; }
+.card { border: 1px solid #e1e4e8; border-radius: 6px; padding: 1em; margin: 1em 0; }
+.stat { background: #f6f8fa; border-radius: 6px; padding: .5em 1em; }
+.stat b { display: block; font-size: 1.4em; }
+.charts { overflow-x: auto; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .4em .6em; border-bottom: 1px solid #e1e4e8; }
+.message { font-size: 1.1em; color: #0366d6; }
+</style>
+</head>
+<body>
+<h1>📊 LazyTrack Report</h1>
+<p class="meta">{{date .StartDate}} - {{date .LastDay}} · generated {{datetime .GeneratedAt}}</p>
+<p class="message">{{.Message}}</p>
+<div class="totals">
+<div class="stat"><b>{{printf "%.1f" .Summary.TotalTime}}h</b>total time</div>
+<div class="stat"><b>{{len .Habits}}</b>habits</div>
+<div class="stat"><b>{{len .Notes}}</b>notes</div>
+</div>
+{{range .Habits}}
+<div class="card">
+<h2>{{.Habit.Emoji}} {{.Habit.Name}}</h2>
+<div class="totals">
+<div class="stat"><b>{{amount .Summary .Habit.GoalType}}</b>total</div>
+{{if hasGoal .Habit}}<div class="stat"><b>{{percent .Summary.GoalProgress}}</b>of goal</div>{{end}}
+<div class="stat"><b>{{percent .Summary.Consistency}}</b>consistency</div>
+<div class="stat"><b>{{.CurrentStreak}}</b>day streak</div>
+<div class="stat"><b>{{.LongestStreak}}</b>longest streak</div>
+</div>
+<div class="charts">{{barChart .}}</div>
+<div class="charts">{{heatmap .}}</div>
+</div>
+{{end}}
+{{if .Notes}}
+<h2>📝 Notes</h2>
+<table>
+<tr><th>When</th><th>Habit</th><th>Amount</th><th>Note</th></tr>
+{{range .Notes}}<tr><td>{{datetime .LoggedAt}}</td><td>{{.HabitName}}</td><td>{{if .Duration}}{{.Duration}}{{else}}{{.Count}}x{{end}}</td><td>{{.Notes}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+// LastDay returns the last day included in the report
+func (r Report) LastDay() time.Time {
+	return r.EndDate.AddDate(0, 0, -1)
+}
+
+// WriteHTML writes the report as a self-contained HTML page with inline CSS and SVG charts
+func WriteHTML(w io.Writer, report Report) error {
+	return htmlTemplate.Execute(w, report)
+}
+
+// chartOptions returns the chart options for a habit
+func chartOptions(habit HabitReport) chart.Options {
+	opts := chart.Options{Goal: summary.PeriodGoal(habit.Habit, 1), Unit: "h"}
+	if habit.Habit.GoalType == "count" {
+		opts.Unit = "x"
+	}
+	return opts
+}
+
+// formatSummaryAmount formats a habit summary's total in the habit's unit
+func formatSummaryAmount(habitSummary types.Summary, goalType string) string {
+	if goalType == "count" {
+		return summary.FormatAmount(goalType, float64(habitSummary.TotalCount))
+	}
+	return fmt.Sprintf("%.1fh", habitSummary.TotalTime)
+}
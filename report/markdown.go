@@ -0,0 +1,104 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// WriteMarkdown writes the report as a Markdown document with a habit table,
+// streaks and a chronological list of notes
+func WriteMarkdown(w io.Writer, report Report) error {
+	var result strings.Builder
+
+	// Header
+	result.WriteString("# 📊 LazyTrack Report\n\n")
+	result.WriteString(fmt.Sprintf("**%s – %s**\n\n",
+		report.StartDate.Format("Jan 2, 2006"), report.LastDay().Format("Jan 2, 2006")))
+	result.WriteString(fmt.Sprintf("> %s\n\n", report.Message))
+
+	// Habit table
+	result.WriteString("## Habits\n\n")
+	result.WriteString("| Habit | Total | Goal | Status | Consistency | Streak | Longest streak |\n")
+	result.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, habit := range report.Habits {
+		result.WriteString(fmt.Sprintf("| %s %s | %s | %s | %s | %.0f%% | %d | %d |\n",
+			habit.Habit.Emoji, escapeTableCell(habit.Habit.Name),
+			formatSummaryAmount(habit.Summary, habit.Habit.GoalType),
+			formatGoal(habit.Habit), formatGoalStatus(habit),
+			habit.Summary.Consistency, habit.CurrentStreak, habit.LongestStreak))
+	}
+	result.WriteString(fmt.Sprintf("\n**Total time:** %.1f hours\n", report.Summary.TotalTime))
+
+	// Notes grouped by day
+	if len(report.Notes) > 0 {
+		result.WriteString("\n## Notes\n")
+		currentDay := ""
+		for _, log := range report.Notes {
+			day := log.LoggedAt.Format("Monday, Jan 2")
+			if day != currentDay {
+				result.WriteString(fmt.Sprintf("\n### %s\n\n", day))
+				currentDay = day
+			}
+			result.WriteString(fmt.Sprintf("- %s %s **%s** (%s) — %s\n",
+				log.LoggedAt.Format("15:04"), report.habitEmoji(log.HabitName), log.HabitName,
+				formatLogAmount(log), strings.Join(strings.Fields(log.Notes), " ")))
+		}
+	}
+
+	_, err := io.WriteString(w, result.String())
+	return err
+}
+
+// habitEmoji returns the emoji of a habit in the report
+func (r Report) habitEmoji(name string) string {
+	for _, habit := range r.Habits {
+		if habit.Habit.Name == name {
+			return habit.Habit.Emoji
+		}
+	}
+	return "📝"
+}
+
+// formatGoal formats a habit's daily or weekly goal
+func formatGoal(habit types.Habit) string {
+	switch {
+	case habit.DailyGoal > 0:
+		return summary.FormatAmount(habit.GoalType, float64(habit.DailyGoal)) + "/day"
+	case hasGoal(habit):
+		return summary.FormatAmount(habit.GoalType, float64(habit.WeeklyGoal)) + "/week"
+	}
+	return "—"
+}
+
+// hasGoal checks if a habit has a daily or weekly goal
+func hasGoal(habit types.Habit) bool {
+	return summary.PeriodGoal(habit, 1) > 0
+}
+
+// formatGoalStatus formats whether a habit reached its goal for the period
+func formatGoalStatus(habit HabitReport) string {
+	if !hasGoal(habit.Habit) {
+		return "—"
+	}
+	if habit.Summary.GoalProgress >= 100 {
+		return fmt.Sprintf("✅ %.0f%%", habit.Summary.GoalProgress)
+	}
+	return fmt.Sprintf("⏳ %.0f%%", habit.Summary.GoalProgress)
+}
+
+// formatLogAmount formats a log's duration or count
+func formatLogAmount(log types.Log) string {
+	if log.Duration != "" {
+		return log.Duration
+	}
+	return fmt.Sprintf("%dx", log.Count)
+}
+
+// escapeTableCell escapes characters that would break a Markdown table
+func escapeTableCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
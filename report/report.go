@@ -0,0 +1,74 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Report holds everything shown in an exported report
+type Report struct {
+	StartDate   time.Time
+	EndDate     time.Time
+	GeneratedAt time.Time
+	Summary     types.WeeklySummary
+	Habits      []HabitReport
+	Notes       []types.Log // logs with notes, oldest first
+	Message     string
+}
+
+// HabitReport holds a single habit's section of a report
+type HabitReport struct {
+	Habit         types.Habit
+	Summary       types.Summary
+	DailyTotals   []types.DailyTotal
+	CurrentStreak int
+	LongestStreak int
+}
+
+// Build builds a report for the period between startDate and endDate using the
+// same calculations as the summary command
+func Build(habits []types.Habit, logsByHabit map[string][]types.Log, startDate, endDate time.Time) Report {
+	habits = append([]types.Habit(nil), habits...)
+	sort.Slice(habits, func(i, j int) bool {
+		return habits[i].Name < habits[j].Name
+	})
+
+	periodSummary := summary.CalculateSummary(habits, logsByHabit, startDate, endDate)
+
+	report := Report{
+		StartDate:   startDate,
+		EndDate:     endDate,
+		GeneratedAt: time.Now(),
+		Summary:     periodSummary,
+		Message:     summary.GetMotivationalMessage(periodSummary, nil),
+	}
+
+	for i, habit := range habits {
+		logs := logsByHabit[habit.Name]
+		dailyTotals := summary.CalculateDailyTotals(habit, logs, startDate, endDate)
+		current, longest := summary.CalculateStreaks(dailyTotals)
+
+		report.Habits = append(report.Habits, HabitReport{
+			Habit:         habit,
+			Summary:       periodSummary.Habits[i],
+			DailyTotals:   dailyTotals,
+			CurrentStreak: current,
+			LongestStreak: longest,
+		})
+
+		for _, log := range logs {
+			if log.Notes != "" {
+				report.Notes = append(report.Notes, log)
+			}
+		}
+	}
+
+	sort.SliceStable(report.Notes, func(i, j int) bool {
+		return report.Notes[i].LoggedAt.Before(report.Notes[j].LoggedAt)
+	})
+
+	return report
+}
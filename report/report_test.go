@@ -0,0 +1,81 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// testReport builds a week's report of a daily, a weekly and a goalless habit
+func testReport() Report {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	habits := []types.Habit{
+		{ID: 1, Name: "code", Emoji: "💻", GoalType: "duration", DailyGoal: 2},
+		{ID: 2, Name: "gym", Emoji: "🏋️", GoalType: "count", WeeklyGoal: 3},
+		{ID: 3, Name: "notes", Emoji: "📝", GoalType: "count"},
+	}
+	logs := map[string][]types.Log{
+		"code":  {{HabitName: "code", Duration: "7h", LoggedAt: start.Add(10 * time.Hour)}},
+		"gym":   {{HabitName: "gym", Count: 1, LoggedAt: start.Add(18 * time.Hour)}, {HabitName: "gym", Count: 1, LoggedAt: start.Add(42 * time.Hour)}},
+		"notes": {{HabitName: "notes", Count: 1, LoggedAt: start.Add(20 * time.Hour)}},
+	}
+	return Build(habits, logs, start, start.AddDate(0, 0, 7))
+}
+
+func TestWriteMarkdownGoals(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteMarkdown(&out, testReport()); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+
+	rows := make(map[string]string)
+	for _, line := range strings.Split(out.String(), "\n") {
+		for _, name := range []string{"code", "gym", "notes"} {
+			if strings.HasPrefix(line, "| ") && strings.Contains(line, " "+name+" |") {
+				rows[name] = line
+			}
+		}
+	}
+	tests := []struct {
+		habit, goal, status string
+	}{
+		{"code", "| 2h/day |", "| ⏳ 50% |"},
+		{"gym", "| 3x/week |", "| ⏳ 67% |"},
+		{"notes", "| — | — |", ""},
+	}
+	for _, test := range tests {
+		row := rows[test.habit]
+		if !strings.Contains(row, test.goal) || !strings.Contains(row, test.status) {
+			t.Errorf("%s row %q, want goal %q and status %q", test.habit, row, test.goal, test.status)
+		}
+	}
+}
+
+func TestWriteHTMLGoals(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteHTML(&out, testReport()); err != nil {
+		t.Fatalf("WriteHTML: %v", err)
+	}
+
+	// Each card of a habit with a goal shows its progress
+	cards := strings.Split(out.String(), `<div class="card">`)[1:]
+	want := map[string]string{"code": "50%", "gym": "67%", "notes": ""}
+	for _, card := range cards {
+		for habit, progress := range want {
+			if !strings.Contains(card, " "+habit+"</h2>") {
+				continue
+			}
+			shown := strings.Contains(card, "of goal")
+			if shown != (progress != "") || (shown && !strings.Contains(card, "<b>"+progress+"</b>of goal")) {
+				t.Errorf("%s card shows goal progress %v, want %q", habit, shown, progress)
+			}
+			delete(want, habit)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("no cards for %v", want)
+	}
+}
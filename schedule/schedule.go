@@ -0,0 +1,231 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minutesPerDay is the number of minutes in a day; 24:00 is a valid window end
+const minutesPerDay = 24 * 60
+
+// dayNames maps day names to weekdays
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Schedule is a set of times of day on some days of the week, e.g. a reminder
+type Schedule struct {
+	spec  string
+	times []int         // minutes after midnight, for fixed times
+	every time.Duration // interval between times, within the window
+	from  int           // window start, minutes after midnight
+	to    int           // window end (inclusive), minutes after midnight
+	days  [7]bool
+}
+
+// Parse parses a schedule. Fixed times and intervals may be followed by days:
+//
+//	18:30                   every day at 18:30
+//	18:30 mon-fri           weekdays at 18:30
+//	09:00,13:00 sat,sun     weekends at 09:00 and 13:00
+//	every 2h 09:00-21:00    every 2 hours from 09:00 to 21:00
+//	every 45m weekdays      every 45 minutes, all day on weekdays
+//
+// Days are names (mon), ranges (mon-fri, fri-sun) or daily, weekdays and
+// weekends, separated by commas.
+func Parse(spec string) (*Schedule, error) {
+	s := &Schedule{spec: strings.Join(strings.Fields(strings.ToLower(spec)), " "), to: minutesPerDay}
+	fields := strings.Fields(s.spec)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty schedule")
+	}
+
+	hasDays, hasWindow := false, false
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "every":
+			if i+1 == len(fields) || s.every != 0 {
+				return nil, fmt.Errorf("invalid schedule %q: 'every' needs one interval, e.g. every 2h", spec)
+			}
+			i++
+			every, err := time.ParseDuration(fields[i])
+			if err != nil || every < time.Minute || every%time.Minute != 0 {
+				return nil, fmt.Errorf("invalid schedule %q: invalid interval %s (use e.g. 30m or 2h)", spec, fields[i])
+			}
+			s.every = every
+		case strings.Contains(field, "-") && strings.Contains(field, ":"):
+			if hasWindow {
+				return nil, fmt.Errorf("invalid schedule %q: more than one time window", spec)
+			}
+			start, end, _ := strings.Cut(field, "-")
+			from, err := parseClock(start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+			}
+			to, err := parseClock(end)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+			}
+			if to <= from {
+				return nil, fmt.Errorf("invalid schedule %q: window %s ends before it starts", spec, field)
+			}
+			s.from, s.to, hasWindow = from, to, true
+		case strings.Contains(field, ":"):
+			for _, value := range strings.Split(field, ",") {
+				minutes, err := parseClock(value)
+				if err != nil || minutes == minutesPerDay {
+					return nil, fmt.Errorf("invalid schedule %q: invalid time %s", spec, value)
+				}
+				s.times = append(s.times, minutes)
+			}
+		default:
+			if hasDays {
+				return nil, fmt.Errorf("invalid schedule %q: days must be given once, e.g. mon-fri or sat,sun", spec)
+			}
+			if err := s.parseDays(field); err != nil {
+				return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+			}
+			hasDays = true
+		}
+	}
+
+	switch {
+	case s.every == 0 && hasWindow:
+		return nil, fmt.Errorf("invalid schedule %q: a time window needs an interval, e.g. every 2h %02d:%02d-%02d:%02d", spec, s.from/60, s.from%60, s.to/60, s.to%60)
+	case s.every == 0 && len(s.times) == 0:
+		return nil, fmt.Errorf("invalid schedule %q: needs a time like 18:30 or an interval like every 2h", spec)
+	case s.every != 0 && len(s.times) > 0:
+		return nil, fmt.Errorf("invalid schedule %q: use either times or 'every', not both", spec)
+	}
+	sort.Ints(s.times)
+	if !hasDays {
+		s.days = [7]bool{true, true, true, true, true, true, true}
+	}
+	return s, nil
+}
+
+// MustParse parses a schedule and panics if it's invalid, for built-in schedules
+func MustParse(spec string) *Schedule {
+	s, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the schedule in its normalized form
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time of the schedule after t
+func (s *Schedule) Next(t time.Time) time.Time {
+	for offset := 0; offset <= 7; offset++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+offset, 12, 0, 0, 0, t.Location())
+		for _, at := range s.timesOn(day) {
+			if at.After(t) {
+				return at
+			}
+		}
+	}
+	return time.Time{} // unreachable, since every schedule has at least one day
+}
+
+// Previous returns the last time of the schedule at or before t
+func (s *Schedule) Previous(t time.Time) time.Time {
+	for offset := 0; offset <= 7; offset++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()-offset, 12, 0, 0, 0, t.Location())
+		times := s.timesOn(day)
+		for i := len(times) - 1; i >= 0; i-- {
+			if !times[i].After(t) {
+				return times[i]
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// timesOn returns the times of the schedule on a day, in order. The day is
+// given by its noon, since some time zones skip midnight.
+func (s *Schedule) timesOn(day time.Time) []time.Time {
+	if !s.days[day.Weekday()] {
+		return nil
+	}
+
+	var minutes []int
+	if s.every != 0 {
+		step := int(s.every / time.Minute)
+		for m := s.from; m <= s.to && m < minutesPerDay; m += step {
+			minutes = append(minutes, m)
+		}
+	} else {
+		minutes = s.times
+	}
+
+	times := make([]time.Time, 0, len(minutes))
+	for _, m := range minutes {
+		at := time.Date(day.Year(), day.Month(), day.Day(), m/60, m%60, 0, 0, day.Location())
+		// A time skipped when clocks go forward comes out before it; move it
+		// past the gap instead, so it's not early
+		clock := at.Hour()*60 + at.Minute()
+		if at.Day() != day.Day() {
+			clock -= minutesPerDay // the gap is at midnight
+		}
+		if clock < m {
+			at = at.Add(time.Duration(m-clock) * time.Minute)
+		}
+		times = append(times, at)
+	}
+	return times
+}
+
+// parseDays parses a comma separated list of days and day ranges
+func (s *Schedule) parseDays(field string) error {
+	for _, part := range strings.Split(field, ",") {
+		switch part {
+		case "daily":
+			s.days = [7]bool{true, true, true, true, true, true, true}
+			continue
+		case "weekdays":
+			part = "mon-fri"
+		case "weekends":
+			part = "sat-sun"
+		}
+
+		first, last, isRange := strings.Cut(part, "-")
+		start, ok := dayNames[first]
+		if !ok {
+			return fmt.Errorf("unknown day %s (use mon, tue, ... or mon-fri)", first)
+		}
+		end := start
+		if isRange {
+			if end, ok = dayNames[last]; !ok {
+				return fmt.Errorf("unknown day %s (use mon, tue, ... or mon-fri)", last)
+			}
+		}
+		// Ranges may wrap around the week, e.g. fri-mon
+		for day := start; ; day = (day + 1) % 7 {
+			s.days[day] = true
+			if day == end {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock parses a time of day like 18:30 into minutes after midnight
+func parseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if !ok || errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || h*60+m > minutesPerDay {
+		return 0, fmt.Errorf("invalid time %s (use HH:MM)", value)
+	}
+	return h*60 + m, nil
+}
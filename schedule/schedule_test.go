@@ -0,0 +1,238 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // for the DST tests on any system
+)
+
+// at returns a time in loc; 2025-06-02 is a Monday
+func at(loc *time.Location, year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, loc)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string // normalized form, or "" if the spec is invalid
+	}{
+		{"18:30", "18:30"},
+		{"  18:30   MON-FRI ", "18:30 mon-fri"},
+		{"09:00,13:00 sat,sun", "09:00,13:00 sat,sun"},
+		{"every 2h 09:00-21:00", "every 2h 09:00-21:00"},
+		{"every 45m weekdays", "every 45m weekdays"},
+		{"every 2h 20:00-24:00", "every 2h 20:00-24:00"},
+		{"00:00 fri-mon", "00:00 fri-mon"},
+		{"24:00", ""},
+		{"18:30,24:00", ""},
+		{"25:00", ""},
+		{"18:60", ""},
+		{"every", ""},
+		{"every 30s", ""},
+		{"every 90s", ""},
+		{"every 2h every 3h", ""},
+		{"09:00-21:00", ""},
+		{"every 2h 21:00-09:00", ""},
+		{"every 1h 09:00-10:00 10:00-11:00", ""},
+		{"every 1h 09:00", ""},
+		{"18:30 mon tue", ""},
+		{"18:30 funday", ""},
+		{"mon-fri", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		s, err := Parse(test.spec)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("Parse(%q) = %q, want an error", test.spec, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.spec, err)
+			continue
+		}
+		if s.String() != test.want {
+			t.Errorf("Parse(%q) = %q, want %q", test.spec, s, test.want)
+		}
+	}
+}
+
+func TestDays(t *testing.T) {
+	tests := []struct {
+		spec string
+		days [7]bool // Sunday first
+	}{
+		{"09:00", [7]bool{true, true, true, true, true, true, true}},
+		{"09:00 mon-fri", [7]bool{false, true, true, true, true, true, false}},
+		{"09:00 weekdays", [7]bool{false, true, true, true, true, true, false}},
+		{"09:00 weekends", [7]bool{true, false, false, false, false, false, true}},
+		{"09:00 fri-mon", [7]bool{true, true, false, false, false, true, true}},
+		{"09:00 sat-sun", [7]bool{true, false, false, false, false, false, true}},
+		{"09:00 sun-sat", [7]bool{true, true, true, true, true, true, true}},
+		{"09:00 wed", [7]bool{false, false, false, true, false, false, false}},
+		{"09:00 mon,wed-thu,sat", [7]bool{false, true, false, true, true, false, true}},
+	}
+
+	for _, test := range tests {
+		s := MustParse(test.spec)
+		if s.days != test.days {
+			t.Errorf("Parse(%q) days = %v, want %v", test.spec, s.days, test.days)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		spec string
+		from time.Time
+		want []time.Time // the next times in turn
+	}{
+		{"18:30", at(loc, 2025, 6, 2, 12, 0), []time.Time{
+			at(loc, 2025, 6, 2, 18, 30), at(loc, 2025, 6, 3, 18, 30),
+		}},
+		// A time isn't after itself
+		{"18:30", at(loc, 2025, 6, 2, 18, 30), []time.Time{at(loc, 2025, 6, 3, 18, 30)}},
+		{"09:00,13:00 sat,sun", at(loc, 2025, 6, 2, 12, 0), []time.Time{
+			at(loc, 2025, 6, 7, 9, 0), at(loc, 2025, 6, 7, 13, 0), at(loc, 2025, 6, 8, 9, 0), at(loc, 2025, 6, 8, 13, 0),
+			at(loc, 2025, 6, 14, 9, 0),
+		}},
+		// Across the end of the week, and of the year
+		{"09:00 mon", at(loc, 2025, 6, 2, 9, 0), []time.Time{at(loc, 2025, 6, 9, 9, 0), at(loc, 2025, 6, 16, 9, 0)}},
+		{"08:00 fri-mon", at(loc, 2025, 12, 30, 10, 0), []time.Time{
+			at(loc, 2026, 1, 2, 8, 0), at(loc, 2026, 1, 3, 8, 0), at(loc, 2026, 1, 4, 8, 0), at(loc, 2026, 1, 5, 8, 0),
+			at(loc, 2026, 1, 9, 8, 0),
+		}},
+		{"every 2h 09:00-13:00", at(loc, 2025, 6, 2, 8, 0), []time.Time{
+			at(loc, 2025, 6, 2, 9, 0), at(loc, 2025, 6, 2, 11, 0), at(loc, 2025, 6, 2, 13, 0), at(loc, 2025, 6, 3, 9, 0),
+		}},
+		{"every 5h 09:00-21:00", at(loc, 2025, 6, 2, 15, 0), []time.Time{
+			at(loc, 2025, 6, 2, 19, 0), at(loc, 2025, 6, 3, 9, 0),
+		}},
+		// Without a window, intervals run all day from midnight
+		{"every 8h", at(loc, 2025, 6, 2, 17, 0), []time.Time{
+			at(loc, 2025, 6, 3, 0, 0), at(loc, 2025, 6, 3, 8, 0), at(loc, 2025, 6, 3, 16, 0), at(loc, 2025, 6, 4, 0, 0),
+		}},
+		{"every 45m weekdays", at(loc, 2025, 6, 6, 22, 40), []time.Time{
+			at(loc, 2025, 6, 6, 23, 15), at(loc, 2025, 6, 9, 0, 0), at(loc, 2025, 6, 9, 0, 45),
+		}},
+		// A window may end at 24:00, which isn't a time of its own
+		{"every 2h 20:00-24:00", at(loc, 2025, 6, 2, 21, 0), []time.Time{
+			at(loc, 2025, 6, 2, 22, 0), at(loc, 2025, 6, 3, 20, 0),
+		}},
+	}
+
+	for _, test := range tests {
+		s := MustParse(test.spec)
+		from := test.from
+		for i, want := range test.want {
+			got := s.Next(from)
+			if !got.Equal(want) {
+				t.Errorf("%q: time %d after %v = %v, want %v", test.spec, i+1, test.from, got, want)
+				break
+			}
+			from = got
+		}
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		spec string
+		t    time.Time
+		want time.Time
+	}{
+		// At or before t
+		{"18:30", at(loc, 2025, 6, 2, 18, 30), at(loc, 2025, 6, 2, 18, 30)},
+		{"18:30", at(loc, 2025, 6, 2, 18, 29), at(loc, 2025, 6, 1, 18, 30)},
+		// A week back, across the start of the week and month
+		{"09:00 mon", at(loc, 2025, 6, 2, 8, 59), at(loc, 2025, 5, 26, 9, 0)},
+		{"09:00 sat,sun", at(loc, 2025, 6, 2, 12, 0), at(loc, 2025, 6, 1, 9, 0)},
+		{"08:00 fri-mon", at(loc, 2026, 1, 2, 7, 0), at(loc, 2025, 12, 29, 8, 0)},
+		{"every 2h 09:00-21:00", at(loc, 2025, 6, 2, 12, 0), at(loc, 2025, 6, 2, 11, 0)},
+		{"every 2h 09:00-21:00", at(loc, 2025, 6, 2, 8, 0), at(loc, 2025, 6, 1, 21, 0)},
+		{"every 2h 20:00-24:00", at(loc, 2025, 6, 3, 1, 0), at(loc, 2025, 6, 2, 22, 0)},
+		{"every 45m", at(loc, 2025, 6, 2, 0, 44), at(loc, 2025, 6, 2, 0, 0)},
+	}
+
+	for _, test := range tests {
+		if got := MustParse(test.spec).Previous(test.t); !got.Equal(test.want) {
+			t.Errorf("%q: previous of %v = %v, want %v", test.spec, test.t, got, test.want)
+		}
+	}
+}
+
+func TestDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+
+	// Clocks go from 02:00 to 03:00 on 2025-03-09, and from 02:00 back to
+	// 01:00 on 2025-11-02
+	tests := []struct {
+		spec string
+		from time.Time
+		want []time.Time
+	}{
+		// Times are wall clock times, so they keep their hour across the change
+		{"09:00", at(loc, 2025, 3, 8, 12, 0), []time.Time{at(loc, 2025, 3, 9, 9, 0), at(loc, 2025, 3, 10, 9, 0)}},
+		{"09:00", at(loc, 2025, 11, 1, 12, 0), []time.Time{at(loc, 2025, 11, 2, 9, 0), at(loc, 2025, 11, 3, 9, 0)}},
+		// A time that's skipped is moved past the gap, once
+		{"02:30", at(loc, 2025, 3, 8, 12, 0), []time.Time{
+			time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC), // 03:30 EDT
+			at(loc, 2025, 3, 10, 2, 30),
+		}},
+		// A time that happens twice is used the first time
+		{"01:30", at(loc, 2025, 11, 1, 12, 0), []time.Time{
+			time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+			at(loc, 2025, 11, 3, 1, 30),
+		}},
+		{"every 1h 00:00-04:00", at(loc, 2025, 3, 9, 0, 30), []time.Time{
+			at(loc, 2025, 3, 9, 1, 0),
+			time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC), // 03:00 EDT, for 02:00 and 03:00
+			time.Date(2025, 3, 9, 8, 0, 0, 0, time.UTC), // 04:00 EDT
+			at(loc, 2025, 3, 10, 0, 0),
+		}},
+	}
+
+	// Clocks go from 00:00 to 01:00 on 2025-09-07 in Santiago
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	tests = append(tests, []struct {
+		spec string
+		from time.Time
+		want []time.Time
+	}{
+		{"00:30 sun", at(santiago, 2025, 9, 6, 12, 0), []time.Time{
+			time.Date(2025, 9, 7, 4, 30, 0, 0, time.UTC), // 01:30 -03
+			at(santiago, 2025, 9, 14, 0, 30),
+		}},
+		{"every 12h sun", at(santiago, 2025, 9, 6, 12, 0), []time.Time{
+			time.Date(2025, 9, 7, 4, 0, 0, 0, time.UTC), // 01:00 -03
+			at(santiago, 2025, 9, 7, 12, 0),
+			at(santiago, 2025, 9, 14, 0, 0),
+		}},
+	}...)
+
+	for _, test := range tests {
+		s := MustParse(test.spec)
+		from := test.from
+		for i, want := range test.want {
+			got := s.Next(from)
+			if !got.Equal(want) {
+				t.Errorf("%q: time %d after %v = %v, want %v", test.spec, i+1, test.from, got, want)
+				break
+			}
+			if previous := s.Previous(got); !previous.Equal(got) {
+				t.Errorf("%q: previous of %v = %v, want itself", test.spec, got, previous)
+			}
+			from = got
+		}
+	}
+}
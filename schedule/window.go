@@ -0,0 +1,103 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a range of time on some days of the week, e.g. quiet hours. A
+// window may wrap around midnight (22:00-07:00) and belongs to the day it
+// starts on.
+type Window struct {
+	spec string
+	from int // minutes after midnight
+	to   int // minutes after midnight, before from if the window wraps
+	days [7]bool
+}
+
+// ParseWindow parses a window like "22:00-07:00" or "12:00-13:00 weekdays"
+func ParseWindow(spec string) (*Window, error) {
+	w := &Window{spec: strings.Join(strings.Fields(strings.ToLower(spec)), " ")}
+	fields := strings.Fields(w.spec)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid window %q: use a range with optional days, e.g. 22:00-07:00 or 12:00-13:00 weekdays", spec)
+	}
+
+	start, end, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q: %s is not a range like 22:00-07:00", spec, fields[0])
+	}
+	from, err := parseClock(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+	}
+	to, err := parseClock(end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+	}
+	if from == minutesPerDay {
+		return nil, fmt.Errorf("invalid window %q: 24:00 can only end a window", spec)
+	}
+	// 00:00-24:00 is a whole day, but 08:00-08:00 is empty
+	if from == to {
+		return nil, fmt.Errorf("invalid window %q: the window is empty", spec)
+	}
+	w.from, w.to = from, to
+
+	if len(fields) == 2 {
+		// Reuse the day parsing of schedules
+		s := &Schedule{}
+		if err := s.parseDays(fields[1]); err != nil {
+			return nil, fmt.Errorf("invalid window %q: %w", spec, err)
+		}
+		w.days = s.days
+	} else {
+		w.days = [7]bool{true, true, true, true, true, true, true}
+	}
+	return w, nil
+}
+
+// ParseWindows parses windows separated by semicolons, e.g.
+// "12:00-13:00 weekdays; 18:00-19:00 sat"
+func ParseWindows(spec string) ([]*Window, error) {
+	var windows []*Window
+	for _, part := range strings.Split(spec, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		w, err := ParseWindow(part)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// String returns the window in its normalized form
+func (w *Window) String() string {
+	return w.spec
+}
+
+// End returns the end of the window containing t, or the zero time if t is
+// outside the window
+func (w *Window) End(t time.Time) time.Time {
+	// A window containing t started today or, if it wraps, yesterday
+	for offset := 0; offset <= 1; offset++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+		if !w.days[day.Weekday()] {
+			continue
+		}
+		to := w.to
+		if to < w.from {
+			to += minutesPerDay
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, w.from, 0, 0, day.Location())
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, to, 0, 0, day.Location())
+		if !t.Before(start) && t.Before(end) {
+			return end
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "22:00-07:00", want: "22:00-07:00"},
+		{spec: " 12:00-13:00   Weekdays ", want: "12:00-13:00 weekdays"},
+		{spec: "00:00-24:00", want: "00:00-24:00"},
+		{spec: "18:00-24:00 sat", want: "18:00-24:00 sat"},
+		{spec: "08:00-08:00", wantErr: true},
+		{spec: "00:00-00:00", wantErr: true},
+		{spec: "24:00-07:00", wantErr: true},
+		{spec: "24:00-24:00", wantErr: true},
+		{spec: "22:00", wantErr: true},
+		{spec: "22:00-25:00", wantErr: true},
+		{spec: "22:00-07:00 someday", wantErr: true},
+		{spec: "22:00-07:00 mon extra", wantErr: true},
+		{spec: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		w, err := ParseWindow(test.spec)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("ParseWindow(%q) = %s, want an error", test.spec, w)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseWindow(%q): %v", test.spec, err)
+			continue
+		}
+		if w.String() != test.want {
+			t.Errorf("ParseWindow(%q) = %q, want %q", test.spec, w, test.want)
+		}
+	}
+}
+
+func TestWindowEnd(t *testing.T) {
+	// Saturday, March 7th 2026
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		spec string
+		t    time.Time
+		want time.Time // zero if t is outside the window
+	}{
+		{"22:00-07:00", at(7, 23, 0), at(8, 7, 0)},
+		{"22:00-07:00", at(8, 6, 59), at(8, 7, 0)},
+		{"22:00-07:00", at(8, 7, 0), time.Time{}},
+		{"22:00-07:00", at(7, 21, 59), time.Time{}},
+		{"00:00-24:00", at(7, 0, 0), at(8, 0, 0)},
+		{"00:00-24:00", at(7, 23, 59), at(8, 0, 0)},
+		{"00:00-24:00 sat", at(7, 12, 0), at(8, 0, 0)},
+		{"00:00-24:00 sat", at(8, 12, 0), time.Time{}},
+		// A window wrapping around midnight belongs to the day it starts on
+		{"22:00-07:00 fri", at(7, 6, 0), at(7, 7, 0)},
+		{"22:00-07:00 fri", at(7, 23, 0), time.Time{}},
+	}
+
+	for _, test := range tests {
+		w, err := ParseWindow(test.spec)
+		if err != nil {
+			t.Fatalf("ParseWindow(%q): %v", test.spec, err)
+		}
+		if got := w.End(test.t); !got.Equal(test.want) {
+			t.Errorf("%q.End(%s) = %s, want %s", test.spec, test.t.Format("Mon 15:04"), got, test.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// Names the daemon is installed under
+const (
+	UnitName = "lazytrack.service"
+	Label    = "com.github.master-wayne7.lazytrack"
+	TaskName = "LazyTrack"
+)
+
+// Config describes the daemon a service runs
+type Config struct {
+	Executable string   // absolute path of the lazytrack binary
+	Args       []string // arguments starting the daemon
+	Label      string   // launchd label and scheduled task name
+	User       string   // Windows user the task starts for at logon (DOMAIN\user)
+}
+
+// Service is the file installing the daemon as a service on a platform, and
+// the commands enabling and disabling it
+type Service struct {
+	Platform string // systemd, launchd or schtasks
+	Path     string
+	Content  string
+	encoding func(string) []byte // how the file is encoded, if not UTF-8
+	enable   [][]string
+	disable  [][]string
+}
+
+// New returns the service for an operating system (as in runtime.GOOS). Its
+// file goes to $XDG_CONFIG_HOME/systemd/user (~/.config/systemd/user) on Linux,
+// ~/Library/LaunchAgents on macOS and dataDir on Windows.
+func New(goos string, cfg Config, homeDir, dataDir string) (*Service, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		content, err := SystemdUnit(cfg)
+		if err != nil {
+			return nil, err
+		}
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			configDir = filepath.Join(homeDir, ".config")
+		}
+		return &Service{
+			Platform: "systemd",
+			Path:     filepath.Join(configDir, "systemd", "user", UnitName),
+			Content:  content,
+			enable: [][]string{
+				{"systemctl", "--user", "daemon-reload"},
+				{"systemctl", "--user", "enable", "--now", UnitName},
+			},
+			disable: [][]string{
+				{"systemctl", "--user", "disable", "--now", UnitName},
+			},
+		}, nil
+
+	case "darwin":
+		content, err := LaunchdPlist(cfg)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(homeDir, "Library", "LaunchAgents", cfg.Label+".plist")
+		domain := fmt.Sprintf("gui/%d", os.Getuid())
+		return &Service{
+			Platform: "launchd",
+			Path:     path,
+			Content:  content,
+			enable:   [][]string{{"launchctl", "bootstrap", domain, path}},
+			disable:  [][]string{{"launchctl", "bootout", domain, path}},
+		}, nil
+
+	case "windows":
+		content, err := ScheduledTask(cfg)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dataDir, "lazytrack-task.xml")
+		return &Service{
+			Platform: "schtasks",
+			Path:     path,
+			Content:  content,
+			encoding: encodeUTF16,
+			enable: [][]string{
+				{"schtasks", "/Create", "/TN", cfg.Label, "/XML", path, "/F"},
+				{"schtasks", "/Run", "/TN", cfg.Label},
+			},
+			disable: [][]string{
+				{"schtasks", "/End", "/TN", cfg.Label},
+				{"schtasks", "/Delete", "/TN", cfg.Label, "/F"},
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("installing the daemon as a service isn't supported on %s", goos)
+}
+
+// Installed checks if the service file exists
+func (s *Service) Installed() bool {
+	_, err := os.Stat(s.Path)
+	return err == nil
+}
+
+// Install writes the service file and enables the service, which starts the
+// daemon now and at every login
+func (s *Service) Install() error {
+	content := []byte(s.Content)
+	if s.encoding != nil {
+		content = s.encoding(s.Content)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create service directory: %w", err)
+	}
+	if err := os.WriteFile(s.Path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write service file: %w", err)
+	}
+	for _, command := range s.enable {
+		if err := run(command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Uninstall disables the service, which stops the daemon, and removes the
+// service file
+func (s *Service) Uninstall() error {
+	var errs []string
+	for _, command := range s.disable {
+		// Keep going, so a half installed service is removed too
+		if err := run(command); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, fmt.Sprintf("failed to remove service file: %v", err))
+	}
+	if s.Platform == "systemd" {
+		run([]string{"systemctl", "--user", "daemon-reload"})
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// run runs a command, returning its output in the error if it fails
+func run(command []string) error {
+	output, err := exec.Command(command[0], command[1:]...).CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			message = err.Error()
+		}
+		return fmt.Errorf("%s failed: %s", strings.Join(command, " "), message)
+	}
+	return nil
+}
+
+// encodeUTF16 encodes text as UTF-16 with a byte order mark, which is what
+// schtasks expects of task XML
+func encodeUTF16(text string) []byte {
+	var out bytes.Buffer
+	out.Write([]byte{0xFF, 0xFE})
+	for _, unit := range utf16.Encode([]rune(text)) {
+		out.WriteByte(byte(unit))
+		out.WriteByte(byte(unit >> 8))
+	}
+	return out.Bytes()
+}
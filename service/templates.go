@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// systemdTemplate is the systemd --user unit running the daemon
+const systemdTemplate = `# Generated by lazytrack daemon install; remove with lazytrack daemon uninstall
+[Unit]
+Description=LazyTrack habit reminders
+Documentation=https://github.com/master-wayne7/lazytrack
+
+[Service]
+Type=simple
+ExecStart={{systemdQuote .Executable}}{{range .Args}} {{systemdQuote .}}{{end}}
+Restart=on-failure
+RestartSec=10
+
+[Install]
+WantedBy=default.target
+`
+
+// launchdTemplate is the launchd agent running the daemon
+const launchdTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<!-- Generated by lazytrack daemon install; remove with lazytrack daemon uninstall -->
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{xml .Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{xml .Executable}}</string>
+{{- range .Args}}
+		<string>{{xml .}}</string>
+{{- end}}
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ProcessType</key>
+	<string>Interactive</string>
+</dict>
+</plist>
+`
+
+// taskTemplate is the Windows scheduled task starting the daemon at logon
+const taskTemplate = `<?xml version="1.0" encoding="UTF-16"?>
+<Task version="1.2" xmlns="http://schemas.microsoft.com/windows/2004/02/mit/task">
+  <RegistrationInfo>
+    <Description>LazyTrack habit reminders (generated by lazytrack daemon install)</Description>
+    <URI>\{{xml .Label}}</URI>
+  </RegistrationInfo>
+  <Triggers>
+    <LogonTrigger>
+      <Enabled>true</Enabled>
+{{- if .User}}
+      <UserId>{{xml .User}}</UserId>
+{{- end}}
+    </LogonTrigger>
+  </Triggers>
+  <Principals>
+    <Principal id="Author">
+      <LogonType>InteractiveToken</LogonType>
+      <RunLevel>LeastPrivilege</RunLevel>
+    </Principal>
+  </Principals>
+  <Settings>
+    <MultipleInstancesPolicy>IgnoreNew</MultipleInstancesPolicy>
+    <DisallowStartIfOnBatteries>false</DisallowStartIfOnBatteries>
+    <StopIfGoingOnBatteries>false</StopIfGoingOnBatteries>
+    <ExecutionTimeLimit>PT0S</ExecutionTimeLimit>
+    <Enabled>true</Enabled>
+  </Settings>
+  <Actions Context="Author">
+    <Exec>
+      <Command>{{xml .Executable}}</Command>
+      <Arguments>{{xml (windowsArgs .Args)}}</Arguments>
+    </Exec>
+  </Actions>
+</Task>
+`
+
+// templateFuncs escape values for the files they're written into
+var templateFuncs = template.FuncMap{
+	"systemdQuote": systemdQuote,
+	"xml":          xmlEscape,
+	"windowsArgs":  windowsArgs,
+}
+
+// render executes a template with a config
+func render(name, text string, cfg Config) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, cfg); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// SystemdUnit renders the systemd --user unit for a config
+func SystemdUnit(cfg Config) (string, error) {
+	return render("systemd", systemdTemplate, cfg)
+}
+
+// LaunchdPlist renders the launchd agent for a config
+func LaunchdPlist(cfg Config) (string, error) {
+	return render("launchd", launchdTemplate, cfg)
+}
+
+// ScheduledTask renders the Windows scheduled task XML for a config
+func ScheduledTask(cfg Config) (string, error) {
+	return render("schtasks", taskTemplate, cfg)
+}
+
+// systemdQuote quotes a word of an ExecStart line. Specifiers (%) are
+// escaped too, since systemd expands them even in quotes.
+func systemdQuote(word string) string {
+	word = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(word)
+	return `"` + word + `"`
+}
+
+// xmlEscape escapes text for XML
+func xmlEscape(text string) string {
+	var out strings.Builder
+	xml.EscapeText(&out, []byte(text))
+	return out.String()
+}
+
+// windowsArgs joins arguments into a Windows command line, quoting those
+// that need it
+func windowsArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+			quoted[i] = arg
+			continue
+		}
+		// Backslashes are only special before a quote (or the closing quote),
+		// where they're doubled
+		var b strings.Builder
+		b.WriteByte('"')
+		slashes := 0
+		for _, r := range arg {
+			switch r {
+			case '\\':
+				slashes++
+				continue
+			case '"':
+				b.WriteString(strings.Repeat(`\`, 2*slashes+1))
+			default:
+				b.WriteString(strings.Repeat(`\`, slashes))
+			}
+			slashes = 0
+			b.WriteRune(r)
+		}
+		b.WriteString(strings.Repeat(`\`, 2*slashes))
+		b.WriteByte('"')
+		quoted[i] = b.String()
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"encoding/xml"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// hostileConfig has paths and arguments with everything the templates escape
+var hostileConfig = Config{
+	Executable: `/home/Jo "JD" Doe/100% $HOME & co\bin/lazytrack`,
+	Args:       []string{"daemon", `--note=50% of $PATH & <more> "quoted" \ end\`, ""},
+	Label:      `com.example.lazy&track`,
+	User:       `CORP\Jo & "JD"`,
+}
+
+// windowsConfig is hostileConfig with a Windows path
+var windowsConfig = Config{
+	Executable: `C:\Users\Jo "JD" Doe\100% $HOME & co\lazytrack.exe`,
+	Args:       hostileConfig.Args,
+	Label:      `Lazy&Track`,
+	User:       hostileConfig.User,
+}
+
+// checkGolden compares output with a file in testdata, or writes it with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from %s:\n%s", name, path, got)
+	}
+}
+
+// checkXML checks that a file is well-formed XML and that its strings
+// unescape to the config's values
+func checkXML(t *testing.T, content string, want ...string) {
+	t.Helper()
+	// The task claims UTF-16, which it's only encoded as when it's written
+	content = strings.Replace(content, `encoding="UTF-16"`, `encoding="UTF-8"`, 1)
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	var texts []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err != io.EOF {
+				t.Fatalf("invalid XML: %v", err)
+			}
+			break
+		}
+		if text, ok := token.(xml.CharData); ok {
+			texts = append(texts, string(text))
+		}
+	}
+	all := strings.Join(texts, "\n")
+	for _, value := range want {
+		if !strings.Contains(all, value) {
+			t.Errorf("XML text doesn't contain %q", value)
+		}
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	unit, err := SystemdUnit(hostileConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "lazytrack.service.golden", unit)
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist, err := LaunchdPlist(hostileConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "lazytrack.plist.golden", plist)
+	checkXML(t, plist, append([]string{hostileConfig.Executable, hostileConfig.Label}, hostileConfig.Args[:2]...)...)
+}
+
+func TestScheduledTask(t *testing.T) {
+	task, err := ScheduledTask(windowsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "lazytrack-task.xml.golden", task)
+	checkXML(t, task, windowsConfig.Executable, `\`+windowsConfig.Label, windowsConfig.User, windowsArgs(windowsConfig.Args))
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"daemon", `"daemon"`},
+		{"", `""`},
+		{"/opt/my apps/lazytrack", `"/opt/my apps/lazytrack"`},
+		{`a "b" c`, `"a \"b\" c"`},
+		{`C:\x\`, `"C:\\x\\"`},
+		{"100%", `"100%%"`},
+		{"%h/$HOME/${USER}", `"%%h/$$HOME/$${USER}"`},
+		{"a & b; c | d", `"a & b; c | d"`},
+	}
+	for _, test := range tests {
+		if got := systemdQuote(test.word); got != test.want {
+			t.Errorf("systemdQuote(%q) = %s, want %s", test.word, got, test.want)
+		}
+	}
+}
+
+func TestXMLEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain", "plain"},
+		{`a & b`, `a &amp; b`},
+		{`<x>`, `&lt;x&gt;`},
+		{`"q" 'a'`, `&#34;q&#34; &#39;a&#39;`},
+		{"a\nb", "a&#xA;b"},
+		{`100% $HOME \`, `100% $HOME \`},
+	}
+	for _, test := range tests {
+		if got := xmlEscape(test.text); got != test.want {
+			t.Errorf("xmlEscape(%q) = %s, want %s", test.text, got, test.want)
+		}
+	}
+}
+
+func TestWindowsArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"daemon", "--background"}, `daemon --background`},
+		{[]string{""}, `""`},
+		{[]string{"a b"}, `"a b"`},
+		{[]string{"tab\there"}, "\"tab\there\""},
+		{[]string{`C:\dir\`}, `C:\dir\`},
+		{[]string{`C:\my dir\`}, `"C:\my dir\\"`},
+		{[]string{`say "hi"`}, `"say \"hi\""`},
+		{[]string{`a\"b`}, `"a\\\"b"`},
+		{[]string{`a\\b c`}, `"a\\b c"`},
+		{[]string{"100%", "$x", "a&b", "<c>"}, `100% $x a&b <c>`},
+	}
+	for _, test := range tests {
+		if got := windowsArgs(test.args); got != test.want {
+			t.Errorf("windowsArgs(%q) = %s, want %s", test.args, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/parser"
+	"github.com/master-wayne7/lazytrack/summary"
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// CalculateHabitStats calculates statistics for a habit from logs between startDate and endDate.
+// Completion rates always look back 30, 90 and 365 days from endDate.
+func CalculateHabitStats(habit types.Habit, logs []types.Log, startDate, endDate time.Time) types.HabitStats {
+	stats := types.HabitStats{
+		HabitName: habit.Name,
+		Emoji:     habit.Emoji,
+		Unit:      "minutes",
+	}
+	if habit.GoalType == "count" {
+		stats.Unit = "count"
+	}
+
+	// Collect session lengths and time-of-day data
+	var sessions []float64
+	for _, log := range logs {
+		if log.LoggedAt.Before(startDate) || !log.LoggedAt.Before(endDate) {
+			continue
+		}
+
+		value := summary.LogValue(habit, log)
+		if stats.Unit == "minutes" {
+			value *= 60
+		}
+		sessions = append(sessions, value)
+		stats.HourHistogram[log.LoggedAt.Hour()]++
+		stats.WeekdayTotals[log.LoggedAt.Weekday()] += value
+	}
+
+	stats.Sessions = len(sessions)
+	if len(sessions) > 0 {
+		sort.Float64s(sessions)
+		stats.Mean = mean(sessions)
+		stats.Median = Percentile(sessions, 50)
+		stats.P25 = Percentile(sessions, 25)
+		stats.P75 = Percentile(sessions, 75)
+		stats.P90 = Percentile(sessions, 90)
+	}
+
+	// Sessions per active day and the most productive weekday
+	dailyTotals := summary.CalculateDailyTotals(habit, logs, startDate, endDate)
+	activeDays := 0
+	for _, day := range dailyTotals {
+		if day.Sessions > 0 {
+			activeDays++
+		}
+	}
+	if activeDays > 0 {
+		stats.SessionsPerDay = float64(stats.Sessions) / float64(activeDays)
+	}
+
+	best := -1
+	for weekday, total := range stats.WeekdayTotals {
+		if total > 0 && (best < 0 || total > stats.WeekdayTotals[best]) {
+			best = weekday
+		}
+	}
+	if best >= 0 {
+		stats.BestWeekday = time.Weekday(best).String()
+	}
+
+	// Completion rates over fixed windows
+	stats.Completion30 = CompletionRate(habit, logs, endDate.AddDate(0, 0, -30), endDate)
+	stats.Completion90 = CompletionRate(habit, logs, endDate.AddDate(0, 0, -90), endDate)
+	stats.Completion365 = CompletionRate(habit, logs, endDate.AddDate(0, 0, -365), endDate)
+
+	// Best and worst complete weeks
+	stats.BestWeek, stats.WorstWeek = bestAndWorstWeeks(dailyTotals, endDate)
+
+	return stats
+}
+
+// CompletionRate returns the percentage of days between startDate and endDate on which
+// the daily goal was reached. Habits without a goal count any logged day as complete.
+func CompletionRate(habit types.Habit, logs []types.Log, startDate, endDate time.Time) float64 {
+	dailyTotals := summary.CalculateDailyTotals(habit, logs, startDate, endDate)
+	if len(dailyTotals) == 0 {
+		return 0
+	}
+
+	completed := 0
+	for _, day := range dailyTotals {
+		if habit.DailyGoal > 0 {
+			if day.Value >= float64(habit.DailyGoal) {
+				completed++
+			}
+		} else if day.Sessions > 0 {
+			completed++
+		}
+	}
+
+	return float64(completed) / float64(len(dailyTotals)) * 100
+}
+
+// bestAndWorstWeeks finds the highest and lowest weekly totals among complete weeks,
+// ignoring weeks before the first logged day
+func bestAndWorstWeeks(dailyTotals []types.DailyTotal, endDate time.Time) (*types.WeekTotal, *types.WeekTotal) {
+	var weeks []types.WeekTotal
+	started := false
+	for _, day := range dailyTotals {
+		if day.Sessions > 0 {
+			started = true
+		}
+		if !started {
+			continue
+		}
+
+		weekStart := parser.WeekStart(day.Date)
+		if weekStart.AddDate(0, 0, 7).After(endDate) {
+			break // Skip the current, incomplete week
+		}
+		if len(weeks) == 0 || !weeks[len(weeks)-1].StartDate.Equal(weekStart) {
+			weeks = append(weeks, types.WeekTotal{StartDate: weekStart})
+		}
+		weeks[len(weeks)-1].Value += day.Value
+	}
+
+	if len(weeks) == 0 {
+		return nil, nil
+	}
+
+	best, worst := weeks[0], weeks[0]
+	for _, week := range weeks[1:] {
+		if week.Value > best.Value {
+			best = week
+		}
+		if week.Value < worst.Value {
+			worst = week
+		}
+	}
+
+	return &best, &worst
+}
+
+// Percentile returns the p-th percentile of sorted values using linear interpolation
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+// FormatHabitStats formats habit statistics for display
+func FormatHabitStats(stats types.HabitStats) string {
+	var result strings.Builder
+
+	// Header
+	result.WriteString(fmt.Sprintf("%s %s\n", stats.Emoji, stats.HabitName))
+	result.WriteString(strings.Repeat("-", 50) + "\n")
+
+	if stats.Sessions == 0 {
+		result.WriteString("No logs in this range\n")
+		return result.String()
+	}
+
+	// Session lengths
+	result.WriteString(fmt.Sprintf("📋 Sessions:        %d (%.1f per active day)\n", stats.Sessions, stats.SessionsPerDay))
+	result.WriteString(fmt.Sprintf("📏 Mean / Median:   %s / %s\n", formatAmount(stats.Mean, stats.Unit), formatAmount(stats.Median, stats.Unit)))
+	result.WriteString(fmt.Sprintf("📐 P25 / P75 / P90: %s / %s / %s\n",
+		formatAmount(stats.P25, stats.Unit), formatAmount(stats.P75, stats.Unit), formatAmount(stats.P90, stats.Unit)))
+
+	// Weekday and completion
+	if stats.BestWeekday != "" {
+		result.WriteString(fmt.Sprintf("📅 Best weekday:    %s\n", stats.BestWeekday))
+	}
+	result.WriteString(fmt.Sprintf("✅ Completion:      %.0f%% (30d)  %.0f%% (90d)  %.0f%% (365d)\n",
+		stats.Completion30, stats.Completion90, stats.Completion365))
+
+	// Weeks
+	if stats.BestWeek != nil {
+		result.WriteString(fmt.Sprintf("🏆 Best week:       %s (%s)\n",
+			stats.BestWeek.StartDate.Format("Jan 2, 2006"), formatWeekValue(stats.BestWeek.Value, stats.Unit)))
+		result.WriteString(fmt.Sprintf("🐢 Worst week:      %s (%s)\n",
+			stats.WorstWeek.StartDate.Format("Jan 2, 2006"), formatWeekValue(stats.WorstWeek.Value, stats.Unit)))
+	}
+
+	// Hour-of-day histogram
+	result.WriteString("\n🕒 Logs by hour of day\n")
+	result.WriteString(formatHourHistogram(stats.HourHistogram))
+
+	return result.String()
+}
+
+// formatAmount formats a session length in minutes or a count
+func formatAmount(value float64, unit string) string {
+	if unit == "count" {
+		return fmt.Sprintf("%.1fx", value)
+	}
+	minutes := int(math.Round(value))
+	return parser.FormatDuration(types.ParsedDuration{Hours: minutes / 60, Minutes: minutes % 60, IsValid: true})
+}
+
+// formatWeekValue formats a weekly total in hours or a count
+func formatWeekValue(value float64, unit string) string {
+	if unit == "count" {
+		return fmt.Sprintf("%.0fx", value)
+	}
+	return fmt.Sprintf("%.1fh", value)
+}
+
+// formatHourHistogram renders the hour histogram as a vertical-bar sparkline
+func formatHourHistogram(histogram [24]int) string {
+	levels := []rune(" ▁▂▃▄▅▆▇█")
+
+	maxValue := 0
+	for _, count := range histogram {
+		if count > maxValue {
+			maxValue = count
+		}
+	}
+
+	var bars strings.Builder
+	for _, count := range histogram {
+		level := 0
+		if maxValue > 0 {
+			level = int(math.Ceil(float64(count) / float64(maxValue) * float64(len(levels)-1)))
+		}
+		bars.WriteRune(levels[level])
+	}
+
+	return bars.String() + "\n0     6     12    18  23\n"
+}
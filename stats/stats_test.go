@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{"empty", nil, 50, 0},
+		{"single value", []float64{7}, 90, 7},
+		{"minimum", []float64{1, 2, 3, 4}, 0, 1},
+		{"maximum", []float64{1, 2, 3, 4}, 100, 4},
+		{"median of even count", []float64{1, 2, 3, 4}, 50, 2.5},
+		{"median of odd count", []float64{1, 2, 3, 4, 5}, 50, 3},
+		{"p25", []float64{1, 2, 3, 4}, 25, 1.75},
+		{"p75", []float64{1, 2, 3, 4}, 75, 3.25},
+		{"p90", []float64{15, 30, 60, 90, 120}, 90, 108},
+	}
+	for _, tt := range tests {
+		if got := Percentile(tt.sorted, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompletionRate(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	day := func(days, hour int) time.Time {
+		return start.AddDate(0, 0, days).Add(time.Duration(hour) * time.Hour)
+	}
+	durationLogs := []types.Log{
+		{HabitName: "code", Duration: "30m", LoggedAt: day(0, 10)},
+		{HabitName: "code", Duration: "1h", LoggedAt: day(0, 14)},
+		{HabitName: "code", Duration: "2h", LoggedAt: day(2, 9)},
+		{HabitName: "code", Duration: "15m", LoggedAt: day(3, 9)},
+		{HabitName: "code", Duration: "1h30m", LoggedAt: day(7, 10)},
+		{HabitName: "code", Duration: "3h", LoggedAt: day(10, 10)}, // outside the range
+	}
+	countLogs := []types.Log{
+		{HabitName: "water", Count: 3, LoggedAt: day(0, 9)},
+		{HabitName: "water", Count: 5, LoggedAt: day(0, 18)},
+		{HabitName: "water", Count: 7, LoggedAt: day(1, 9)},
+	}
+
+	tests := []struct {
+		name  string
+		habit types.Habit
+		logs  []types.Log
+		end   time.Time
+		want  float64
+	}{
+		{"duration goal", types.Habit{Name: "code", GoalType: "duration", DailyGoal: 1}, durationLogs, day(10, 0), 30},
+		{"no goal counts logged days", types.Habit{Name: "code", GoalType: "duration"}, durationLogs, day(10, 0), 40},
+		{"count goal", types.Habit{Name: "water", GoalType: "count", DailyGoal: 8}, countLogs, day(4, 0), 25},
+		{"empty range", types.Habit{Name: "code", GoalType: "duration", DailyGoal: 1}, durationLogs, start, 0},
+	}
+	for _, tt := range tests {
+		if got := CompletionRate(tt.habit, tt.logs, start, tt.end); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateHabitStats(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local) // a Monday
+	end := start.AddDate(0, 0, 21)
+	day := func(days, hour int) time.Time {
+		return start.AddDate(0, 0, days).Add(time.Duration(hour) * time.Hour)
+	}
+	habit := types.Habit{Name: "code", GoalType: "duration", DailyGoal: 1}
+	logs := []types.Log{
+		{HabitName: "code", Duration: "30m", LoggedAt: day(0, 10)},
+		{HabitName: "code", Duration: "1h", LoggedAt: day(0, 14)},
+		{HabitName: "code", Duration: "2h", LoggedAt: day(2, 9)},
+		{HabitName: "code", Duration: "1h30m", LoggedAt: day(7, 10)},
+		{HabitName: "code", Duration: "15m", LoggedAt: day(15, 20)},
+	}
+
+	stats := CalculateHabitStats(habit, logs, start, end)
+	if stats.Unit != "minutes" || stats.Sessions != 5 {
+		t.Fatalf("got %d sessions in %s, want 5 in minutes", stats.Sessions, stats.Unit)
+	}
+
+	// Sessions are 15, 30, 60, 90 and 120 minutes
+	values := []struct {
+		name      string
+		got, want float64
+	}{
+		{"mean", stats.Mean, 63},
+		{"median", stats.Median, 60},
+		{"p25", stats.P25, 30},
+		{"p75", stats.P75, 90},
+		{"p90", stats.P90, 108},
+		{"sessions per day", stats.SessionsPerDay, 1.25},
+		{"completion 30d", stats.Completion30, 10},
+	}
+	for _, v := range values {
+		if math.Abs(v.got-v.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", v.name, v.got, v.want)
+		}
+	}
+
+	if stats.BestWeekday != "Monday" {
+		t.Errorf("best weekday: got %s, want Monday", stats.BestWeekday)
+	}
+	if stats.HourHistogram[10] != 2 || stats.HourHistogram[20] != 1 {
+		t.Errorf("hour histogram: got %v", stats.HourHistogram)
+	}
+	if stats.BestWeek == nil || !stats.BestWeek.StartDate.Equal(start) || stats.BestWeek.Value != 3.5 {
+		t.Errorf("best week: got %+v, want 3.5h from %s", stats.BestWeek, start.Format("2006-01-02"))
+	}
+	if stats.WorstWeek == nil || !stats.WorstWeek.StartDate.Equal(day(14, 0)) || stats.WorstWeek.Value != 0.25 {
+		t.Errorf("worst week: got %+v, want 0.25h from %s", stats.WorstWeek, day(14, 0).Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/master-wayne7/lazytrack/filelock"
+)
+
+// lockName is the file locked while the data is changed
+const lockName = "lazytrack.lock"
+
+// processLock is a process's lock of a data directory
+type processLock struct {
+	file  *os.File
+	count int // stores and saves holding it
+}
+
+var (
+	locksMu sync.Mutex
+	locks   = make(map[string]*processLock) // by data directory
+)
+
+// acquireLock locks a data directory against other processes. Stores of one
+// process share the lock, so a store opened while another is held (e.g. to
+// send a notification from a command) doesn't wait for itself.
+func acquireLock(dataPath string) error {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+
+	if held, ok := locks[dataPath]; ok {
+		held.count++
+		return nil
+	}
+	file, err := os.OpenFile(filepath.Join(dataPath, lockName), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := filelock.Lock(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to lock data: %w", err)
+	}
+	locks[dataPath] = &processLock{file: file, count: 1}
+	return nil
+}
+
+// releaseLock releases a lock taken with acquireLock
+func releaseLock(dataPath string) {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+
+	held, ok := locks[dataPath]
+	if !ok {
+		return
+	}
+	if held.count--; held.count == 0 {
+		filelock.Unlock(held.file)
+		held.file.Close()
+		delete(locks, dataPath)
+	}
+}
+
+// writeFile replaces a file by renaming a new one over it, so other processes
+// never read a half written file
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// Keys of the settings, kept in config.json next to the other configuration
+const (
+	NotificationsEnabledKey = "notifications.enabled"
+	SoundEnabledKey         = "sound.enabled"
+)
+
+// Setting describes a setting changed with lazytrack config set. A "*" in the
+// key stands for the name of a habit or notification backend.
+type Setting struct {
+	Key         string
+	Default     string
+	Description string
+}
+
+// Settings lists the settings
+var Settings = []Setting{
+	{NotificationsEnabledKey, "true", "Send notifications"},
+	{SoundEnabledKey, "true", "Play notification sounds and ring the bell when a goal is reached"},
+	{"habits.*.muted", "false", "Send no notifications about a habit"},
+	{"notifiers.*.enabled", "true", "Send notifications via a backend"},
+}
+
+// HabitMutedKey returns the key of the setting muting a habit
+func HabitMutedKey(habit string) string {
+	return "habits." + habit + ".muted"
+}
+
+// NotifierEnabledKey returns the key of the setting toggling a backend
+func NotifierEnabledKey(backend string) string {
+	return "notifiers." + backend + ".enabled"
+}
+
+// LookupSetting returns the setting of a key, and the habit or backend name in
+// it for the settings of one
+func LookupSetting(key string) (Setting, string, error) {
+	for _, setting := range Settings {
+		prefix, suffix, wildcard := strings.Cut(setting.Key, "*")
+		if !wildcard {
+			if key == setting.Key {
+				return setting, "", nil
+			}
+			continue
+		}
+		if name, ok := strings.CutPrefix(key, prefix); ok {
+			if name, ok = strings.CutSuffix(name, suffix); ok && name != "" {
+				return setting, name, nil
+			}
+		}
+	}
+	return Setting{}, "", fmt.Errorf("unknown setting: %s (see lazytrack config list)", key)
+}
+
+// ParseBool parses the value of an on/off setting
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q (use true or false)", value)
+	}
+	return enabled, nil
+}
+
+// GetSetting gets a setting's value, or its default if it isn't set
+func (s *Store) GetSetting(key string) (string, error) {
+	setting, _, err := LookupSetting(key)
+	if err != nil {
+		return "", err
+	}
+	if value, exists := s.config[key]; exists {
+		return value, nil
+	}
+	return setting.Default, nil
+}
+
+// SetSetting checks and sets a setting's value. Setting it back to its
+// default removes it.
+func (s *Store) SetSetting(key, value string) error {
+	setting, name, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(setting.Key, "habits.") {
+		if _, err := s.GetHabitByName(name); err != nil {
+			return err
+		}
+	}
+
+	enabled, err := ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	value = strconv.FormatBool(enabled)
+	if value == setting.Default {
+		delete(s.config, key)
+		return nil
+	}
+	s.config[key] = value
+	return nil
+}
+
+// GetSettings returns the settings
+func (s *Store) GetSettings() types.Config {
+	cfg := types.Config{NotificationsEnabled: true, SoundEnabled: true}
+	for key, value := range s.config {
+		setting, name, err := LookupSetting(key)
+		if err != nil {
+			continue // other configuration
+		}
+		enabled, err := ParseBool(value)
+		if err != nil {
+			enabled, _ = ParseBool(setting.Default)
+		}
+		switch {
+		case key == NotificationsEnabledKey:
+			cfg.NotificationsEnabled = enabled
+		case key == SoundEnabledKey:
+			cfg.SoundEnabled = enabled
+		case key == HabitMutedKey(name) && enabled:
+			cfg.MutedHabits = append(cfg.MutedHabits, name)
+		case key == NotifierEnabledKey(name) && !enabled:
+			cfg.DisabledNotifiers = append(cfg.DisabledNotifiers, name)
+		}
+	}
+	sort.Strings(cfg.MutedHabits)
+	sort.Strings(cfg.DisabledNotifiers)
+	return cfg
+}
+
+// GetSettingValues returns every setting that differs from its default
+func (s *Store) GetSettingValues() map[string]string {
+	values := make(map[string]string)
+	for key, value := range s.config {
+		if _, _, err := LookupSetting(key); err == nil {
+			values[key] = value
+		}
+	}
+	return values
+}
@@ -1,12 +1,14 @@
 package store
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/master-wayne7/lazytrack/parser"
 	"github.com/master-wayne7/lazytrack/types"
 )
 
@@ -15,10 +17,32 @@ type Store struct {
 	habits   map[string]*types.Habit
 	logs     []types.Log
 	config   map[string]string
+	ids      logIDs
+	saved    map[string][]byte // what the data files hold, by name
+	locked   bool              // holds the data lock until Close
 }
 
-// NewStore creates a new store instance
+// logIDs remembers the last log ID given out, so the ID of a deleted log
+// isn't given to a new one
+type logIDs struct {
+	Last int `json:"last_log_id"`
+}
+
+// NewStore creates a new store instance, for reading the data. Close saves
+// changes, but they may overwrite what other processes saved in between; use
+// NewLockedStore to change the data.
 func NewStore() (*Store, error) {
+	return newStore(false)
+}
+
+// NewLockedStore creates a store for changing the data. Other processes
+// changing the data wait until it's closed.
+func NewLockedStore() (*Store, error) {
+	return newStore(true)
+}
+
+// newStore creates a store, optionally locking the data until Close
+func newStore(locked bool) (*Store, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
@@ -34,20 +58,37 @@ func NewStore() (*Store, error) {
 		habits:   make(map[string]*types.Habit),
 		logs:     []types.Log{},
 		config:   make(map[string]string),
+		locked:   locked,
 	}
 
+	if locked {
+		if err := acquireLock(dataPath); err != nil {
+			return nil, err
+		}
+	}
 	if err := store.loadData(); err != nil {
+		store.Release()
 		return nil, fmt.Errorf("failed to load data: %w", err)
 	}
 
 	return store, nil
 }
 
-// Close closes the store (saves data)
+// Close closes the store (saves data that changed and releases the lock)
 func (s *Store) Close() error {
+	defer s.Release()
 	return s.saveData()
 }
 
+// Release releases the lock of a locked store without saving it, e.g. when
+// a command fails halfway. It does nothing once the store is closed.
+func (s *Store) Release() {
+	if s.locked {
+		s.locked = false
+		releaseLock(s.dataPath)
+	}
+}
+
 // loadData loads data from JSON files
 func (s *Store) loadData() error {
 	// Load habits
@@ -74,33 +115,54 @@ func (s *Store) loadData() error {
 		}
 	}
 
+	// Load the last log ID
+	idsPath := filepath.Join(s.dataPath, "ids.json")
+	if data, err := os.ReadFile(idsPath); err == nil {
+		if err := json.Unmarshal(data, &s.ids); err != nil {
+			return fmt.Errorf("failed to unmarshal log IDs: %w", err)
+		}
+	}
+
+	// Remember what was loaded, so only files that changed are saved
+	s.saved = make(map[string][]byte)
+	for name, value := range s.dataFiles() {
+		if data, err := json.MarshalIndent(value, "", "  "); err == nil {
+			s.saved[name] = data
+		}
+	}
+
 	return nil
 }
 
-// saveData saves data to JSON files
+// dataFiles returns the data files by name (habits for habits.json), and what
+// they hold
+func (s *Store) dataFiles() map[string]any {
+	return map[string]any{"habits": s.habits, "logs": s.logs, "config": s.config, "ids": s.ids}
+}
+
+// saveData saves the data to the JSON files that changed
 func (s *Store) saveData() error {
-	// Save habits
-	habitsPath := filepath.Join(s.dataPath, "habits.json")
-	if data, err := json.MarshalIndent(s.habits, "", "  "); err == nil {
-		if err := os.WriteFile(habitsPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to save habits: %w", err)
+	changed := make(map[string][]byte)
+	for name, value := range s.dataFiles() {
+		if data, err := json.MarshalIndent(value, "", "  "); err == nil && !bytes.Equal(data, s.saved[name]) {
+			changed[name] = data
 		}
 	}
+	if len(changed) == 0 {
+		return nil
+	}
 
-	// Save logs
-	logsPath := filepath.Join(s.dataPath, "logs.json")
-	if data, err := json.MarshalIndent(s.logs, "", "  "); err == nil {
-		if err := os.WriteFile(logsPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to save logs: %w", err)
+	if !s.locked {
+		if err := acquireLock(s.dataPath); err != nil {
+			return err
 		}
+		defer releaseLock(s.dataPath)
 	}
-
-	// Save config
-	configPath := filepath.Join(s.dataPath, "config.json")
-	if data, err := json.MarshalIndent(s.config, "", "  "); err == nil {
-		if err := os.WriteFile(configPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
+	for name, data := range changed {
+		if err := writeFile(filepath.Join(s.dataPath, name+".json"), data); err != nil {
+			return fmt.Errorf("failed to save %s: %w", name, err)
 		}
+		s.saved[name] = data
 	}
 
 	return nil
@@ -138,7 +200,7 @@ func (s *Store) GetHabitByName(name string) (*types.Habit, error) {
 // AddLog adds a new log entry
 func (s *Store) AddLog(habitID int, habitName, duration string, count int, notes string) error {
 	log := types.Log{
-		ID:        len(s.logs) + 1,
+		ID:        s.nextLogID(),
 		HabitID:   habitID,
 		HabitName: habitName,
 		Duration:  duration,
@@ -164,6 +226,95 @@ func (s *Store) GetLogsByHabit(habitName string, startDate, endDate time.Time) (
 	return filteredLogs, nil
 }
 
+// AddLogEntry adds a log entry with its own timestamp (e.g., from an import)
+func (s *Store) AddLogEntry(log types.Log) error {
+	if log.HabitName == "" {
+		return fmt.Errorf("log has no habit name")
+	}
+	if log.LoggedAt.IsZero() {
+		return fmt.Errorf("log has no timestamp")
+	}
+
+	log.ID = s.nextLogID()
+	s.logs = append(s.logs, log)
+	return nil
+}
+
+// nextLogID returns the ID for a new log. IDs are never reused, even after
+// the newest log is deleted, so files that refer to logs by ID stay valid.
+func (s *Store) nextLogID() int {
+	id := max(len(s.logs), s.ids.Last) + 1
+	for _, log := range s.logs {
+		id = max(id, log.ID+1)
+	}
+	s.ids.Last = id
+	return id
+}
+
+// DeleteLog removes the log with the given ID
+func (s *Store) DeleteLog(id int) error {
+	for i := range s.logs {
+		if s.logs[i].ID == id {
+			s.logs = append(s.logs[:i], s.logs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("log not found: %d", id)
+}
+
+// UpdateLog replaces a log entry with the same ID
+func (s *Store) UpdateLog(log types.Log) error {
+	for i := range s.logs {
+		if s.logs[i].ID == log.ID {
+			s.logs[i] = log
+			return nil
+		}
+	}
+	return fmt.Errorf("log not found: %d", log.ID)
+}
+
+// GetLogBySource gets the log created by an integration with the given source key
+func (s *Store) GetLogBySource(source string) (*types.Log, error) {
+	for i := range s.logs {
+		if source != "" && s.logs[i].Source == source {
+			log := s.logs[i]
+			return &log, nil
+		}
+	}
+	return nil, fmt.Errorf("log not found: %s", source)
+}
+
+// GetAllLogs gets all logs
+func (s *Store) GetAllLogs() ([]types.Log, error) {
+	logs := make([]types.Log, len(s.logs))
+	copy(logs, s.logs)
+	return logs, nil
+}
+
+// HasLog checks if a log with the same habit, timestamp and quantity exists
+func (s *Store) HasLog(habitName string, loggedAt time.Time, duration string, count int) bool {
+	for _, log := range s.logs {
+		if log.HabitName == habitName && log.LoggedAt.Equal(loggedAt) && log.Count == count &&
+			sameDuration(log.Duration, duration) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameDuration checks if two duration strings describe the same amount of time
+func sameDuration(a, b string) bool {
+	if a == b {
+		return true
+	}
+	parsedA, errA := parser.ParseDuration(a)
+	parsedB, errB := parser.ParseDuration(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return parser.GetTotalMinutes(parsedA) == parser.GetTotalMinutes(parsedB)
+}
+
 // GetAllHabits gets all habits
 func (s *Store) GetAllHabits() ([]types.Habit, error) {
 	var habits []types.Habit
@@ -179,6 +330,46 @@ func (s *Store) UpdateHabit(habit *types.Habit) error {
 	return nil
 }
 
+// DataDir returns the directory holding lazytrack's files
+func (s *Store) DataDir() string {
+	return s.dataPath
+}
+
+// LoadState loads a state file kept next to the data files (e.g. "sync" for
+// sync.json). A missing file leaves the value unchanged.
+func (s *Store) LoadState(name string, value any) error {
+	data, err := os.ReadFile(filepath.Join(s.dataPath, name+".json"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s state: %w", name, err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to unmarshal %s state: %w", name, err)
+	}
+	return nil
+}
+
+// SaveState saves a state file kept next to the data files. State loaded
+// and saved with a locked store isn't changed by other processes in between.
+func (s *Store) SaveState(name string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s state: %w", name, err)
+	}
+	if !s.locked {
+		if err := acquireLock(s.dataPath); err != nil {
+			return err
+		}
+		defer releaseLock(s.dataPath)
+	}
+	if err := writeFile(filepath.Join(s.dataPath, name+".json"), data); err != nil {
+		return fmt.Errorf("failed to save %s state: %w", name, err)
+	}
+	return nil
+}
+
 // GetConfig gets a configuration value
 func (s *Store) GetConfig(key string) (string, error) {
 	if value, exists := s.config[key]; exists {
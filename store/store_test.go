@@ -0,0 +1,196 @@
+package store
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+// setHome points the store at a temporary home directory and returns the data directory
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return filepath.Join(home, ".lazytrack")
+}
+
+// addLog adds a log of a habit to a store
+func addLog(t *testing.T, s *Store, habitName string) {
+	t.Helper()
+	habit, err := s.GetOrCreateHabit(habitName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddLogEntry(types.Log{HabitID: habit.ID, HabitName: habit.Name, Count: 1, LoggedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCloseSavesOnlyChanges(t *testing.T) {
+	dataDir := setHome(t)
+
+	// Nothing is written for a store that wasn't changed
+	s, err := NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "logs.json")); !os.IsNotExist(err) {
+		t.Fatalf("logs.json was written for an unchanged store: %v", err)
+	}
+
+	s, _ = NewLockedStore()
+	addLog(t, s, "water")
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"habits.json", "logs.json"} {
+		if _, err := os.Stat(filepath.Join(dataDir, name)); err != nil {
+			t.Errorf("%s wasn't saved: %v", name, err)
+		}
+	}
+
+	// A habit changed through its pointer is saved too, and only its file
+	logsPath := filepath.Join(dataDir, "logs.json")
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(logsPath, old, old)
+	before, _ := os.Stat(logsPath)
+	s, _ = NewLockedStore()
+	habit, _ := s.GetHabitByName("water")
+	habit.DailyGoal = 10
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.Stat(logsPath); !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("logs.json was saved without changes")
+	}
+	s, _ = NewStore()
+	if habit, _ := s.GetHabitByName("water"); habit.DailyGoal != 10 {
+		t.Errorf("daily goal = %d, want 10", habit.DailyGoal)
+	}
+
+	// No temporary files are left
+	entries, _ := os.ReadDir(dataDir)
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".tmp" {
+			t.Errorf("temporary file %s left", entry.Name())
+		}
+	}
+}
+
+func TestLockedStoresShareProcessLock(t *testing.T) {
+	setHome(t)
+	outer, err := NewLockedStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A store opened while another is held, and saving state, don't wait
+	done := make(chan error, 1)
+	go func() {
+		inner, err := NewLockedStore()
+		if err == nil {
+			err = inner.SaveState("test", map[string]int{"a": 1})
+			inner.Close()
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a second store of the process waits for the first")
+	}
+	outer.Close()
+	outer.Close() // closing twice doesn't release the lock twice
+
+	if len(locks) != 0 {
+		t.Errorf("locks still held: %v", locks)
+	}
+}
+
+// TestHelperAddLog adds a log from another process, for TestLockedStoreWaits
+func TestHelperAddLog(t *testing.T) {
+	if os.Getenv("LAZYTRACK_TEST_ADD_LOG") == "" {
+		t.Skip("only run by TestLockedStoreWaits")
+	}
+	s, err := NewLockedStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addLog(t, s, os.Getenv("LAZYTRACK_TEST_ADD_LOG"))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockedStoreWaits(t *testing.T) {
+	setHome(t)
+	s, err := NewLockedStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addLog(t, s, "water")
+
+	// Another process adding a log waits until this store is closed, instead
+	// of saving its own logs in between and losing this store's
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperAddLog$")
+	cmd.Env = append(os.Environ(), "LAZYTRACK_TEST_ADD_LOG=code")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		t.Fatalf("the other process didn't wait for the lock: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("other process: %v", err)
+	}
+
+	s, _ = NewStore()
+	logs, _ := s.GetAllLogs()
+	if len(logs) != 2 || logs[0].HabitName != "water" || logs[1].HabitName != "code" || logs[1].ID != 2 {
+		t.Errorf("logs = %+v, want water and then code", logs)
+	}
+}
+
+func TestLogIDsAreNotReused(t *testing.T) {
+	setHome(t)
+	s, _ := NewLockedStore()
+	addLog(t, s, "water")
+	addLog(t, s, "water")
+	if err := s.DeleteLog(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The newest log was deleted, but its ID stays taken after reloading
+	s, _ = NewLockedStore()
+	addLog(t, s, "water")
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = NewStore()
+	logs, _ := s.GetAllLogs()
+	if len(logs) != 2 || logs[0].ID != 1 || logs[1].ID != 3 {
+		t.Errorf("logs = %+v, want IDs 1 and 3", logs)
+	}
+}
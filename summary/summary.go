@@ -2,18 +2,25 @@ package summary
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/master-wayne7/lazytrack/message"
 	"github.com/master-wayne7/lazytrack/parser"
 	"github.com/master-wayne7/lazytrack/types"
 )
 
 // CalculateWeeklySummary calculates a weekly summary for all habits
 func CalculateWeeklySummary(habits []types.Habit, logsByHabit map[string][]types.Log) types.WeeklySummary {
-	startDate := getWeekStart(time.Now())
+	startDate := parser.WeekStart(time.Now())
 	endDate := startDate.AddDate(0, 0, 7)
 
+	return CalculateSummary(habits, logsByHabit, startDate, endDate)
+}
+
+// CalculateSummary calculates a summary for all habits between startDate and endDate
+func CalculateSummary(habits []types.Habit, logsByHabit map[string][]types.Log, startDate, endDate time.Time) types.WeeklySummary {
 	var summaries []types.Summary
 	totalTime := 0.0
 
@@ -38,16 +45,18 @@ func calculateHabitSummary(habit types.Habit, logs []types.Log, startDate, endDa
 	var totalCount int
 	var streak int
 
-	// Filter logs for the week
-	var weekLogs []types.Log
+	// Filter logs for the period
+	var periodLogs []types.Log
 	for _, log := range logs {
 		if log.LoggedAt.After(startDate) && log.LoggedAt.Before(endDate) {
-			weekLogs = append(weekLogs, log)
+			periodLogs = append(periodLogs, log)
 		}
 	}
 
 	// Calculate totals
-	for _, log := range weekLogs {
+	var totalDistance float64
+	for _, log := range periodLogs {
+		totalDistance += log.Distance
 		if habit.GoalType == "count" {
 			totalCount += log.Count
 		} else {
@@ -61,43 +70,61 @@ func calculateHabitSummary(habit types.Habit, logs []types.Log, startDate, endDa
 	}
 
 	// Calculate goal progress
+	days := parser.DaysInRange(startDate, endDate)
+	goal := PeriodGoal(habit, days)
 	var goalProgress float64
-	if habit.DailyGoal > 0 {
+	if goal > 0 {
 		if habit.GoalType == "count" {
-			goalProgress = float64(totalCount) / float64(habit.DailyGoal*7) * 100
+			goalProgress = float64(totalCount) / goal * 100
 		} else {
-			goalProgress = totalTime / float64(habit.DailyGoal*7) * 100
+			goalProgress = totalTime / goal * 100
 		}
 	}
 
 	// Calculate streak (simplified)
-	streak = calculateStreak(weekLogs)
+	streak = calculateStreak(periodLogs)
+
+	// Calculate consistency
+	var consistency float64
+	if days > 0 {
+		consistency = float64(streak) / float64(days) * 100
+	}
 
 	// Generate bar chart
-	barChart := generateBarChart(habit, totalTime, totalCount, habit.DailyGoal)
+	barChart := generateBarChart(habit, totalTime, totalCount, goal)
 
 	return types.Summary{
 		HabitName:    habit.Name,
 		Emoji:        habit.Emoji,
+		GoalType:     habit.GoalType,
 		TotalTime:    totalTime,
 		TotalCount:   totalCount,
+		TotalDistance: totalDistance,
 		GoalProgress: goalProgress,
 		Streak:       streak,
+		Consistency:  consistency,
 		BarChart:     barChart,
 	}
 }
 
+// PeriodGoal returns the goal for a habit over a number of days.
+// Weekly goals are spread evenly over the days of the week.
+func PeriodGoal(habit types.Habit, days int) float64 {
+	if habit.DailyGoal == 0 && habit.WeeklyGoal > 0 {
+		return float64(habit.WeeklyGoal*days) / 7
+	}
+	return float64(habit.DailyGoal * days)
+}
+
 // generateBarChart creates a visual bar chart
-func generateBarChart(habit types.Habit, totalTime float64, totalCount int, dailyGoal int) string {
+func generateBarChart(habit types.Habit, totalTime float64, totalCount int, goal float64) string {
 	var value float64
-	var maxValue float64
+	maxValue := goal // Period goal (hours for duration habits)
 
 	if habit.GoalType == "count" {
 		value = float64(totalCount)
-		maxValue = float64(dailyGoal * 7) // Weekly goal
 	} else {
 		value = totalTime
-		maxValue = float64(dailyGoal * 7) // Weekly goal in hours
 	}
 
 	if maxValue == 0 {
@@ -138,16 +165,6 @@ func calculateStreak(logs []types.Log) int {
 	return len(uniqueDays)
 }
 
-// getWeekStart returns the start of the current week (Monday)
-func getWeekStart(t time.Time) time.Time {
-	weekday := int(t.Weekday())
-	if weekday == 0 { // Sunday
-		weekday = 7
-	}
-	daysToSubtract := weekday - 1 // Monday = 1
-	return t.AddDate(0, 0, -daysToSubtract).Truncate(24 * time.Hour)
-}
-
 // FormatSummary formats the summary for display
 func FormatSummary(summary types.WeeklySummary) string {
 	var result strings.Builder
@@ -190,6 +207,9 @@ func formatHabitSummary(summary types.Summary) string {
 	} else {
 		result.WriteString("0")
 	}
+	if summary.TotalDistance > 0 {
+		result.WriteString(fmt.Sprintf(" · %.1f km", summary.TotalDistance))
+	}
 
 	// Goal progress
 	if summary.GoalProgress > 0 {
@@ -204,38 +224,341 @@ func formatHabitSummary(summary types.Summary) string {
 	return result.String()
 }
 
-// GetMotivationalMessage returns a motivational message based on progress
-func GetMotivationalMessage(summary types.WeeklySummary) string {
-	var totalProgress float64
-	var completedHabits int
+// GetMotivationalMessage returns a motivational message based on progress.
+// When a previous period is given, the trend against it is preferred.
+// The message comes from the motivation template.
+func GetMotivationalMessage(summary types.WeeklySummary, previous *types.WeeklySummary) string {
+	var data message.Data
+	if previous != nil {
+		data.Trend = getTrendMessage(ComparePeriods(summary, *previous))
+	}
 
+	var totalProgress float64
 	for _, habit := range summary.Habits {
 		if habit.GoalProgress > 0 {
 			totalProgress += habit.GoalProgress
-			completedHabits++
+			data.Habits = append(data.Habits, habit.HabitName)
+		}
+	}
+	if len(data.Habits) > 0 {
+		data.Percent = totalProgress / float64(len(data.Habits))
+	}
+
+	return message.Render(message.Motivation, data)
+}
+
+// getTrendMessage returns a message about the biggest change between periods
+func getTrendMessage(comparison types.PeriodComparison) string {
+	var best *types.HabitComparison
+	for i, habit := range comparison.Habits {
+		if !habit.HasBaseline {
+			continue
+		}
+		if best == nil || math.Abs(habit.Change) > math.Abs(best.Change) {
+			best = &comparison.Habits[i]
+		}
+	}
+
+	if best == nil || math.Abs(best.Change) < 5 {
+		return ""
+	}
+
+	label := periodLabel(comparison.Previous)
+	if best.Change > 0 {
+		return fmt.Sprintf("📈 You logged %.0f%% more %s than %s! Keep it up!", best.Change, best.HabitName, label)
+	}
+	return fmt.Sprintf("📉 You logged %.0f%% less %s than %s. Time to bounce back!", -best.Change, best.HabitName, label)
+}
+
+// periodLabel describes a previous period in words
+func periodLabel(period types.WeeklySummary) string {
+	switch days := parser.DaysInRange(period.StartDate, period.EndDate); {
+	case days == 1:
+		return "the day before"
+	case days == 7:
+		return "last week"
+	case days >= 28 && days <= 31 && period.StartDate.Day() == 1:
+		return "last month"
+	default:
+		return "the previous period"
+	}
+}
+
+// ComparePeriods compares two summaries habit by habit
+func ComparePeriods(current, previous types.WeeklySummary) types.PeriodComparison {
+	previousByHabit := make(map[string]types.Summary)
+	for _, habit := range previous.Habits {
+		previousByHabit[habit.HabitName] = habit
+	}
+
+	var habits []types.HabitComparison
+	for _, habit := range current.Habits {
+		before := previousByHabit[habit.HabitName]
+		change, hasBaseline := percentChange(summaryValue(habit), summaryValue(before))
+
+		habits = append(habits, types.HabitComparison{
+			HabitName:   habit.HabitName,
+			Emoji:       habit.Emoji,
+			Current:     habit,
+			Previous:    before,
+			Change:      change,
+			HasBaseline: hasBaseline,
+		})
+	}
+
+	totalChange, _ := percentChange(current.TotalTime, previous.TotalTime)
+
+	return types.PeriodComparison{
+		Current:     current,
+		Previous:    previous,
+		Habits:      habits,
+		TotalChange: totalChange,
+	}
+}
+
+// summaryValue returns the main value of a habit summary (hours or count)
+func summaryValue(summary types.Summary) float64 {
+	switch summary.GoalType {
+	case "count":
+		return float64(summary.TotalCount)
+	default:
+		return summary.TotalTime
+	}
+}
+
+// percentChange returns the percentage change from before to after
+func percentChange(after, before float64) (float64, bool) {
+	if before == 0 {
+		return 0, false
+	}
+	return (after - before) / before * 100, true
+}
+
+// FormatComparison formats a period comparison for display
+func FormatComparison(comparison types.PeriodComparison) string {
+	var result strings.Builder
+
+	// Header
+	result.WriteString("📊 Period Comparison\n")
+	result.WriteString("=" + strings.Repeat("=", 50) + "\n")
+	result.WriteString(fmt.Sprintf("📅 %s  vs  %s\n\n",
+		formatPeriod(comparison.Current), formatPeriod(comparison.Previous)))
+
+	// Habit comparisons
+	for _, habit := range comparison.Habits {
+		result.WriteString(fmt.Sprintf("%s %s\n", habit.Emoji, habit.HabitName))
+
+		change := "  new"
+		if habit.HasBaseline {
+			change = formatDelta(habit.Change, "%")
+		}
+		isCount := habit.Current.TotalCount > 0 || habit.Previous.TotalCount > 0
+		result.WriteString(fmt.Sprintf("   Total:       %-8s vs  %-8s %s\n",
+			formatValue(habit.Current, isCount), formatValue(habit.Previous, isCount), change))
+
+		if habit.Current.GoalProgress > 0 || habit.Previous.GoalProgress > 0 {
+			result.WriteString(fmt.Sprintf("   Goal:        %-8s vs  %-8s %s\n",
+				fmt.Sprintf("%.0f%%", habit.Current.GoalProgress),
+				fmt.Sprintf("%.0f%%", habit.Previous.GoalProgress),
+				formatDelta(habit.Current.GoalProgress-habit.Previous.GoalProgress, " pts")))
 		}
+
+		result.WriteString(fmt.Sprintf("   Consistency: %-8s vs  %-8s %s\n",
+			fmt.Sprintf("%.0f%%", habit.Current.Consistency),
+			fmt.Sprintf("%.0f%%", habit.Previous.Consistency),
+			formatDelta(habit.Current.Consistency-habit.Previous.Consistency, " pts")))
 	}
 
-	if completedHabits == 0 {
-		return "🌟 Every journey starts with a single step! Log your first habit today!"
+	// Total
+	result.WriteString("\n" + strings.Repeat("=", 52) + "\n")
+	totalChange := "new"
+	if comparison.Previous.TotalTime > 0 {
+		totalChange = formatDelta(comparison.TotalChange, "%")
 	}
+	result.WriteString(fmt.Sprintf("🎯 Total Time: %.1f vs %.1f hours %s\n",
+		comparison.Current.TotalTime, comparison.Previous.TotalTime, totalChange))
 
-	avgProgress := totalProgress / float64(completedHabits)
+	return result.String()
+}
 
+// formatPeriod formats the dates of a summary period
+func formatPeriod(summary types.WeeklySummary) string {
+	last := summary.EndDate.AddDate(0, 0, -1)
+	if last.Equal(summary.StartDate) {
+		return summary.StartDate.Format("Jan 2")
+	}
+	return fmt.Sprintf("%s - %s", summary.StartDate.Format("Jan 2"), last.Format("Jan 2"))
+}
+
+// formatValue formats the main value of a habit summary
+func formatValue(summary types.Summary, isCount bool) string {
+	if isCount {
+		return fmt.Sprintf("%dx", summary.TotalCount)
+	}
+	return fmt.Sprintf("%.1fh", summary.TotalTime)
+}
+
+// formatDelta formats a change with a direction arrow
+func formatDelta(change float64, unit string) string {
 	switch {
-	case avgProgress >= 100:
-		return "🎉 Amazing! You're crushing your goals this week!"
-	case avgProgress >= 80:
-		return "🚀 Great progress! You're so close to your goals!"
-	case avgProgress >= 60:
-		return "💪 Good work! Keep up the momentum!"
-	case avgProgress >= 40:
-		return "👍 You're making progress! Every bit counts!"
-	case avgProgress >= 20:
-		return "🌱 Getting started is the hardest part. You're doing great!"
+	case change >= 0.5:
+		return fmt.Sprintf("▲ %.0f%s", change, unit)
+	case change <= -0.5:
+		return fmt.Sprintf("▼ %.0f%s", -change, unit)
 	default:
-		return "🌟 Every small step counts! Keep going!"
+		return fmt.Sprintf("= 0%s", unit)
+	}
+}
+
+// LogValue returns the value of a log in the habit's unit (hours or count)
+func LogValue(habit types.Habit, log types.Log) float64 {
+	if habit.GoalType == "count" {
+		return float64(log.Count)
+	}
+	if log.Duration == "" {
+		return 0
+	}
+	duration, err := parser.ParseDuration(log.Duration)
+	if err != nil {
+		return 0
+	}
+	return parser.GetTotalHours(duration)
+}
+
+// CalculateDailyTotals returns one total per day between startDate and endDate
+func CalculateDailyTotals(habit types.Habit, logs []types.Log, startDate, endDate time.Time) []types.DailyTotal {
+	var totals []types.DailyTotal
+	index := make(map[string]int)
+	for day := startDate; day.Before(endDate); day = day.AddDate(0, 0, 1) {
+		index[day.Format("2006-01-02")] = len(totals)
+		totals = append(totals, types.DailyTotal{Date: day})
+	}
+
+	for _, log := range logs {
+		i, exists := index[log.LoggedAt.In(startDate.Location()).Format("2006-01-02")]
+		if !exists {
+			continue
+		}
+		totals[i].Value += LogValue(habit, log)
+		totals[i].Sessions++
 	}
+
+	return totals
+}
+
+// CalculateStreaks returns the current and longest runs of consecutive logged days.
+// The current streak is still alive if only the last day is missing a log.
+func CalculateStreaks(dailyTotals []types.DailyTotal) (int, int) {
+	var current, longest, run int
+	for _, day := range dailyTotals {
+		if day.Sessions > 0 {
+			run++
+		} else {
+			run = 0
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	current = run
+	if current == 0 && len(dailyTotals) > 1 {
+		for i := len(dailyTotals) - 2; i >= 0 && dailyTotals[i].Sessions > 0; i-- {
+			current++
+		}
+	}
+
+	return current, longest
+}
+
+// CalculateForecast projects a habit's goal for the period between startDate and endDate
+// from its pace so far
+func CalculateForecast(habit types.Habit, logs []types.Log, startDate, endDate, now time.Time) types.Forecast {
+	totalDays := parser.DaysInRange(startDate, endDate)
+	goal := PeriodGoal(habit, totalDays)
+
+	var progress float64
+	for _, log := range logs {
+		if log.LoggedAt.After(startDate) && log.LoggedAt.Before(endDate) && !log.LoggedAt.After(now) {
+			progress += LogValue(habit, log)
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	remainingDays := parser.DaysInRange(today, endDate)
+	elapsedDays := now.Sub(startDate).Hours() / 24
+
+	// Project the current pace over the whole period
+	projected := progress
+	if elapsedDays > 0 && elapsedDays < float64(totalDays) {
+		projected = progress / elapsedDays * float64(totalDays)
+	}
+
+	var requiredPerDay, neededToday float64
+	if goal > progress && remainingDays > 0 {
+		requiredPerDay = (goal - progress) / float64(remainingDays)
+	}
+	if totalDays > 0 {
+		daysThroughToday := parser.DaysInRange(startDate, today.AddDate(0, 0, 1))
+		neededToday = math.Max(0, goal*float64(daysThroughToday)/float64(totalDays)-progress)
+	}
+
+	return types.Forecast{
+		HabitName:      habit.Name,
+		Emoji:          habit.Emoji,
+		GoalType:       habit.GoalType,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		Goal:           goal,
+		Progress:       progress,
+		RemainingDays:  remainingDays,
+		ProjectedTotal: projected,
+		RequiredPerDay: requiredPerDay,
+		NeededToday:    neededToday,
+		OnTrack:        projected >= goal,
+	}
+}
+
+// FormatAmount formats a value in the habit's unit, e.g. "1h30m" or "3x"
+func FormatAmount(goalType string, value float64) string {
+	if goalType == "count" {
+		return fmt.Sprintf("%dx", int(math.Ceil(value)))
+	}
+	minutes := int(math.Ceil(value * 60))
+	return parser.FormatDuration(types.ParsedDuration{Hours: minutes / 60, Minutes: minutes % 60, IsValid: true})
+}
+
+// FormatForecast formats a forecast for display
+func FormatForecast(forecast types.Forecast) string {
+	var result strings.Builder
+
+	// Emoji, name and status
+	status := "✅ on track"
+	if !forecast.OnTrack {
+		status = "⚠️  behind"
+	}
+	result.WriteString(fmt.Sprintf("%s %s %s\n", forecast.Emoji, forecast.HabitName, status))
+
+	// Progress and projection
+	result.WriteString(fmt.Sprintf("   Progress:  %s of %s\n",
+		FormatAmount(forecast.GoalType, forecast.Progress), FormatAmount(forecast.GoalType, forecast.Goal)))
+	result.WriteString(fmt.Sprintf("   Projected: %s by %s\n",
+		FormatAmount(forecast.GoalType, forecast.ProjectedTotal), forecast.EndDate.AddDate(0, 0, -1).Format("Jan 2")))
+
+	// What's still needed
+	if forecast.RequiredPerDay > 0 && forecast.RemainingDays == 1 {
+		result.WriteString(fmt.Sprintf("   Needed:    %s today\n", FormatAmount(forecast.GoalType, forecast.RequiredPerDay)))
+	} else if forecast.RequiredPerDay > 0 {
+		result.WriteString(fmt.Sprintf("   Needed:    %s per day for the remaining %d days\n",
+			FormatAmount(forecast.GoalType, forecast.RequiredPerDay), forecast.RemainingDays))
+	} else {
+		result.WriteString("   Needed:    nothing, goal reached! 🎉\n")
+	}
+	if forecast.NeededToday > 0 {
+		result.WriteString(fmt.Sprintf("   Today:     %s more to stay on pace\n", FormatAmount(forecast.GoalType, forecast.NeededToday)))
+	}
+
+	return result.String()
 }
 
 // CalculateDailyProgress calculates progress for today
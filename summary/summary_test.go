@@ -0,0 +1,70 @@
+package summary
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/master-wayne7/lazytrack/types"
+)
+
+func TestComparePeriodsByGoalType(t *testing.T) {
+	current := types.WeeklySummary{Habits: []types.Summary{
+		// Duration logs may carry a count too, which isn't what's compared
+		{HabitName: "code", GoalType: "duration", TotalTime: 3, TotalCount: 3},
+		// A count habit logged with a duration, but no count, this period
+		{HabitName: "water", GoalType: "count", TotalTime: 0.5},
+		{HabitName: "walk", GoalType: "duration", TotalTime: 1},
+	}}
+	previous := types.WeeklySummary{Habits: []types.Summary{
+		{HabitName: "code", GoalType: "duration", TotalTime: 2, TotalCount: 1},
+		{HabitName: "water", GoalType: "count", TotalCount: 4},
+	}}
+
+	tests := map[string]struct {
+		change      float64
+		hasBaseline bool
+	}{
+		"code":  {50, true},
+		"water": {-100, true},
+		"walk":  {0, false},
+	}
+	comparison := ComparePeriods(current, previous)
+	for _, habit := range comparison.Habits {
+		want := tests[habit.HabitName]
+		if math.Abs(habit.Change-want.change) > 0.01 || habit.HasBaseline != want.hasBaseline {
+			t.Errorf("%s: got %.1f%% (baseline %v), want %.1f%% (baseline %v)",
+				habit.HabitName, habit.Change, habit.HasBaseline, want.change, want.hasBaseline)
+		}
+	}
+}
+
+func TestCalculateSummaryGoalType(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	habits := []types.Habit{
+		{ID: 1, Name: "code", GoalType: "duration", DailyGoal: 2},
+		{ID: 2, Name: "water", GoalType: "count", DailyGoal: 8},
+	}
+	logs := map[string][]types.Log{
+		"code":  {{HabitName: "code", Duration: "1h30m", Count: 1, LoggedAt: start.Add(10 * time.Hour)}},
+		"water": {{HabitName: "water", Count: 3, LoggedAt: start.Add(9 * time.Hour)}},
+	}
+
+	summary := CalculateSummary(habits, logs, start, start.AddDate(0, 0, 1))
+	want := map[string]struct {
+		goalType string
+		value    float64
+	}{
+		"code":  {"duration", 1.5},
+		"water": {"count", 3},
+	}
+	for _, habit := range summary.Habits {
+		w := want[habit.HabitName]
+		if habit.GoalType != w.goalType {
+			t.Errorf("%s: goal type %q, want %q", habit.HabitName, habit.GoalType, w.goalType)
+		}
+		if got := summaryValue(habit); got != w.value {
+			t.Errorf("%s: summaryValue = %v, want %v", habit.HabitName, got, w.value)
+		}
+	}
+}
@@ -11,7 +11,9 @@ type Habit struct {
 	Emoji       string    `json:"emoji" db:"emoji"`
 	DefaultDuration string `json:"default_duration" db:"default_duration"`
 	DailyGoal   int       `json:"daily_goal" db:"daily_goal"`
+	WeeklyGoal  int       `json:"weekly_goal,omitempty" db:"weekly_goal"` // used when there is no daily goal, e.g. 3x a week
 	GoalType    string    `json:"goal_type" db:"goal_type"` // "count" or "duration"
+	Remind      string    `json:"remind,omitempty" db:"remind"` // reminder schedule, e.g. "every 2h 09:00-21:00"
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -23,12 +25,18 @@ type Log struct {
 	Duration  string    `json:"duration" db:"duration"` // e.g., "30m", "2h"
 	Count     int       `json:"count" db:"count"`       // for count-based habits
 	LoggedAt  time.Time `json:"logged_at" db:"logged_at"`
+	StartedAt time.Time `json:"started_at,omitzero" db:"started_at"` // set when the start time is known
 	Notes     string    `json:"notes" db:"notes"`
+	Source    string    `json:"source,omitempty" db:"source"` // identifies logs created by integrations, e.g. "wakatime:<id>"
+	Distance  float64   `json:"distance,omitempty" db:"distance"` // in kilometers, for runs, walks and rides
 }
 
 // Config represents user configuration
 type Config struct {
+	NotificationsEnabled bool     `json:"notifications_enabled"`
 	SoundEnabled bool              `json:"sound_enabled"`
+	MutedHabits  []string          `json:"muted_habits,omitempty"`       // habits without notifications
+	DisabledNotifiers []string     `json:"disabled_notifiers,omitempty"` // notification backends turned off
 	DefaultHabits map[string]Habit `json:"default_habits"`
 	Theme        string            `json:"theme"` // "default", "dark", "colorful"
 }
@@ -37,10 +45,13 @@ type Config struct {
 type Summary struct {
 	HabitName    string  `json:"habit_name"`
 	Emoji        string  `json:"emoji"`
+	GoalType     string  `json:"goal_type"`     // "count" or "duration"
 	TotalTime    float64 `json:"total_time"`    // in hours
 	TotalCount   int     `json:"total_count"`
+	TotalDistance float64 `json:"total_distance,omitempty"` // in kilometers
 	GoalProgress float64 `json:"goal_progress"` // percentage
 	Streak       int     `json:"streak"`
+	Consistency  float64 `json:"consistency"`   // percentage of days with logs
 	BarChart     string  `json:"bar_chart"`
 }
 
@@ -52,6 +63,90 @@ type WeeklySummary struct {
 	TotalTime float64    `json:"total_time"`
 }
 
+// HabitComparison compares a habit's summary across two periods
+type HabitComparison struct {
+	HabitName   string  `json:"habit_name"`
+	Emoji       string  `json:"emoji"`
+	Current     Summary `json:"current"`
+	Previous    Summary `json:"previous"`
+	Change      float64 `json:"change"`       // percentage change of time or count
+	HasBaseline bool    `json:"has_baseline"` // false when the previous period is empty
+}
+
+// PeriodComparison represents two periods side by side
+type PeriodComparison struct {
+	Current     WeeklySummary     `json:"current"`
+	Previous    WeeklySummary     `json:"previous"`
+	Habits      []HabitComparison `json:"habits"`
+	TotalChange float64           `json:"total_change"` // percentage change of total time
+}
+
+// DailyTotal represents a habit's total for a single day
+type DailyTotal struct {
+	Date     time.Time `json:"date"`
+	Value    float64   `json:"value"` // hours or count
+	Sessions int       `json:"sessions"`
+}
+
+// WeekTotal represents a habit's total for a week starting on Monday
+type WeekTotal struct {
+	StartDate time.Time `json:"start_date"`
+	Value     float64   `json:"value"` // hours or count
+}
+
+// HabitStats represents statistics about a habit's logs
+type HabitStats struct {
+	HabitName      string     `json:"habit_name"`
+	Emoji          string     `json:"emoji"`
+	Unit           string     `json:"unit"` // "minutes" or "count"
+	Sessions       int        `json:"sessions"`
+	Mean           float64    `json:"mean"`
+	Median         float64    `json:"median"`
+	P25            float64    `json:"p25"`
+	P75            float64    `json:"p75"`
+	P90            float64    `json:"p90"`
+	SessionsPerDay float64    `json:"sessions_per_day"` // on days with at least one log
+	BestWeekday    string     `json:"best_weekday"`
+	WeekdayTotals  [7]float64 `json:"weekday_totals"` // Sunday first
+	HourHistogram  [24]int    `json:"hour_histogram"`
+	Completion30   float64    `json:"completion_30d"`
+	Completion90   float64    `json:"completion_90d"`
+	Completion365  float64    `json:"completion_365d"`
+	BestWeek       *WeekTotal `json:"best_week,omitempty"`
+	WorstWeek      *WeekTotal `json:"worst_week,omitempty"`
+}
+
+// Insight represents a relationship between two habits
+type Insight struct {
+	Kind           string  `json:"kind"`      // "correlation" or "conditional"
+	Habit          string  `json:"habit"`     // the condition habit
+	Target         string  `json:"target"`    // the habit being compared
+	Condition      string  `json:"condition"` // "logged" or "goal" (conditional only)
+	Correlation    float64 `json:"correlation,omitempty"`
+	WithAverage    float64 `json:"with_average,omitempty"`
+	WithoutAverage float64 `json:"without_average,omitempty"`
+	Change         float64 `json:"change,omitempty"` // percentage difference
+	Samples        int     `json:"samples"`
+	Score          float64 `json:"score"` // t statistic
+	Message        string  `json:"message"`
+}
+
+// Forecast represents a projection of a habit's goal for the current period
+type Forecast struct {
+	HabitName      string    `json:"habit_name"`
+	Emoji          string    `json:"emoji"`
+	GoalType       string    `json:"goal_type"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	Goal           float64   `json:"goal"`     // hours or count for the whole period
+	Progress       float64   `json:"progress"` // hours or count so far
+	RemainingDays  int       `json:"remaining_days"` // including today
+	ProjectedTotal float64   `json:"projected_total"`
+	RequiredPerDay float64   `json:"required_per_day"` // to reach the goal by the end
+	NeededToday    float64   `json:"needed_today"`     // to stay on pace by the end of today
+	OnTrack        bool      `json:"on_track"`
+}
+
 // ParsedDuration represents parsed time duration
 type ParsedDuration struct {
 	Hours   int